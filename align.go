@@ -0,0 +1,48 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nounsafe
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// makeBlocks returns n zeroed blocks, aligned to BlockAlignment bytes.
+func makeBlocks(n uint64) []block {
+	b := make([]block, n)
+	if n == 0 || uintptr(unsafe.Pointer(&b[0]))%BlockAlignment == 0 {
+		return b
+	}
+	// The allocator gave us a misaligned array.
+	return alignedBlocks(n)
+}
+
+// alignedBlocks allocates n+1 blocks' worth of memory and returns the n
+// blocks starting at the first aligned address.
+func alignedBlocks(n uint64) (b []block) {
+	mem := make([]uint32, (n+1)*blockWords)
+	addr := uintptr(unsafe.Pointer(&mem[0]))
+	skip := (BlockAlignment - addr%BlockAlignment) % BlockAlignment / 4
+
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	hdr.Data = uintptr(unsafe.Pointer(&mem[skip]))
+	hdr.Len, hdr.Cap = int(n), int(n)
+	runtime.KeepAlive(mem)
+	return b
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nounsafe
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"bytes"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assertAligned(t *testing.T, b []block) {
+	t.Helper()
+	if len(b) > 0 {
+		assert.Zero(t, uintptr(unsafe.Pointer(&b[0]))%BlockAlignment)
+	}
+}
+
+func TestAlignment(t *testing.T) {
+	t.Parallel()
+
+	for n := uint64(0); n < 40; n++ {
+		b := makeBlocks(n)
+		assert.Len(t, b, int(n))
+		assertAligned(t, b)
+
+		b = alignedBlocks(n)
+		assert.Len(t, b, int(n))
+		assertAligned(t, b)
+		for i := range b {
+			assert.Equal(t, block{}, b[i])
+		}
+	}
+
+	f := NewOptimized(Config{Capacity: 1000, FPRate: 1e-3})
+	assertAligned(t, f.b)
+	s := NewSync(1<<16, 4)
+	assertAligned(t, s.b)
+	assertAligned(t, s.Freeze().b)
+	assertAligned(t, NewStriped(1<<16, 4, 0).Freeze().b)
+
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "")
+	require.NoError(t, err)
+	l, err := NewLoader(&buf)
+	require.NoError(t, err)
+	g, err := l.Load(nil)
+	require.NoError(t, err)
+	assertAligned(t, g.b)
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nounsafe
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// An Allocator provides memory for the blocks of a Filter, for programs
+// that manage memory outside the Go garbage collector, e.g., in an arena
+// or with C's malloc.
+type Allocator interface {
+	// Alloc returns a pointer to size bytes of zeroed memory,
+	// aligned to at least eight bytes.
+	Alloc(size uintptr) unsafe.Pointer
+
+	// Free releases memory returned by Alloc.
+	Free(p unsafe.Pointer, size uintptr)
+}
+
+// NewWithAllocator is like New, but allocates the Filter's blocks with a.
+// The memory is released by calling Free on the Filter.
+//
+// The Filter must not be used after Free. Filters produced from it,
+// e.g., by Load, are allocated normally.
+//
+// NewWithAllocator is not available when the nounsafe build tag is set.
+func NewWithAllocator(nbits uint64, nhashes int, a Allocator) *Filter {
+	nbits, nhashes = fixBitsAndHashes(nbits, nhashes)
+	n := int(nbits / BlockBits)
+	size := uintptr(n) * BlockBits / 8
+
+	p := a.Alloc(size)
+	if p == nil {
+		panic("blobloom: allocator returned nil")
+	}
+	if uintptr(p)%8 != 0 {
+		panic("blobloom: allocated memory must be 8-byte aligned")
+	}
+
+	var b []block
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	hdr.Data, hdr.Len, hdr.Cap = uintptr(p), n, n
+
+	return &Filter{
+		b:    b,
+		k:    nhashes,
+		free: func() { a.Free(p, size) },
+	}
+}
+
+// NewFromBuffer constructs a Filter that uses buf as its blocks, without
+// copying. The Filter has as many blocks as fit in buf; any remaining
+// bytes are not used. The bits already set in buf are part of the filter,
+// so the memory should be zeroed for a new, empty Filter. The bit layout
+// in buf depends on the machine's byte order.
+//
+// The caller owns buf and must not release it while the Filter is in use.
+// Free on the Filter does not touch buf.
+//
+// NewFromBuffer panics if buf is shorter than BlockBits/8 bytes or not
+// aligned to eight bytes. Alignment to BlockAlignment is recommended.
+// It is not available when the nounsafe build tag is set.
+func NewFromBuffer(buf []byte, nhashes int) *Filter {
+	n := uint64(len(buf)) / (BlockBits / 8)
+	if n == 0 {
+		panic("blobloom: buffer smaller than one block")
+	}
+	if uintptr(unsafe.Pointer(&buf[0]))%8 != 0 {
+		panic("blobloom: buffer must be 8-byte aligned")
+	}
+	nbits, nhashes := fixBitsAndHashes(n*BlockBits, nhashes)
+	n = nbits / BlockBits
+
+	var b []block
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	hdr.Data, hdr.Len, hdr.Cap = uintptr(unsafe.Pointer(&buf[0])), int(n), int(n)
+
+	return &Filter{b: b, k: nhashes}
+}
+
+// WithAllocator makes NewWithOptions allocate the Filter's blocks with a,
+// as NewWithAllocator does.
+//
+// WithAllocator is not available when the nounsafe build tag is set.
+func WithAllocator(a Allocator) Option {
+	return func(o *options) {
+		o.new = func(nbits uint64, nhashes int) *Filter {
+			return NewWithAllocator(nbits, nhashes, a)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nounsafe
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A testAllocator allocates from the Go heap and tracks its allocations.
+type testAllocator struct {
+	live map[unsafe.Pointer][]uint64
+}
+
+func (a *testAllocator) Alloc(size uintptr) unsafe.Pointer {
+	mem := make([]uint64, size/8)
+	p := unsafe.Pointer(&mem[0])
+	a.live[p] = mem
+	return p
+}
+
+func (a *testAllocator) Free(p unsafe.Pointer, size uintptr) {
+	if uintptr(len(a.live[p]))*8 != size {
+		panic("wrong size or double free")
+	}
+	delete(a.live, p)
+}
+
+func TestNewWithAllocator(t *testing.T) {
+	t.Parallel()
+
+	a := &testAllocator{live: make(map[unsafe.Pointer][]uint64)}
+	f := NewWithAllocator(1<<14, 4, a)
+	g := New(1<<14, 4)
+	assert.Len(t, a.live, 1)
+	assert.True(t, f.Empty())
+
+	hashes := randomU64(1000, 0xa11c)
+	for _, h := range hashes[:500] {
+		f.Add(h)
+		g.Add(h)
+	}
+	assert.True(t, f.Equals(g))
+	f.Union(g)
+	assert.InDelta(t, 500, f.Cardinality(), 20)
+
+	f.Free()
+	assert.Empty(t, a.live)
+	f.Free()
+	assert.Panics(t, func() { f.Has(hashes[0]) })
+
+	// Free works on ordinary Filters, too.
+	g.Free()
+	assert.Panics(t, func() { g.Add(hashes[0]) })
+}
+
+func TestNewFromBuffer(t *testing.T) {
+	t.Parallel()
+
+	mem := make([]uint64, 2*BlockBits/64+1)
+	buf := (*[1 << 20]byte)(unsafe.Pointer(&mem[0]))[:8*len(mem)]
+
+	f := NewFromBuffer(buf, 5)
+	g := New(2*BlockBits, 5)
+	assert.EqualValues(t, 2*BlockBits, f.NumBits())
+	assert.True(t, f.Empty())
+
+	hashes := randomU64(100, 0xb0f)
+	for _, h := range hashes {
+		f.Add(h)
+		g.Add(h)
+	}
+	assert.True(t, f.Equals(g))
+	assert.NotEqual(t, make([]byte, 8), buf[:8], "filter not in buf")
+	assert.Equal(t, make([]byte, 8), buf[2*BlockBits/8:], "wrote past blocks")
+
+	// The contents of buf are retained.
+	f = NewFromBuffer(buf, 5)
+	assert.True(t, f.Equals(g))
+
+	f.Free()
+	assert.NotEqual(t, make([]byte, 8), buf[:8], "Free cleared buf")
+
+	assert.Panics(t, func() { NewFromBuffer(buf[:BlockBits/8-1], 2) })
+	assert.Panics(t, func() { NewFromBuffer(buf[1:], 2) })
+}
+
+func TestWithAllocator(t *testing.T) {
+	t.Parallel()
+
+	a := &testAllocator{live: make(map[unsafe.Pointer][]uint64)}
+	f := NewWithOptions(WithAllocator(a), WithBits(1<<14), WithPremix(true))
+	assert.Len(t, a.live, 1)
+	assert.EqualValues(t, 1<<14, f.NumBits())
+	assert.True(t, f.premix)
+
+	f.Free()
+	assert.Empty(t, a.live)
+}
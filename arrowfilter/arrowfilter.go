@@ -0,0 +1,96 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package arrowfilter adds columns of Apache Arrow hash values to Bloom
+// filters and probes filters with them, without copying the columns.
+//
+// It does not depend on the Arrow module: any array with the methods of
+// Uint64Array can be used, including Arrow's *array.Uint64.
+package arrowfilter
+
+import (
+	"encoding/binary"
+
+	"github.com/greatroar/blobloom"
+)
+
+// Uint64Array is the subset of the methods of an Arrow *array.Uint64
+// that this package uses.
+type Uint64Array interface {
+	Len() int
+	NullN() int
+	IsNull(i int) bool
+
+	// Uint64Values returns the values of the array, of length Len().
+	Uint64Values() []uint64
+}
+
+// AddMany adds the hash values in a to f. Null elements are skipped.
+func AddMany(f *blobloom.Filter, a Uint64Array) {
+	values := a.Uint64Values()
+	if a.NullN() == 0 {
+		f.AddBatch(values)
+		return
+	}
+	for i, h := range values {
+		if !a.IsNull(i) {
+			f.Add(h)
+		}
+	}
+}
+
+// HasMany probes f for the hash values in a. It returns a bitmap in the
+// layout of an Arrow boolean array's values buffer, with bit i set if
+// f has a.Uint64Values()[i]. Bits for null elements are clear.
+//
+// The bitmap is written to dst, which is grown if it is shorter than
+// (a.Len()+7)/8 bytes. With Arrow, the result for an array a without
+// nulls can be wrapped as
+//
+//	array.NewBoolean(a.Len(), memory.NewBufferBytes(bitmap), nil, 0)
+func HasMany(f *blobloom.Filter, a Uint64Array, dst []byte) []byte {
+	values := a.Uint64Values()
+	n := len(values)
+
+	nbytes := (n + 7) / 8
+	if cap(dst) < nbytes {
+		dst = make([]byte, nbytes)
+	}
+	dst = dst[:nbytes]
+
+	var (
+		chunk [1024 / 64]uint64
+		word  [8]byte
+	)
+	for off := 0; off < n; off += 1024 {
+		end := off + 1024
+		if end > n {
+			end = n
+		}
+		found := f.HasBatchBits(values[off:end], chunk[:0])
+		for i, w := range found {
+			binary.LittleEndian.PutUint64(word[:], w)
+			copy(dst[off/8+8*i:], word[:])
+		}
+	}
+
+	if a.NullN() > 0 {
+		for i := 0; i < n; i++ {
+			if a.IsNull(i) {
+				dst[i/8] &^= 1 << (i % 8)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowfilter_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/blobloom"
+	"github.com/greatroar/blobloom/arrowfilter"
+	"github.com/stretchr/testify/assert"
+)
+
+// A uint64Array mimics Arrow's *array.Uint64.
+type uint64Array struct {
+	values []uint64
+	nulls  map[int]bool
+}
+
+func (a *uint64Array) Len() int               { return len(a.values) }
+func (a *uint64Array) NullN() int             { return len(a.nulls) }
+func (a *uint64Array) IsNull(i int) bool      { return a.nulls[i] }
+func (a *uint64Array) Uint64Values() []uint64 { return a.values }
+
+func TestAddHasMany(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(0xa770))
+	values := make([]uint64, 3000)
+	for i := range values {
+		values[i] = r.Uint64()
+	}
+
+	for _, withNulls := range []bool{false, true} {
+		a := &uint64Array{values: values[:1500], nulls: map[int]bool{}}
+		if withNulls {
+			for i := 0; i < len(a.values); i += 7 {
+				a.nulls[i] = true
+			}
+		}
+
+		f := blobloom.New(1<<16, 5)
+		arrowfilter.AddMany(f, a)
+		for i, h := range a.values {
+			if !a.nulls[i] {
+				assert.True(t, f.Has(h))
+			}
+		}
+
+		for _, n := range []int{0, 1, 1023, 1025, 3000} {
+			probe := &uint64Array{values: values[:n], nulls: a.nulls}
+			bitmap := arrowfilter.HasMany(f, probe, []byte{0xff})
+			assert.Len(t, bitmap, (n+7)/8)
+			for i, h := range probe.values {
+				want := f.Has(h) && !probe.nulls[i]
+				assert.Equal(t, want, bitmap[i/8]&(1<<(i%8)) != 0, "element %d", i)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package atomicfilter provides atomic publication of Bloom filters,
+// for services that rebuild a filter in the background and swap it in
+// without blocking lookups.
+package atomicfilter
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/greatroar/blobloom"
+)
+
+// A Value holds a *blobloom.Filter that can be loaded and replaced
+// atomically. The zero Value holds nil and is ready to use.
+//
+// A Filter must not be modified after it has been stored in a Value,
+// since readers may be using it concurrently.
+//
+// A Value must not be copied after first use.
+type Value struct {
+	mu sync.Mutex   // Serializes Store and Swap.
+	v  atomic.Value // Holds a *blobloom.Filter, possibly nil.
+}
+
+// Load returns the Filter in v, or nil.
+func (v *Value) Load() *blobloom.Filter {
+	f, _ := v.v.Load().(*blobloom.Filter)
+	return f
+}
+
+// Store sets the Filter in v to f, which may be nil.
+func (v *Value) Store(f *blobloom.Filter) {
+	v.mu.Lock()
+	v.v.Store(f)
+	v.mu.Unlock()
+}
+
+// Swap sets the Filter in v to f and returns the previous Filter.
+func (v *Value) Swap(f *blobloom.Filter) (old *blobloom.Filter) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	old = v.Load()
+	v.v.Store(f)
+	return old
+}
+
+// Has reports whether the Filter in v has a key with hash value h.
+// It returns false if v holds nil.
+func (v *Value) Has(h uint64) bool {
+	f := v.Load()
+	return f != nil && f.Has(h)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomicfilter_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/greatroar/blobloom"
+	"github.com/greatroar/blobloom/atomicfilter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	var v atomicfilter.Value
+	assert.Nil(t, v.Load())
+	assert.False(t, v.Has(1))
+
+	f := blobloom.NewOptimized(blobloom.Config{Capacity: 10, FPRate: 1e-6})
+	f.Add(1)
+	v.Store(f)
+	assert.Equal(t, f, v.Load())
+	assert.True(t, v.Has(1))
+
+	assert.Equal(t, f, v.Swap(nil))
+	assert.Nil(t, v.Load())
+	assert.False(t, v.Has(1))
+	assert.Nil(t, v.Swap(f))
+}
+
+func TestValueConcurrent(t *testing.T) {
+	t.Parallel()
+
+	config := blobloom.Config{Capacity: 100, FPRate: 1e-6}
+	filters := make([]*blobloom.Filter, 10)
+	for i := range filters {
+		filters[i] = blobloom.NewOptimized(config)
+		filters[i].Add(42)
+	}
+
+	var (
+		v  atomicfilter.Value
+		wg sync.WaitGroup
+	)
+	v.Store(filters[0])
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				assert.True(t, v.Has(42))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for _, f := range filters {
+				assert.NotNil(t, v.Swap(f))
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomicfilter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/greatroar/blobloom"
+)
+
+// ErrRebuilding is returned by Live.RebuildFrom when another rebuild
+// is in progress.
+var ErrRebuilding = errors.New("atomicfilter: rebuild already in progress")
+
+// A Live holds a SyncFilter that serves Add and Has and that can be
+// rebuilt from an authoritative source of keys without interrupting either.
+//
+// Since keys cannot be deleted from a Bloom filter, services that delete
+// keys usually mark them as deleted in their database and periodically
+// rebuild the filter from the keys that remain. RebuildFrom does this.
+type Live struct {
+	added uint64 // Keys added to the filter being built. Accessed atomically.
+
+	mu         sync.RWMutex
+	cur        *blobloom.SyncFilter
+	next       *blobloom.SyncFilter // Filter being built, or nil.
+	rebuilding bool                 // Whether RebuildFrom is running.
+}
+
+// NewLive returns a Live that serves f. It panics if f is nil.
+func NewLive(f *blobloom.SyncFilter) *Live {
+	if f == nil {
+		panic("atomicfilter: nil filter")
+	}
+	return &Live{cur: f}
+}
+
+// Add inserts a key with hash value h into the filter, and also into the
+// filter being rebuilt, if any, so that the key survives the swap.
+func (l *Live) Add(h uint64) {
+	l.mu.RLock()
+	l.cur.Add(h)
+	if l.next != nil {
+		l.next.Add(h)
+	}
+	l.mu.RUnlock()
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+func (l *Live) Has(h uint64) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.cur.Has(h)
+}
+
+// Filter returns the SyncFilter currently being served.
+func (l *Live) Filter() *blobloom.SyncFilter {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.cur
+}
+
+// RebuildFrom builds a new filter for config from the hash values produced
+// by keys, while the current filter keeps serving. Concurrent calls to Add
+// go to both filters. When keys is exhausted, the new filter replaces the
+// current one.
+//
+// The keys function has the signature of an iter.Seq[uint64], which can be
+// passed directly. RebuildFrom is meant to be run in its own goroutine. If
+// ctx is done before keys is exhausted, RebuildFrom stops iterating, drops
+// the new filter and returns ctx.Err(). It returns ErrRebuilding if another
+// rebuild is in progress.
+func (l *Live) RebuildFrom(ctx context.Context, keys func(yield func(uint64) bool), config blobloom.Config) error {
+	l.mu.Lock()
+	if l.rebuilding {
+		l.mu.Unlock()
+		return ErrRebuilding
+	}
+	l.rebuilding = true
+	l.mu.Unlock()
+
+	// Runs also when keys panics, so that the next rebuild can start.
+	defer func() {
+		l.mu.Lock()
+		l.next, l.rebuilding = nil, false
+		l.mu.Unlock()
+	}()
+
+	// Allocate outside the lock, which Add and Has need.
+	next := blobloom.NewSyncOptimized(config)
+	l.mu.Lock()
+	l.next = next
+	atomic.StoreUint64(&l.added, 0)
+	l.mu.Unlock()
+
+	done := ctx.Done()
+	keys(func(h uint64) bool {
+		next.Add(h)
+		atomic.AddUint64(&l.added, 1)
+		select {
+		case <-done:
+			return false
+		default:
+			return true
+		}
+	})
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.cur, l.next = next, nil
+	l.mu.Unlock()
+	return nil
+}
+
+// Progress reports whether a rebuild is in progress and the number of keys
+// that the current or last rebuild has taken from its source.
+func (l *Live) Progress() (rebuilding bool, keys uint64) {
+	l.mu.RLock()
+	rebuilding = l.rebuilding
+	l.mu.RUnlock()
+	return rebuilding, atomic.LoadUint64(&l.added)
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomicfilter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greatroar/blobloom"
+	"github.com/greatroar/blobloom/atomicfilter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveRebuildFrom(t *testing.T) {
+	t.Parallel()
+
+	config := blobloom.Config{Capacity: 1000, FPRate: 1e-6, Premix: true}
+	l := atomicfilter.NewLive(blobloom.NewSyncOptimized(config))
+	for h := uint64(0); h < 1000; h++ {
+		l.Add(h)
+	}
+
+	// Soft-delete the odd keys. While rebuilding, add new keys.
+	started, resume := make(chan struct{}), make(chan struct{})
+	keys := func(yield func(uint64) bool) {
+		for h := uint64(0); h < 1000; h += 2 {
+			if h == 500 {
+				close(started)
+				<-resume
+			}
+			if !yield(h) {
+				return
+			}
+		}
+	}
+	errc := make(chan error)
+	go func() { errc <- l.RebuildFrom(context.Background(), keys, config) }()
+
+	<-started
+	rebuilding, n := l.Progress()
+	assert.True(t, rebuilding)
+	assert.Equal(t, uint64(250), n)
+	assert.Equal(t, atomicfilter.ErrRebuilding, l.RebuildFrom(context.Background(), keys, config))
+	assert.True(t, l.Has(1)) // Old filter still serving.
+	l.Add(5000)
+	close(resume)
+	require.NoError(t, <-errc)
+
+	rebuilding, n = l.Progress()
+	assert.False(t, rebuilding)
+	assert.Equal(t, uint64(500), n)
+	for h := uint64(0); h < 1000; h++ {
+		assert.Equal(t, h%2 == 0, l.Has(h))
+	}
+	assert.True(t, l.Has(5000))
+	assert.True(t, l.Filter().Has(5000))
+}
+
+func TestLiveRebuildCancel(t *testing.T) {
+	t.Parallel()
+
+	config := blobloom.Config{Capacity: 100, FPRate: 1e-6}
+	old := blobloom.NewSyncOptimized(config)
+	l := atomicfilter.NewLive(old)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	yielded := 0
+	err := l.RebuildFrom(ctx, func(yield func(uint64) bool) {
+		for h := uint64(0); ; h++ {
+			if h == 10 {
+				cancel()
+			}
+			yielded++
+			if !yield(h) {
+				return
+			}
+		}
+	}, config)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 11, yielded)
+	assert.Same(t, old, l.Filter())
+
+	rebuilding, _ := l.Progress()
+	assert.False(t, rebuilding)
+	assert.Panics(t, func() { atomicfilter.NewLive(nil) })
+}
+
+func TestLiveRebuildPanic(t *testing.T) {
+	t.Parallel()
+
+	config := blobloom.Config{Capacity: 100, FPRate: 1e-6}
+	old := blobloom.NewSyncOptimized(config)
+	l := atomicfilter.NewLive(old)
+
+	assert.Panics(t, func() {
+		_ = l.RebuildFrom(context.Background(), func(yield func(uint64) bool) {
+			yield(1)
+			panic("database gone")
+		}, config)
+	})
+	rebuilding, _ := l.Progress()
+	assert.False(t, rebuilding)
+	assert.Same(t, old, l.Filter())
+
+	err := l.RebuildFrom(context.Background(), func(yield func(uint64) bool) {
+		yield(2)
+	}, config)
+	require.NoError(t, err)
+	assert.True(t, l.Has(2))
+}
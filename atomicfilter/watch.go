@@ -0,0 +1,234 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomicfilter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/greatroar/blobloom"
+)
+
+// A Source loads a filter when it has changed.
+type Source interface {
+	// Fetch returns the filter if it has changed since the last successful
+	// Fetch, or nil if it has not. The first Fetch always returns a filter.
+	Fetch(ctx context.Context) (*blobloom.Filter, error)
+}
+
+// FileSource returns a Source that loads the dump in the file at path.
+// A change is detected when the file's size or modification time changes.
+//
+// Files should be replaced atomically, by renaming a new file over the
+// old one, so that Fetch does not see partially written dumps.
+func FileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+type fileSource struct {
+	path string
+	mu   sync.Mutex
+	size int64
+	mod  time.Time
+}
+
+func (s *fileSource) Fetch(ctx context.Context) (*blobloom.Filter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	switch {
+	case err != nil:
+		return nil, err
+	case info.Size() == s.size && info.ModTime().Equal(s.mod):
+		return nil, nil
+	}
+
+	l, err := blobloom.NewLoader(file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := l.Load(nil)
+	if err != nil {
+		return nil, err
+	}
+	s.size, s.mod = info.Size(), info.ModTime()
+	return f, nil
+}
+
+// HTTPSource returns a Source that downloads a dump from url using client,
+// or http.DefaultClient if client is nil. Changes are detected by the
+// server, through the ETag and If-None-Match headers.
+func HTTPSource(client *http.Client, url string) Source {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpSource{client: client, url: url}
+}
+
+type httpSource struct {
+	client *http.Client
+	url    string
+	mu     sync.Mutex
+	etag   string
+}
+
+func (s *httpSource) Fetch(ctx context.Context) (*blobloom.Filter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("atomicfilter: GET %s: %s", s.url, resp.Status)
+	}
+
+	l, err := blobloom.NewLoader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	f, err := l.Load(nil)
+	if err != nil {
+		return nil, err
+	}
+	s.etag = resp.Header.Get("ETag")
+	return f, nil
+}
+
+// WatcherOptions holds optional settings for a Watcher.
+type WatcherOptions struct {
+	// Trigger the "contains filtered or unexported fields" message for
+	// forward compatibility and force the caller to use named fields.
+	_ struct{}
+
+	// Interval between polls of the Source. Zero means one minute.
+	// A negative Interval disables polling, so that only Events
+	// trigger reloads.
+	Interval time.Duration
+
+	// Each receive from Events triggers a reload. This can be used to
+	// connect a file system notification mechanism, such as fsnotify.
+	// Closing Events stops event-triggered reloads.
+	Events <-chan struct{}
+
+	// If Validate is not nil, it is called on each newly fetched filter.
+	// The filter is only swapped in if Validate returns nil.
+	Validate func(*blobloom.Filter) error
+
+	// OnReload, if not nil, is called after a new filter has been swapped in.
+	OnReload func(*blobloom.Filter)
+
+	// OnError, if not nil, is called when fetching or validating fails.
+	OnError func(error)
+
+	// Clock for polling. Defaults to blobloom.SystemClock.
+	Clock blobloom.Clock
+}
+
+// A Watcher reloads a filter from a Source and stores it in a Value
+// whenever it changes.
+type Watcher struct {
+	v    *Value
+	src  Source
+	opts WatcherOptions
+}
+
+// NewWatcher returns a Watcher that keeps v up to date with src.
+func NewWatcher(v *Value, src Source, opts WatcherOptions) *Watcher {
+	if opts.Interval == 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.Clock == nil {
+		opts.Clock = blobloom.SystemClock
+	}
+	return &Watcher{v: v, src: src, opts: opts}
+}
+
+// Check fetches the filter from the Source and, if it has changed and
+// is valid, stores it in the Value. It reports whether it did so.
+func (w *Watcher) Check(ctx context.Context) (bool, error) {
+	f, err := w.src.Fetch(ctx)
+	if err == nil && f != nil && w.opts.Validate != nil {
+		err = w.opts.Validate(f)
+	}
+	if err != nil {
+		if w.opts.OnError != nil {
+			w.opts.OnError(err)
+		}
+		return false, err
+	}
+	if f == nil {
+		return false, nil
+	}
+
+	w.v.Store(f)
+	if w.opts.OnReload != nil {
+		w.opts.OnReload(f)
+	}
+	return true, nil
+}
+
+// Run calls Check immediately, then at every Interval and on every event,
+// until ctx is done.
+func (w *Watcher) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if w.opts.Interval > 0 {
+		t := w.opts.Clock.NewTicker(w.opts.Interval)
+		defer t.Stop()
+		tick = t.C()
+	}
+
+	events := w.opts.Events
+	for {
+		w.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+			}
+		}
+	}
+}
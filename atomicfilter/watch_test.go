@@ -0,0 +1,168 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomicfilter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/greatroar/blobloom"
+	"github.com/greatroar/blobloom/atomicfilter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dump(t *testing.T, hashes ...uint64) []byte {
+	f := blobloom.NewOptimized(blobloom.Config{Capacity: 100, FPRate: 1e-6})
+	for _, h := range hashes {
+		f.Add(h)
+	}
+	var buf bytes.Buffer
+	_, err := blobloom.Dump(&buf, f, "")
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestFileSource(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "atomicfilter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "filter")
+
+	ctx := context.Background()
+	src := atomicfilter.FileSource(path)
+	_, err = src.Fetch(ctx)
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, dump(t, 1), 0666))
+	f, err := src.Fetch(ctx)
+	require.NoError(t, err)
+	assert.True(t, f.Has(1))
+
+	f, err = src.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	require.NoError(t, ioutil.WriteFile(path, dump(t, 2), 0666))
+	mod := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(path, mod, mod))
+	f, err = src.Fetch(ctx)
+	require.NoError(t, err)
+	assert.True(t, f.Has(2))
+}
+
+func TestHTTPSource(t *testing.T) {
+	t.Parallel()
+
+	content, etag := dump(t, 1), `"v1"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	src := atomicfilter.HTTPSource(nil, srv.URL)
+	f, err := src.Fetch(ctx)
+	require.NoError(t, err)
+	assert.True(t, f.Has(1))
+
+	f, err = src.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	content, etag = dump(t, 2), `"v2"`
+	f, err = src.Fetch(ctx)
+	require.NoError(t, err)
+	assert.True(t, f.Has(2))
+
+	_, err = atomicfilter.HTTPSource(nil, srv.URL+"/%zz").Fetch(ctx)
+	assert.Error(t, err)
+}
+
+// A fakeSource returns the filters sent on it.
+type fakeSource chan *blobloom.Filter
+
+func (s fakeSource) Fetch(ctx context.Context) (*blobloom.Filter, error) {
+	select {
+	case f := <-s:
+		return f, nil
+	default:
+		return nil, nil
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	config := blobloom.Config{Capacity: 100, FPRate: 1e-6}
+	good, bad := blobloom.NewOptimized(config), blobloom.NewOptimized(config)
+	good.Add(1)
+	errBad := errors.New("bad filter")
+
+	var (
+		v        atomicfilter.Value
+		src      = make(fakeSource, 1)
+		events   = make(chan struct{})
+		reloaded = make(chan *blobloom.Filter, 1)
+		failed   = make(chan error, 1)
+	)
+	w := atomicfilter.NewWatcher(&v, src, atomicfilter.WatcherOptions{
+		Interval: -1,
+		Events:   events,
+		Validate: func(f *blobloom.Filter) error {
+			if f == bad {
+				return errBad
+			}
+			return nil
+		},
+		OnReload: func(f *blobloom.Filter) { reloaded <- f },
+		OnError:  func(err error) { failed <- err },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	src <- good
+	events <- struct{}{}
+	assert.Equal(t, good, <-reloaded)
+	assert.True(t, v.Has(1))
+
+	src <- bad
+	events <- struct{}{}
+	assert.Equal(t, errBad, <-failed)
+	assert.Equal(t, good, v.Load())
+
+	cancel()
+	<-done
+}
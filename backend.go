@@ -0,0 +1,217 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "sync/atomic"
+
+// A Backend stores the blocks of a BackendFilter, which supplies the
+// hashing and probing logic. This allows a filter's bits to live outside
+// the Go heap, e.g., in shared memory or in a networked key-value store.
+//
+// Blocks are numbered from zero, each has BlockBits/32 words, and bits
+// are numbered from the least significant one in each word, as in the
+// blocks of a Filter. Errors returned by a Backend are passed to
+// the caller of the BackendFilter method.
+//
+// A Backend must be safe for concurrent use if its BackendFilter is.
+type Backend interface {
+	// Len returns the number of blocks. It must be positive and constant.
+	Len() uint64
+
+	// LoadBlock stores the words of block i in dst,
+	// which has length BlockBits/32.
+	LoadBlock(i uint64, dst []uint32) error
+
+	// CompareAndSwapWord sets word j of block i to new if its value is old,
+	// and reports whether it did so.
+	CompareAndSwapWord(i uint64, j int, old, new uint32) (bool, error)
+}
+
+// A BackendFilter is a Bloom filter whose blocks are stored in a Backend.
+// It maps hash values to bits in the same way as a Filter with the same
+// number of blocks, hashes, premixing and Layout.
+//
+// A BackendFilter is safe for concurrent use if its Backend is.
+type BackendFilter struct {
+	be     Backend
+	mem    []block // Blocks of be if it is a *MemoryBackend, for a fast path.
+	n      uint64  // Cached be.Len().
+	k      int
+	premix bool
+	layout Layout
+}
+
+// NewBackendFilter constructs a BackendFilter on be. The numbers of hashes,
+// premixing and Layout are configured by opts, as for NewWithOptions;
+// the number of bits is determined by be and options that set it are
+// ignored.
+//
+// NewBackendFilter panics if be has no blocks or if the options specify
+// an unknown Layout.
+func NewBackendFilter(be Backend, opts ...Option) *BackendFilter {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.layout.check()
+	_, nhashes := fixBitsAndHashes(0, o.nhashes)
+
+	n := be.Len()
+	if n == 0 {
+		panic("blobloom: backend has no blocks")
+	}
+	if n > MaxBits/BlockBits {
+		panic("nbits exceeds MaxBits")
+	}
+	f := &BackendFilter{be: be, n: n, k: nhashes, premix: o.premix, layout: o.layout}
+	if m, ok := be.(*MemoryBackend); ok {
+		f.mem = m.b
+	}
+	return f
+}
+
+// probe returns the index of the block for h and the bits in it that
+// are probed for h.
+func (f *BackendFilter) probe(h uint64) (i uint64, bits block) {
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		bits.setbit(bit)
+		return true
+	})
+	return reducerange(blk, f.n), bits
+}
+
+// Add inserts a key with hash value h into f.
+func (f *BackendFilter) Add(h uint64) error {
+	if f.mem != nil {
+		// Same as SyncFilter.Add. Calling that would cost an extra call.
+		if f.premix {
+			h = mix64(h)
+		}
+		blk, h1, h2 := f.layout.split(h)
+		b := getblock(f.mem, blk)
+		probe(h1, h2, f.k, func(bit uint32) bool {
+			setbitAtomic(b, bit)
+			return true
+		})
+		return nil
+	}
+
+	i, bits := f.probe(h)
+	var b block
+
+	if err := f.be.LoadBlock(i, b[:]); err != nil {
+		return err
+	}
+	for j := range bits {
+		for b[j]&bits[j] != bits[j] {
+			ok, err := f.be.CompareAndSwapWord(i, j, b[j], b[j]|bits[j])
+			if err != nil {
+				return err
+			}
+			if ok {
+				break
+			}
+			// Lost a race. Reload and retry.
+			if err := f.be.LoadBlock(i, b[:]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+func (f *BackendFilter) Has(h uint64) (bool, error) {
+	if f.mem != nil {
+		// Same as SyncFilter.Has.
+		if f.premix {
+			h = mix64(h)
+		}
+		blk, h1, h2 := f.layout.split(h)
+		b := getblock(f.mem, blk)
+		return probe(h1, h2, f.k, func(bit uint32) bool {
+			return getbitAtomic(b, bit)
+		}), nil
+	}
+
+	i, bits := f.probe(h)
+	var b block
+
+	if err := f.be.LoadBlock(i, b[:]); err != nil {
+		return false, err
+	}
+	for j := range bits {
+		if b[j]&bits[j] != bits[j] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Freeze returns a copy of f's blocks as a Filter,
+// e.g., for use with Dump.
+func (f *BackendFilter) Freeze() (*Filter, error) {
+	b := makeBlocks(f.n)
+	for i := range b {
+		if err := f.be.LoadBlock(uint64(i), b[i][:]); err != nil {
+			return nil, err
+		}
+	}
+	return &Filter{b: b, k: f.k, premix: f.premix, layout: f.layout}, nil
+}
+
+// K returns the number of hash functions of f.
+func (f *BackendFilter) K() int { return f.k }
+
+// NumBits returns the number of bits of f.
+func (f *BackendFilter) NumBits() uint64 { return BlockBits * f.n }
+
+// A MemoryBackend is a Backend that stores blocks in memory.
+// It is safe for concurrent use.
+//
+// A BackendFilter recognizes a MemoryBackend and accesses its blocks
+// directly, at the speed of a SyncFilter.
+type MemoryBackend struct {
+	b []block
+}
+
+// NewMemoryBackend returns a MemoryBackend with the given number of bits,
+// which is adjusted as New adjusts it.
+func NewMemoryBackend(nbits uint64) *MemoryBackend {
+	nbits, _ = fixBitsAndHashes(nbits, 0)
+	return &MemoryBackend{b: makeBlocks(nbits / BlockBits)}
+}
+
+// Len returns the number of blocks in m.
+func (m *MemoryBackend) Len() uint64 { return uint64(len(m.b)) }
+
+// LoadBlock stores the words of block i in dst.
+func (m *MemoryBackend) LoadBlock(i uint64, dst []uint32) error {
+	b := &m.b[i]
+	for j := range b {
+		dst[j] = atomic.LoadUint32(&b[j])
+	}
+	return nil
+}
+
+// CompareAndSwapWord sets word j of block i to new if its value is old.
+func (m *MemoryBackend) CompareAndSwapWord(i uint64, j int, old, new uint32) (bool, error) {
+	return atomic.CompareAndSwapUint32(&m.b[i][j], old, new), nil
+}
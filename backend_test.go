@@ -0,0 +1,221 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendFilter(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(2000, 0xbac4e)
+
+	for _, layout := range []Layout{LayoutV0, LayoutV1} {
+		for _, premix := range []bool{false, true} {
+			config := Config{Capacity: 1000, FPRate: 1e-3, Premix: premix, Layout: layout}
+			nbits, _ := Optimize(config)
+
+			f := NewBackendFilter(NewMemoryBackend(nbits), WithConfig(config))
+			ref := NewOptimized(config)
+			assert.Equal(t, ref.K(), f.K())
+			assert.Equal(t, ref.NumBits(), f.NumBits())
+
+			for _, h := range hashes[:1000] {
+				require.NoError(t, f.Add(h))
+				ref.Add(h)
+			}
+			for _, h := range hashes {
+				found, err := f.Has(h)
+				require.NoError(t, err)
+				assert.Equal(t, ref.Has(h), found)
+			}
+
+			frozen, err := f.Freeze()
+			require.NoError(t, err)
+			assert.True(t, ref.Equals(frozen))
+		}
+	}
+}
+
+func TestBackendFilterConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const nworkers = 4
+	hashes := randomU64(4000, 0xc0bac)
+
+	f := NewBackendFilter(NewMemoryBackend(BlockBits), WithHashes(7))
+	ref := New(BlockBits, 7)
+
+	var wg sync.WaitGroup
+	for w := 0; w < nworkers; w++ {
+		wg.Add(1)
+		go func(hashes []uint64) {
+			defer wg.Done()
+			for _, h := range hashes {
+				f.Add(h)
+			}
+		}(hashes[w*len(hashes)/nworkers : (w+1)*len(hashes)/nworkers])
+	}
+	for _, h := range hashes {
+		ref.Add(h)
+	}
+	wg.Wait()
+
+	frozen, err := f.Freeze()
+	require.NoError(t, err)
+	assert.True(t, ref.Equals(frozen))
+}
+
+// A genericBackend hides the type of a MemoryBackend from BackendFilter,
+// to test the code path for other Backends.
+type genericBackend struct{ Backend }
+
+func TestBackendFilterGeneric(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(2000, 0x9e4e71c)
+	config := Config{Capacity: 1000, FPRate: 1e-3, Premix: true}
+	nbits, _ := Optimize(config)
+
+	f := NewBackendFilter(genericBackend{NewMemoryBackend(nbits)}, WithConfig(config))
+	g := NewBackendFilter(NewMemoryBackend(nbits), WithConfig(config))
+	assert.Nil(t, f.mem)
+	assert.NotNil(t, g.mem)
+
+	for _, h := range hashes[:1000] {
+		require.NoError(t, f.Add(h))
+		require.NoError(t, g.Add(h))
+	}
+	for _, h := range hashes {
+		x, err := f.Has(h)
+		require.NoError(t, err)
+		y, err := g.Has(h)
+		require.NoError(t, err)
+		assert.Equal(t, y, x)
+	}
+
+	ff, err := f.Freeze()
+	require.NoError(t, err)
+	gf, err := g.Freeze()
+	require.NoError(t, err)
+	assert.True(t, ff.Equals(gf))
+}
+
+type failingBackend struct {
+	*MemoryBackend
+	err error
+}
+
+func (b *failingBackend) CompareAndSwapWord(i uint64, j int, old, new uint32) (bool, error) {
+	return false, b.err
+}
+
+func TestBackendFilterError(t *testing.T) {
+	t.Parallel()
+
+	be := &failingBackend{NewMemoryBackend(1 << 12), errors.New("unreachable")}
+	f := NewBackendFilter(be)
+
+	assert.Equal(t, be.err, f.Add(1))
+	found, err := f.Has(1)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.Panics(t, func() { NewBackendFilter(&MemoryBackend{}) })
+}
+
+// The Backend benchmarks compare a BackendFilter on a MemoryBackend to
+// a SyncFilter, which it should be as fast as (within benchmark noise):
+//
+//	go test -run=^$ -bench='Backend(Add|Has)' -count=10 | benchstat -col /impl -
+//
+// The generic case shows the cost of a Backend that is not special-cased.
+const (
+	benchBackendBits   = 1 << 23 // 1MiB.
+	benchBackendHashes = 7
+)
+
+func BenchmarkBackendAdd(b *testing.B) {
+	hashes := randomU64(1<<12, 0xbe4c)
+	mask := len(hashes) - 1
+
+	b.Run("impl=sync", func(b *testing.B) {
+		f := NewSync(benchBackendBits, benchBackendHashes)
+		for i := 0; i < b.N; i++ {
+			f.Add(hashes[i&mask])
+		}
+	})
+	b.Run("impl=memory", func(b *testing.B) {
+		f := NewBackendFilter(NewMemoryBackend(benchBackendBits),
+			WithHashes(benchBackendHashes))
+		for i := 0; i < b.N; i++ {
+			f.Add(hashes[i&mask])
+		}
+	})
+	b.Run("impl=generic", func(b *testing.B) {
+		f := NewBackendFilter(genericBackend{NewMemoryBackend(benchBackendBits)},
+			WithHashes(benchBackendHashes))
+		for i := 0; i < b.N; i++ {
+			f.Add(hashes[i&mask])
+		}
+	})
+}
+
+func BenchmarkBackendHas(b *testing.B) {
+	hashes := randomU64(1<<12, 0xbe4c)
+	mask := len(hashes) - 1
+
+	b.Run("impl=sync", func(b *testing.B) {
+		f := NewSync(benchBackendBits, benchBackendHashes)
+		for _, h := range hashes[:len(hashes)/2] {
+			f.Add(h)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			f.Has(hashes[i&mask])
+		}
+	})
+	b.Run("impl=memory", func(b *testing.B) {
+		f := NewBackendFilter(NewMemoryBackend(benchBackendBits),
+			WithHashes(benchBackendHashes))
+		for _, h := range hashes[:len(hashes)/2] {
+			f.Add(h)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			f.Has(hashes[i&mask])
+		}
+	})
+	b.Run("impl=generic", func(b *testing.B) {
+		f := NewBackendFilter(genericBackend{NewMemoryBackend(benchBackendBits)},
+			WithHashes(benchBackendHashes))
+		for _, h := range hashes[:len(hashes)/2] {
+			f.Add(h)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			f.Has(hashes[i&mask])
+		}
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Number of hashes for which blocks are prefetched at a time.
+const batchSize = 16
+
+// prefetch computes the blocks for hashes, which must be at most batchSize
+// long, and loads a word from each of them. Since the loads are independent,
+// the CPU can overlap their cache misses, instead of taking them one by one
+// in the probe loop. The hashes are premixed in place if f.premix is set.
+//
+// prefetch returns the OR of the loaded words. Callers pass it to
+// runtime.KeepAlive, so that the loads are not eliminated, without
+// storing it anywhere that concurrent callers would contend for.
+func (f *Filter) prefetch(hashes []uint64, blocks *[batchSize]*block) (sink uint32) {
+	for i, h := range hashes {
+		if f.premix {
+			h = mix64(h)
+			hashes[i] = h
+		}
+		blk, _, _ := f.layout.split(h)
+		b := getblock(f.b, blk)
+		blocks[i] = b
+		sink |= b[0]
+	}
+	return sink
+}
+
+// AddBatch inserts keys with the given hash values into f.
+// It has the same effect as calling Add for each of them,
+// but can be faster for large filters that don't fit in the CPU cache.
+func (f *Filter) AddBatch(hashes []uint64) {
+	var (
+		buf    [batchSize]uint64
+		blocks [batchSize]*block
+	)
+	for len(hashes) > 0 {
+		n := copy(buf[:], hashes)
+		hashes = hashes[n:]
+		runtime.KeepAlive(f.prefetch(buf[:n], &blocks))
+
+		for i, h := range buf[:n] {
+			_, h1, h2 := f.layout.split(h)
+			b := blocks[i]
+			probe(h1, h2, f.k, func(bit uint32) bool {
+				b.setbit(bit)
+				return true
+			})
+		}
+	}
+}
+
+// HasBatch reports, for each of hashes, whether f has that hash value.
+// The results are appended to found, which is returned.
+// It is equivalent to calling Has for each hash, but can be faster
+// for large filters that don't fit in the CPU cache.
+func (f *Filter) HasBatch(hashes []uint64, found []bool) []bool {
+	var (
+		buf    [batchSize]uint64
+		blocks [batchSize]*block
+	)
+	for len(hashes) > 0 {
+		n := copy(buf[:], hashes)
+		hashes = hashes[n:]
+		runtime.KeepAlive(f.prefetch(buf[:n], &blocks))
+
+		for i, h := range buf[:n] {
+			_, h1, h2 := f.layout.split(h)
+			found = append(found, probe(h1, h2, f.k, blocks[i].getbit))
+		}
+	}
+	return found
+}
+
+// HasBatchBits is like HasBatch, but stores its results as a bitset,
+// with bit i%64 of found[i/64] set if f has hashes[i]. This takes an eighth
+// of the memory of HasBatch's results.
+//
+// The results overwrite found, which is grown if it is too short for
+// len(hashes) bits. Bits past the last result are zero.
+// The resulting slice is returned.
+func (f *Filter) HasBatchBits(hashes []uint64, found []uint64) []uint64 {
+	nwords := (len(hashes) + 63) / 64
+	if cap(found) < nwords {
+		found = make([]uint64, nwords)
+	}
+	found = found[:nwords]
+	for i := range found {
+		found[i] = 0
+	}
+
+	var (
+		buf    [batchSize]uint64
+		blocks [batchSize]*block
+	)
+	for off := 0; off < len(hashes); off += batchSize {
+		n := copy(buf[:], hashes[off:])
+		runtime.KeepAlive(f.prefetch(buf[:n], &blocks))
+
+		for i, h := range buf[:n] {
+			_, h1, h2 := f.layout.split(h)
+			if probe(h1, h2, f.k, blocks[i].getbit) {
+				k := off + i
+				found[k/64] |= 1 << (k % 64)
+			}
+		}
+	}
+	return found
+}
+
+// prefetch is like Filter.prefetch, but uses atomic loads.
+func (f *SyncFilter) prefetch(hashes []uint64, blocks *[batchSize]*block) (sink uint32) {
+	for i, h := range hashes {
+		if f.premix {
+			h = mix64(h)
+			hashes[i] = h
+		}
+		blk, _, _ := f.layout.split(h)
+		b := getblock(f.b, blk)
+		blocks[i] = b
+		sink |= atomic.LoadUint32(&b[0])
+	}
+	return sink
+}
+
+// TestAndAddBatch calls TestAndAdd for each of hashes and appends the
+// results to present, which is returned. It can be faster than separate
+// calls for large filters that don't fit in the CPU cache.
+func (f *SyncFilter) TestAndAddBatch(hashes []uint64, present []bool) []bool {
+	var (
+		buf    [batchSize]uint64
+		blocks [batchSize]*block
+	)
+	for len(hashes) > 0 {
+		n := copy(buf[:], hashes)
+		hashes = hashes[n:]
+		runtime.KeepAlive(f.prefetch(buf[:n], &blocks))
+
+		for i, h := range buf[:n] {
+			_, h1, h2 := f.layout.split(h)
+			present = append(present, f.testAndAdd(blocks[i], h1, h2))
+		}
+	}
+	return present
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(1000, 0xba7c4)
+
+	for _, premix := range []bool{false, true} {
+		f := New(1<<16, 6)
+		g := New(1<<16, 6)
+		f.premix, g.premix = premix, premix
+
+		saved := append([]uint64(nil), hashes[:500]...)
+		f.AddBatch(hashes[:500])
+		assert.Equal(t, saved, hashes[:500], "input modified")
+		for _, h := range hashes[:500] {
+			g.Add(h)
+		}
+		assert.True(t, f.Equals(g))
+
+		found := f.HasBatch(hashes, []bool{true})
+		assert.Len(t, found, 1+len(hashes))
+		for i, h := range hashes {
+			assert.Equal(t, f.Has(h), found[i+1])
+		}
+	}
+}
+
+func TestHasBatchBits(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(1000, 0xb175)
+	f := New(1<<14, 4)
+	f.AddBatch(hashes[:300])
+
+	for _, n := range []int{0, 1, 63, 64, 65, 1000} {
+		// Dirty buffer, to check that it is cleared.
+		found := f.HasBatchBits(hashes[:n], []uint64{^uint64(0), ^uint64(0)})
+		assert.Len(t, found, (n+63)/64)
+		for i, h := range hashes[:n] {
+			assert.Equal(t, f.Has(h), found[i/64]&(1<<(i%64)) != 0)
+		}
+		if n%64 != 0 {
+			assert.Zero(t, found[n/64]>>(n%64), "bits past end")
+		}
+	}
+}
+
+func TestTestAndAddBatch(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(1000, 0x7e57)
+	// Duplicates within a batch.
+	hashes = append(hashes, hashes[:40]...)
+
+	for _, premix := range []bool{false, true} {
+		f := NewSync(1<<16, 6)
+		f.premix = premix
+		ref := New(1<<16, 6)
+		ref.premix = premix
+
+		saved := append([]uint64(nil), hashes...)
+		present := f.TestAndAddBatch(hashes, nil)
+		assert.Equal(t, saved, hashes, "input modified")
+		for i, h := range hashes {
+			assert.Equal(t, ref.Has(h), present[i])
+			ref.Add(h)
+		}
+	}
+}
+
+// A batch benchmark probes a filter much larger than the CPU caches with
+// a stream of random hashes, so that nearly every probe is a cache miss.
+var batchBench struct {
+	once   sync.Once
+	f      *Filter
+	hashes []uint64
+}
+
+// batchBenchChunk returns the i'th chunk of 4096 hashes for a benchmark.
+func batchBenchChunk(i int) []uint64 {
+	const chunk = 1 << 12
+	h := batchBench.hashes
+	off := i * chunk % len(h)
+	return h[off : off+chunk]
+}
+
+func setupBatchBench() *Filter {
+	batchBench.once.Do(func() {
+		batchBench.f = New(1<<32, 7) // 512MiB.
+		batchBench.hashes = randomU64(1<<22, 0xba)
+		batchBench.f.AddBatch(batchBench.hashes[:1<<21])
+	})
+	return batchBench.f
+}
+
+func BenchmarkHasBatchBits(b *testing.B) {
+	f := setupBatchBench()
+	found := make([]uint64, len(batchBenchChunk(0))/64)
+
+	b.SetBytes(int64(8 * len(found) * 64))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		found = f.HasBatchBits(batchBenchChunk(i), found)
+	}
+}
+
+func benchmarkBatch(b *testing.B, batch bool) {
+	f := setupBatchBench()
+	found := make([]bool, 0, len(batchBenchChunk(0)))
+
+	b.SetBytes(int64(8 * cap(found)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		hashes := batchBenchChunk(i)
+		found = found[:0]
+		if batch {
+			found = f.HasBatch(hashes, found)
+			continue
+		}
+		for _, h := range hashes {
+			found = append(found, f.Has(h))
+		}
+	}
+}
+
+func BenchmarkHasBatch(b *testing.B)  { benchmarkBatch(b, true) }
+func BenchmarkHasSingle(b *testing.B) { benchmarkBatch(b, false) }
@@ -73,6 +73,28 @@ func BenchmarkAddSync128kB(b *testing.B) { benchmarkAddSync(b, 1<<20) }
 func BenchmarkAddSync1MB(b *testing.B)   { benchmarkAddSync(b, 1<<23) }
 func BenchmarkAddSync16MB(b *testing.B)  { benchmarkAddSync(b, 1<<27) }
 
+// Each goroutine adds keys to its own block, with blocks spaced apart
+// by the given distance. A distance of one shows whether writes to
+// neighboring blocks interfere, e.g., because the CPU transfers cache
+// lines in pairs.
+func benchmarkAddSyncBlocks(b *testing.B, distance uint64) {
+	const nblocks = 1 << 12
+	f := NewSync(nblocks*BlockBits, 8)
+	var worker uint64
+
+	b.RunParallel(func(pb *testing.PB) {
+		w := atomic.AddUint64(&worker, 1)
+		blk := (w * distance % nblocks << 32) / nblocks
+		r := rand.New(rand.NewSource(int64(w)))
+		for pb.Next() {
+			f.Add(r.Uint64()<<32 | blk)
+		}
+	})
+}
+
+func BenchmarkAddSyncNeighbors(b *testing.B) { benchmarkAddSyncBlocks(b, 1) }
+func BenchmarkAddSyncSpread(b *testing.B)    { benchmarkAddSyncBlocks(b, 16) }
+
 func BenchmarkCardinalityDense(b *testing.B) {
 	f := New(1<<20, 2)
 	for i := range f.b {
@@ -109,6 +131,7 @@ func BenchmarkOnescount(b *testing.B) {
 		blk[i] = rand.Uint32()
 	}
 
+	onescount := kernel().onescount
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
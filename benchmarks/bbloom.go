@@ -19,6 +19,8 @@ package benchmarks
 
 import "github.com/ipfs/bbloom"
 
+const implementation = "bbloom"
+
 type bloomFilter = bbloom.Bloom
 
 func newBF(capacity int, fpr float64) *bloomFilter {
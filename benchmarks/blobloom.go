@@ -23,6 +23,8 @@ import (
 	"github.com/greatroar/blobloom"
 )
 
+const implementation = "blobloom"
+
 type bloomFilter blobloom.Filter
 
 func (f *bloomFilter) Add(hash []byte) {
@@ -22,6 +22,8 @@ import (
 	"github.com/zeebo/xxh3"
 )
 
+const implementation = "xxh3"
+
 type bloomFilter blobloom.Filter
 
 func (f *bloomFilter) Add(hash []byte) {
@@ -20,6 +20,8 @@ import (
 	"github.com/greatroar/blobloom"
 )
 
+const implementation = "xxhash"
+
 type bloomFilter blobloom.Filter
 
 func (f *bloomFilter) Add(hash []byte) {
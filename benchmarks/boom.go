@@ -19,6 +19,8 @@ package benchmarks
 
 import "github.com/tylertreat/BoomFilters"
 
+const implementation = "boom"
+
 type bloomFilter boom.BloomFilter
 
 func (f *bloomFilter) Add(hash []byte) {
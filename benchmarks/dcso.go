@@ -19,6 +19,8 @@ package benchmarks
 
 import "github.com/DCSO/bloom"
 
+const implementation = "dcso"
+
 type bloomFilter struct{ bloom.BloomFilter }
 
 func newBF(capacity int, fpr float64) *bloomFilter {
@@ -22,6 +22,8 @@ import (
 	"github.com/devopsfaith/bloomfilter/bloomfilter"
 )
 
+const implementation = "devopsfaith"
+
 type bloomFilter baseBloomfilter.Bloomfilter
 
 func newBF(capacity int, fpr float64) *bloomFilter {
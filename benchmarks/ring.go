@@ -19,6 +19,8 @@ package benchmarks
 
 import "github.com/tannerryan/ring"
 
+const implementation = "ring"
+
 type bloomFilter ring.Ring
 
 func (f *bloomFilter) Add(hash []byte) {
@@ -0,0 +1,142 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package benchmarks contains benchmarks for various Bloom filter
+// implementations, selected by build tags. See README.md for details.
+//
+// The benchmarks can be run through go test or, for comparison against
+// other code in a downstream project's CI, through Run.
+package benchmarks
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Config selects the workloads run by Run.
+type Config struct {
+	// Each workload is run for each combination of capacity
+	// and false positive rate. Capacities defaults to 1e5, 1e6, 1e7, 1e8
+	// and FPRates defaults to 1e-2, 1e-3, as in the go test benchmarks.
+	Capacities []int
+	FPRates    []float64
+
+	// Number of keys used to measure the actual false positive rate.
+	// Defaults to 1<<16.
+	FPRProbes int
+}
+
+// A Report holds the results of Run.
+type Report struct {
+	// Filter implementation benchmarked, which is the build tag that
+	// selects it or "blobloom" for the default.
+	Implementation string
+
+	Results []Result
+}
+
+// A Result holds the measurements for one workload.
+type Result struct {
+	// Workload is one of "Add", "TestPos", "TestNeg" or "TestEmpty",
+	// corresponding to the go test benchmarks of the same name.
+	Workload string
+	Capacity int
+	FPRate   float64 // Target false positive rate.
+
+	N        int     // Number of iterations.
+	NsPerOp  float64 // Time per key.
+	GBPerSec float64 // Throughput in terms of 32-byte keys.
+
+	// Measured false positive rate after Capacity keys have been added.
+	// Only set for TestNeg.
+	ActualFPRate float64
+}
+
+var workloads = []struct {
+	name string
+	fn   func(b *testing.B, capacity int, fpr float64)
+}{
+	{"Add", benchmarkAdd},
+	{"TestPos", benchmarkTestPos},
+	{"TestNeg", benchmarkTestNeg},
+	{"TestEmpty", benchmarkTestEmpty},
+}
+
+// Run runs the benchmark workloads for the filter implementation
+// selected at build time, using testing.Benchmark.
+func Run(config Config) Report {
+	capacities := config.Capacities
+	if len(capacities) == 0 {
+		capacities = []int{1e5, 1e6, 1e7, 1e8}
+	}
+	fprates := config.FPRates
+	if len(fprates) == 0 {
+		fprates = []float64{1e-2, 1e-3}
+	}
+	nprobes := config.FPRProbes
+	if nprobes <= 0 {
+		nprobes = 1 << 16
+	}
+
+	report := Report{Implementation: implementation}
+
+	for _, w := range workloads {
+		for _, fpr := range fprates {
+			for _, capacity := range capacities {
+				fn := w.fn
+				capacity, fpr := capacity, fpr
+				r := testing.Benchmark(func(b *testing.B) { fn(b, capacity, fpr) })
+
+				res := Result{
+					Workload: w.name,
+					Capacity: capacity,
+					FPRate:   fpr,
+					N:        r.N,
+				}
+				if r.N > 0 {
+					res.NsPerOp = float64(r.T.Nanoseconds()) / float64(r.N)
+					res.GBPerSec = hashSize / res.NsPerOp
+				}
+				if w.name == "TestNeg" {
+					res.ActualFPRate = measureFPR(capacity, fpr, nprobes)
+				}
+				report.Results = append(report.Results, res)
+			}
+		}
+	}
+
+	return report
+}
+
+// measureFPR fills a Bloom filter to capacity, then reports the fraction
+// of nprobes fresh keys that it claims to contain.
+func measureFPR(capacity int, fpr float64, nprobes int) float64 {
+	r := rand.New(rand.NewSource(0xae694))
+	f := newBF(capacity, fpr)
+
+	h := make([]byte, hashSize)
+	for i := 0; i < capacity; i++ {
+		r.Read(h)
+		f.Add(h)
+	}
+
+	hashes := makehashes(nprobes, 562175)
+	fp := 0
+	for i := 0; i < nprobes; i++ {
+		if f.Has(hashes[i*hashSize : (i+1)*hashSize]) {
+			fp++
+		}
+	}
+	return float64(fp) / float64(nprobes)
+}
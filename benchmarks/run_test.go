@@ -0,0 +1,53 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmarks
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs benchmarks")
+	}
+
+	const capacity, fpr = 1e4, 1e-2
+
+	report := Run(Config{
+		Capacities: []int{capacity},
+		FPRates:    []float64{fpr},
+	})
+
+	if report.Implementation != implementation {
+		t.Errorf("implementation = %q", report.Implementation)
+	}
+	if len(report.Results) != len(workloads) {
+		t.Fatalf("got %d results, want %d", len(report.Results), len(workloads))
+	}
+
+	for i, r := range report.Results {
+		if r.Workload != workloads[i].name || r.Capacity != capacity || r.FPRate != fpr {
+			t.Errorf("unexpected result %+v", r)
+		}
+		if r.N == 0 || r.NsPerOp <= 0 || r.GBPerSec <= 0 {
+			t.Errorf("%s: no measurements: %+v", r.Workload, r)
+		}
+		if r.Workload == "TestNeg" {
+			if r.ActualFPRate <= 0 || r.ActualFPRate > 4*fpr {
+				t.Errorf("%s: false positive rate %g", r.Workload, r.ActualFPRate)
+			}
+		} else if r.ActualFPRate != 0 {
+			t.Errorf("%s: false positive rate set", r.Workload)
+		}
+	}
+}
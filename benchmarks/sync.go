@@ -23,6 +23,8 @@ import (
 	"github.com/greatroar/blobloom"
 )
 
+const implementation = "sync"
+
 type bloomFilter blobloom.SyncFilter
 
 func (f *bloomFilter) Add(hash []byte) {
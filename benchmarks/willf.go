@@ -19,6 +19,8 @@ package benchmarks
 
 import "github.com/bits-and-blooms/bloom/v3"
 
+const implementation = "willf"
+
 type bloomFilter bloom.BloomFilter
 
 func (f *bloomFilter) Add(hash []byte) {
@@ -0,0 +1,124 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blobloomtest provides utilities for testing code that uses
+// the blobloom package, in particular its error handling around
+// Dump, NewLoader and Load.
+//
+// It also provides CompareImplementations, for testing the package's
+// own implementations of bulk operations against each other,
+// and FakeClock, for testing time-based components deterministically.
+package blobloomtest
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+)
+
+// ErrInjected is the default error returned by a FaultyReader or FaultyWriter.
+var ErrInjected = errors.New("blobloomtest: injected fault")
+
+// A FaultyReader reads from R until N bytes have been read,
+// then returns Err, or ErrInjected if Err is nil.
+type FaultyReader struct {
+	R   io.Reader
+	N   int64 // Number of bytes to read before failing.
+	Err error
+}
+
+func (r *FaultyReader) Read(p []byte) (n int, err error) {
+	if r.N <= 0 {
+		return 0, r.err()
+	}
+	if int64(len(p)) > r.N {
+		p = p[:r.N]
+	}
+	n, err = r.R.Read(p)
+	r.N -= int64(n)
+	return n, err
+}
+
+func (r *FaultyReader) err() error {
+	if r.Err == nil {
+		return ErrInjected
+	}
+	return r.Err
+}
+
+// A FaultyWriter writes to W until N bytes have been written,
+// then returns Err, or ErrInjected if Err is nil.
+// The write that crosses the limit is a short write.
+type FaultyWriter struct {
+	W   io.Writer
+	N   int64 // Number of bytes to write before failing.
+	Err error
+}
+
+func (w *FaultyWriter) Write(p []byte) (n int, err error) {
+	short := int64(len(p)) > w.N
+	if short {
+		p = p[:w.N]
+	}
+	n, err = w.W.Write(p)
+	w.N -= int64(n)
+	if err == nil && short {
+		err = w.err()
+	}
+	return n, err
+}
+
+func (w *FaultyWriter) err() error {
+	if w.Err == nil {
+		return ErrInjected
+	}
+	return w.Err
+}
+
+// FlipBits calls fn n times, each time with a copy of dump in which
+// a single bit has been flipped. The bits are chosen pseudo-randomly,
+// based on seed. The index of the flipped bit is passed as the second
+// argument to fn.
+//
+// FlipBits can be used to check that code handles corrupted dumps
+// gracefully. Note that most bit flips in the blocks of a dump cannot
+// be detected; they merely change the contents of the Bloom filter.
+// Flips in the block count, bits 96 to 127, produce a valid header
+// that may cause Loader.Load(nil) to allocate a very large filter.
+func FlipBits(dump []byte, n int, seed int64, fn func(mutated []byte, bit int)) {
+	if len(dump) == 0 {
+		return
+	}
+	r := rand.New(rand.NewSource(seed))
+	p := make([]byte, len(dump))
+
+	for i := 0; i < n; i++ {
+		copy(p, dump)
+		bit := r.Intn(8 * len(p))
+		p[bit/8] ^= 1 << (bit % 8)
+		fn(p, bit)
+	}
+}
+
+// Truncations calls fn with every proper prefix of dump, from short to long,
+// in steps of the given size. A dump truncated at any point should fail
+// to load.
+func Truncations(dump []byte, step int, fn func(truncated []byte)) {
+	if step < 1 {
+		step = 1
+	}
+	for i := 0; i < len(dump); i += step {
+		fn(dump[:i])
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloomtest_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/greatroar/blobloom"
+	"github.com/greatroar/blobloom/blobloomtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeDump(t *testing.T) []byte {
+	t.Helper()
+
+	f := blobloom.New(10*blobloom.BlockBits, 4)
+	for i := uint64(0); i < 100; i++ {
+		f.Add(i * 0x9e3779b97f4a7c15)
+	}
+	var buf bytes.Buffer
+	_, err := blobloom.Dump(&buf, f, "test")
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func load(r io.Reader) (*blobloom.Filter, error) {
+	l, err := blobloom.NewLoader(r)
+	if err != nil {
+		return nil, err
+	}
+	return l.Load(nil)
+}
+
+func TestFaultyWriter(t *testing.T) {
+	dump := makeDump(t)
+
+	for _, n := range []int64{0, 10, 64, 100, int64(len(dump)) - 1} {
+		var buf bytes.Buffer
+		w := &blobloomtest.FaultyWriter{W: &buf, N: n}
+		_, err := io.Copy(w, bytes.NewReader(dump))
+		assert.Equal(t, blobloomtest.ErrInjected, err)
+		assert.EqualValues(t, n, buf.Len())
+	}
+
+	w := &blobloomtest.FaultyWriter{W: ioutil.Discard, N: int64(len(dump))}
+	_, err := w.Write(dump)
+	assert.NoError(t, err)
+}
+
+func TestFaultyReader(t *testing.T) {
+	dump := makeDump(t)
+
+	for _, n := range []int64{0, 32, 64, 65, int64(len(dump)) - 1} {
+		_, err := load(&blobloomtest.FaultyReader{R: bytes.NewReader(dump), N: n})
+		assert.Equal(t, blobloomtest.ErrInjected, err)
+	}
+
+	r := &blobloomtest.FaultyReader{R: bytes.NewReader(dump), N: 1 << 20, Err: io.ErrClosedPipe}
+	_, err := load(r)
+	assert.NoError(t, err)
+}
+
+func TestFlipBits(t *testing.T) {
+	dump := makeDump(t)
+
+	nflips := 0
+	blobloomtest.FlipBits(dump, 1000, 1, func(p []byte, bit int) {
+		nflips++
+		assert.NotEqual(t, dump, p)
+
+		if bit >= 8*12 && bit < 8*16 {
+			// Flips in the block count produce valid headers, but may
+			// make Load allocate huge filters before it fails.
+			_, err := blobloom.NewLoader(bytes.NewReader(p))
+			assert.NoError(t, err)
+			return
+		}
+
+		f, err := load(bytes.NewReader(p))
+		if bit < 8*8 {
+			assert.Error(t, err, "bit %d", bit) // Magic number.
+		}
+		if err != nil {
+			assert.Nil(t, f)
+		}
+	})
+	assert.Equal(t, 1000, nflips)
+}
+
+func TestTruncations(t *testing.T) {
+	dump := makeDump(t)
+
+	n := 0
+	blobloomtest.Truncations(dump, 7, func(p []byte) {
+		n++
+		_, err := load(bytes.NewReader(p))
+		assert.Error(t, err)
+	})
+	assert.Equal(t, (len(dump)+6)/7, n)
+}
+
+func TestCompareImplementations(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		assert.NoError(t, blobloomtest.CompareImplementations(seed, 50))
+	}
+}
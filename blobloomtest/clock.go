@@ -0,0 +1,105 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloomtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/greatroar/blobloom"
+)
+
+// A FakeClock is a blobloom.Clock whose time only moves when Advance
+// is called. It is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+// NewFakeClock returns a FakeClock set to t.
+func NewFakeClock(t time.Time) *FakeClock { return &FakeClock{now: t} }
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker returns a Ticker that fires when Advance moves the clock past
+// multiples of d from now. Like a time.Ticker, it drops ticks for slow
+// receivers. It panics if d is not positive.
+func (c *FakeClock) NewTicker(d time.Duration) blobloom.Ticker {
+	if d <= 0 {
+		panic("blobloomtest: non-positive interval for NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTicker{c: make(chan time.Time, 1), clock: c, d: d, next: c.now.Add(d)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and fires the tickers that are due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		if t.next.After(c.now) {
+			continue
+		}
+		select {
+		case t.c <- c.now:
+		default:
+		}
+		missed := c.now.Sub(t.next) / t.d
+		t.next = t.next.Add((missed + 1) * t.d)
+	}
+}
+
+// Tickers returns the number of tickers that have not been stopped.
+// Tests can use it to wait until a goroutine has started its ticker
+// before calling Advance.
+func (c *FakeClock) Tickers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.tickers)
+}
+
+type fakeTicker struct {
+	c     chan time.Time
+	clock *FakeClock
+	d     time.Duration
+	next  time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.c }
+
+func (t *fakeTicker) Stop() {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, u := range c.tickers {
+		if u == t {
+			c.tickers = append(c.tickers[:i], c.tickers[i+1:]...)
+			return
+		}
+	}
+}
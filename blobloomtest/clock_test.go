@@ -0,0 +1,105 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloomtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/greatroar/blobloom"
+	"github.com/greatroar/blobloom/blobloomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClock(t *testing.T) {
+	t.Parallel()
+
+	c := blobloomtest.NewFakeClock(t0)
+	assert.Equal(t, t0, c.Now())
+
+	tick := c.NewTicker(time.Minute)
+	assert.Equal(t, 1, c.Tickers())
+
+	c.Advance(30 * time.Second)
+	assert.Len(t, tick.C(), 0)
+	c.Advance(30 * time.Second)
+	assert.Equal(t, t0.Add(time.Minute), <-tick.C())
+
+	// Missed ticks are dropped.
+	c.Advance(10 * time.Minute)
+	assert.Len(t, tick.C(), 1)
+	<-tick.C()
+	c.Advance(time.Minute)
+	assert.Len(t, tick.C(), 1)
+
+	tick.Stop()
+	assert.Equal(t, 0, c.Tickers())
+	assert.Panics(t, func() { c.NewTicker(0) })
+}
+
+func TestFakeClockRotating(t *testing.T) {
+	t.Parallel()
+
+	c := blobloomtest.NewFakeClock(t0)
+	config := blobloom.Config{Capacity: 100, FPRate: 1e-6}
+	r := blobloom.NewRotating(config, 1).WithClock(c)
+	r.Add(42)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.RotateEvery(ctx, time.Hour)
+	waitTickers(t, c, 1)
+
+	c.Advance(59 * time.Minute)
+	assert.True(t, r.Has(42))
+	c.Advance(time.Minute)
+	assert.Eventually(t, func() bool { return !r.Has(42) }, time.Second, time.Millisecond)
+}
+
+func TestFakeClockHistory(t *testing.T) {
+	t.Parallel()
+
+	c := blobloomtest.NewFakeClock(t0)
+	h := blobloom.NewHistory(4).WithClock(c)
+	f := blobloom.New(1<<10, 3)
+	h.Record(f)
+	c.Advance(time.Minute)
+	h.Record(f)
+
+	s := h.Snapshots()
+	assert.Equal(t, t0, s[0].Time)
+	assert.Equal(t, t0.Add(time.Minute), s[1].Time)
+}
+
+func TestFakeClockTimeWindow(t *testing.T) {
+	t.Parallel()
+
+	c := blobloomtest.NewFakeClock(t0)
+	config := blobloom.Config{Capacity: 100, FPRate: 1e-6}
+	w := blobloom.NewTimeWindow(config, time.Minute, 2).WithClock(c)
+	w.Add(42)
+	c.Advance(time.Minute)
+	assert.True(t, w.Has(42))
+	c.Advance(time.Minute)
+	assert.False(t, w.Has(42))
+}
+
+func waitTickers(t *testing.T, c *blobloomtest.FakeClock, n int) {
+	t.Helper()
+	assert.Eventually(t, func() bool { return c.Tickers() == n }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloomtest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/greatroar/blobloom"
+)
+
+// CompareImplementations runs a random sequence of nops operations,
+// determined by seed, through every implementation of bulk operations
+// listed by blobloom.Implementations. It returns an error describing the
+// first operation whose result differs from that of the "generic"
+// implementation.
+//
+// Forks that add their own implementations can call CompareImplementations
+// from a test with many seeds. It switches implementations with
+// blobloom.SetImplementation and restores the original one before
+// returning, so it must not run concurrently with code that relies
+// on a particular implementation.
+func CompareImplementations(seed int64, nops int) error {
+	impls := blobloom.Implementations()
+	defer blobloom.SetImplementation(blobloom.Implementation())
+
+	r := rand.New(rand.NewSource(seed))
+	nbits := uint64(1+r.Intn(64)) * blobloom.BlockBits
+	nhashes := 2 + r.Intn(10)
+
+	filters := make([]*blobloom.Filter, len(impls))
+	syncs := make([]*blobloom.SyncFilter, len(impls))
+	for i := range impls {
+		filters[i] = blobloom.New(nbits, nhashes)
+		syncs[i] = blobloom.NewSync(nbits, nhashes)
+	}
+
+	randomFilter := func() *blobloom.Filter {
+		g := blobloom.New(nbits, nhashes)
+		for n := r.Intn(int(nbits) / 4); n > 0; n-- {
+			g.Add(r.Uint64())
+		}
+		return g
+	}
+
+	for op := 0; op < nops; op++ {
+		var (
+			name  string
+			apply func(f *blobloom.Filter, s *blobloom.SyncFilter) float64
+		)
+		switch r.Intn(4) {
+		case 0:
+			name = "Add"
+			hashes := make([]uint64, r.Intn(100))
+			for i := range hashes {
+				hashes[i] = r.Uint64()
+			}
+			apply = func(f *blobloom.Filter, s *blobloom.SyncFilter) float64 {
+				for _, h := range hashes {
+					f.Add(h)
+					s.Add(h)
+				}
+				return 0
+			}
+		case 1:
+			name = "Union"
+			g := randomFilter()
+			apply = func(f *blobloom.Filter, _ *blobloom.SyncFilter) float64 {
+				f.Union(g)
+				return 0
+			}
+		case 2:
+			name = "Intersect"
+			g := randomFilter()
+			g.Union(filters[0]) // Keep some bits set.
+			apply = func(f *blobloom.Filter, _ *blobloom.SyncFilter) float64 {
+				f.Intersect(g)
+				return 0
+			}
+		case 3:
+			name = "Cardinality"
+			apply = func(f *blobloom.Filter, s *blobloom.SyncFilter) float64 {
+				c, cs := f.Cardinality(), s.Cardinality()
+				return c + 2*cs
+			}
+		}
+
+		var results []float64
+		for i, impl := range impls {
+			if err := blobloom.SetImplementation(impl); err != nil {
+				return err
+			}
+			results = append(results, apply(filters[i], syncs[i]))
+		}
+
+		for i := 1; i < len(impls); i++ {
+			switch {
+			case math.Float64bits(results[i]) != math.Float64bits(results[0]):
+				return fmt.Errorf("blobloomtest: operation %d (%s): %s returned %v, %s returned %v",
+					op, name, impls[i], results[i], impls[0], results[0])
+			case !filters[i].Equals(filters[0]):
+				blk, word := filters[i].DiffIndex(filters[0])
+				return fmt.Errorf("blobloomtest: operation %d (%s): %s and %s differ in block %d, word %d",
+					op, name, impls[i], impls[0], blk, word)
+			}
+		}
+	}
+	return nil
+}
@@ -46,13 +46,22 @@ import "math"
 // of popular architectures (386, amd64, arm64).
 const BlockBits = 512
 
+// BlockAlignment is the alignment, in bytes, of the blocks of the filters
+// that this package allocates, so that each block occupies exactly one
+// cache line. The alignment is not guaranteed when the nounsafe build tag
+// is set, nor for memory obtained from an Allocator.
+const BlockAlignment = BlockBits / 8
+
 // MaxBits is the maximum number of bits supported by a Filter.
 const MaxBits = BlockBits << 32 // 256GiB.
 
 // A Filter is a blocked Bloom filter.
 type Filter struct {
-	b []block // Shards.
-	k int     // Number of hash functions required.
+	b      []block // Shards.
+	k      int     // Number of hash functions required.
+	premix bool    // Whether to mix hash values before use.
+	layout Layout
+	free   func() // Releases b, if not allocated by Go.
 }
 
 // New constructs a Bloom filter with given numbers of bits and hash functions.
@@ -67,7 +76,7 @@ func New(nbits uint64, nhashes int) *Filter {
 	nbits, nhashes = fixBitsAndHashes(nbits, nhashes)
 
 	return &Filter{
-		b: make([]block, nbits/BlockBits),
+		b: makeBlocks(nbits / BlockBits),
 		k: nhashes,
 	}
 }
@@ -93,13 +102,35 @@ func fixBitsAndHashes(nbits uint64, nhashes int) (uint64, int) {
 
 // Add insert a key with hash value h into f.
 func (f *Filter) Add(h uint64) {
-	h1, h2 := uint32(h>>32), uint32(h)
-	b := getblock(f.b, h2)
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	b := getblock(f.b, blk)
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		b.setbit(bit)
+		return true
+	})
+}
 
-	for i := 1; i < f.k; i++ {
-		h1, h2 = doublehash(h1, h2, i)
-		b.setbit(h1)
+// TestAndAdd inserts a key with hash value h into f and reports whether
+// it was already present, i.e., whether Has(h) would have returned true.
+// It is cheaper than Has followed by Add.
+func (f *Filter) TestAndAdd(h uint64) bool {
+	if f.premix {
+		h = mix64(h)
 	}
+	blk, h1, h2 := f.layout.split(h)
+	b := getblock(f.b, blk)
+
+	present := true
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		if !b.testAndSet(bit) {
+			present = false
+		}
+		return true
+	})
+	return present
 }
 
 // log(1 - 1/BlockBits) computed with 128 bits precision.
@@ -118,7 +149,7 @@ const log1minus1divBlockbits = -0.0019550348358033505576274922418668121377
 // and Nejdl, summed over the blocks
 // (https://www.win.tue.nl/~opapapetrou/papers/Bloomfilters-DAPD.pdf).
 func (f *Filter) Cardinality() float64 {
-	return cardinality(f.k, f.b, onescount)
+	return cardinality(f.k, f.b, kernel().onescount)
 }
 
 func cardinality(nhashes int, b []block, onescount func(*block) int) float64 {
@@ -139,6 +170,61 @@ func cardinality(nhashes int, b []block, onescount func(*block) int) float64 {
 	return n / logP0
 }
 
+// EstimateUnionCardinality estimates the number of distinct keys in the
+// union of f and g. It returns the same as f.Cardinality would after
+// f.Union(g), but modifies neither f nor g.
+//
+// EstimateUnionCardinality panics under the same conditions as Union.
+func EstimateUnionCardinality(f, g *Filter) float64 {
+	checkShape(f, g)
+	k := f.k
+	if g.k < k {
+		k = g.k
+	}
+	onescount := kernel().onescount
+
+	var n float64
+	for i := range f.b {
+		var u block
+		for j := range u {
+			u[j] = f.b[i][j] | g.b[i][j]
+		}
+		ones := onescount(&u)
+		if ones == 0 {
+			continue
+		}
+		n += math.Log1p(-float64(ones) / BlockBits)
+	}
+	return n / (float64(k-1) * log1minus1divBlockbits)
+}
+
+// EstimateIntersectionCardinality estimates the number of distinct keys
+// in the intersection of f and g, by inclusion-exclusion:
+// |f ∩ g| = |f| + |g| - |f ∪ g|. The estimate is never negative.
+//
+// The estimate is only as good as the Cardinality estimates it is computed
+// from, and has a large relative error when the intersection is small
+// compared to the union.
+//
+// EstimateIntersectionCardinality panics under the same conditions as Union.
+func EstimateIntersectionCardinality(f, g *Filter) float64 {
+	n := f.Cardinality() + g.Cardinality() - EstimateUnionCardinality(f, g)
+	return math.Max(n, 0)
+}
+
+// Jaccard estimates the Jaccard similarity, |f ∩ g| / |f ∪ g|, of the sets
+// of keys in f and g. It returns zero if both are empty.
+//
+// Jaccard panics under the same conditions as Union.
+func Jaccard(f, g *Filter) float64 {
+	union := EstimateUnionCardinality(f, g)
+	if union == 0 {
+		return 0
+	}
+	inter := math.Max(f.Cardinality()+g.Cardinality()-union, 0)
+	return math.Min(inter/union, 1)
+}
+
 // Clear resets f to its empty state.
 func (f *Filter) Clear() {
 	for i := 0; i < len(f.b); i++ {
@@ -158,16 +244,139 @@ func (f *Filter) Empty() bool {
 
 // Equals returns true if f and g contain the same keys (in terms of Has)
 // when used with the same hash function.
+//
+// When Equals returns false, DiffIndex can be used to find out why.
 func (f *Filter) Equals(g *Filter) bool {
-	if g.k != f.k || len(g.b) != len(f.b) {
+	if g.k != f.k || g.premix != f.premix || g.layout != f.layout {
 		return false
 	}
-	for i := range g.b {
-		if f.b[i] != g.b[i] {
-			return false
+	blk, _ := f.DiffIndex(g)
+	return blk < 0
+}
+
+// EqualsConstantTime is like Equals, but its running time depends only on
+// the sizes of f and g, not on their contents, so that it does not reveal
+// where they differ.
+func (f *Filter) EqualsConstantTime(g *Filter) bool {
+	if g.k != f.k || g.premix != f.premix || g.layout != f.layout ||
+		len(g.b) != len(f.b) {
+		return false
+	}
+	var diff uint32
+	for i := range f.b {
+		for j := range f.b[i] {
+			diff |= f.b[i][j] ^ g.b[i][j]
 		}
 	}
-	return true
+	return diff == 0
+}
+
+// DiffIndex returns the index of the first block in which the bits of f
+// and g differ, and the index of the first differing 32-bit word in that
+// block. It returns -1, -1 if f and g have the same bits.
+//
+// If one filter has fewer blocks than the other and they agree on those,
+// the result is the number of blocks of the smaller one and word zero.
+// DiffIndex does not compare the number of hash functions or other
+// parameters; Equals does.
+func (f *Filter) DiffIndex(g *Filter) (block, word int) {
+	n := len(f.b)
+	if len(g.b) < n {
+		n = len(g.b)
+	}
+	for i := 0; i < n; i++ {
+		if f.b[i] == g.b[i] {
+			continue
+		}
+		for j := range f.b[i] {
+			if f.b[i][j] != g.b[i][j] {
+				return i, j
+			}
+		}
+	}
+	if len(f.b) != len(g.b) {
+		return n, 0
+	}
+	return -1, -1
+}
+
+// Fold shrinks f to 1/factor of its size by ORing each run of factor
+// consecutive blocks into a single block. Every key added to f before the
+// fold is still reported by Has, and keys can be added afterwards, but the
+// false positive rate goes up, as reported by f.FPRate.
+//
+// This reclaims memory from a filter that turns out to be much emptier
+// than planned, without re-adding the keys.
+//
+// Fold panics if factor < 1 or the number of blocks of f is not a multiple
+// of factor.
+func (f *Filter) Fold(factor int) {
+	if factor < 1 || len(f.b)%factor != 0 {
+		panic("blobloom: number of blocks is not a multiple of the folding factor")
+	}
+	if factor == 1 {
+		return
+	}
+
+	// Block selection maps a hash to block i of n blocks if and only if it
+	// maps it to block i/factor of n/factor blocks, since
+	// floor(floor(x)/factor) = floor(x/factor).
+	b := makeBlocks(uint64(len(f.b) / factor))
+	for i := range f.b {
+		b[i/factor].union(&f.b[i])
+	}
+
+	if f.free != nil {
+		f.free()
+		f.free = nil
+	}
+	f.b = b
+}
+
+// UnionFolded is like Union, but also accepts filters whose numbers of
+// blocks differ by a whole factor. The larger of the two is folded down
+// to the size of the smaller, as by Fold, so f may shrink; g is never
+// modified.
+//
+// UnionFolded panics if neither number of blocks is a multiple of the
+// other, or if f and g have different premixing settings or layouts.
+func (f *Filter) UnionFolded(g *Filter) {
+	small, large := len(f.b), len(g.b)
+	if small > large {
+		small, large = large, small
+	}
+	if small == 0 || large%small != 0 {
+		panic("blobloom: numbers of blocks are not multiples of each other")
+	}
+	// Check the other parameters before modifying f.
+	f2, g2 := *f, *g
+	f2.b, g2.b = f.b[:small], g.b[:small]
+	checkShape(&f2, &g2)
+	f.Fold(len(f.b) / small)
+
+	if g.k < f.k {
+		f.k = g.k
+	}
+	factor := len(g.b) / small
+	if factor == 1 {
+		kernel().union(f.b, g.b)
+		return
+	}
+	for i := range g.b {
+		f.b[i/factor].union(&g.b[i])
+	}
+}
+
+// Free releases the memory of a Filter constructed by NewWithAllocator,
+// or unmaps a Filter returned by OpenMmap. Afterwards, f is empty and must
+// not be used, except that further calls to Free do nothing. For other
+// Filters, Free only drops the reference to the memory, leaving it to the
+// garbage collector.
+func (f *Filter) Free() {
+	if f.free != nil {
+		f.free()
+	}
+	f.b, f.free = nil, nil
 }
 
 // Fill set f to a completely full filter.
@@ -183,12 +392,90 @@ func (f *Filter) Fill() {
 // Has reports whether a key with hash value h has been added.
 // It may return a false positive.
 func (f *Filter) Has(h uint64) bool {
-	h1, h2 := uint32(h>>32), uint32(h)
-	b := getblock(f.b, h2)
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	b := getblock(f.b, blk)
+	return probe(h1, h2, f.k, b.getbit)
+}
+
+// HasConstantTime is like Has, but it always probes all bits for h and
+// combines them without branching, so that its running time does not reveal
+// which probe, if any, failed.
+//
+// The memory location of the block that is probed still depends on h,
+// so HasConstantTime does not protect against cache timing attacks.
+func (f *Filter) HasConstantTime(h uint64) bool {
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	b := getblock(f.b, blk)
 
+	var missing uint32
 	for i := 1; i < f.k; i++ {
 		h1, h2 = doublehash(h1, h2, i)
-		if !b.getbit(h1) {
+		x := (*b)[(h1/wordSize)%blockWords]
+		missing |= ^x >> (h1 % wordSize) & 1
+	}
+	return missing == 0
+}
+
+// A Layout determines how a Bloom filter maps hash values to bits.
+//
+// In all layouts, the lower 32 bits of a hash value select the block.
+// They differ in how the positions of the bits within the block are chosen.
+type Layout uint8
+
+const (
+	// LayoutV0 is the original layout. It derives the bit positions from
+	// both halves of the hash value. For filters with more than 2²³
+	// blocks (512MiB), part of the lower half is shared with the block
+	// selection, so keys in the same block get correlated bit positions.
+	// At the maximum size, all keys in a block get the same bit pattern,
+	// shifted by at most BlockBits positions, and the false positive rate
+	// rises well above the estimate from FPRate.
+	//
+	// LayoutV0 is the default, for compatibility with existing dumps.
+	LayoutV0 Layout = 0
+
+	// LayoutV1 derives the bit positions from the upper half of the hash value
+	// only, so that they are independent of the block selection for any
+	// filter size. It should be used for filters larger than 512MiB.
+	//
+	// Filters with LayoutV1 cannot be read by versions of this package
+	// that predate it.
+	LayoutV1 Layout = 1
+)
+
+func (l Layout) check() {
+	if l > LayoutV1 {
+		panic("unknown Bloom filter layout")
+	}
+}
+
+// split splits a hash value into the block selector and the initial values
+// for double hashing.
+func (l Layout) split(h uint64) (blk, h1, h2 uint32) {
+	blk, h1, h2 = uint32(h), uint32(h>>32), uint32(h)
+	if l == LayoutV1 {
+		// doublehash only uses the lower bits of h1 and h2.
+		h2 = uint32(h >> 48)
+	}
+	return blk, h1, h2
+}
+
+// probe calls fn with each bit index in a block that is probed for a key,
+// given h1 and h2 from Layout.split and the number of hashes k, until fn
+// returns false. It reports whether all calls returned true.
+//
+// This is the probing logic shared by the filter types. It is small enough
+// to be inlined along with fn.
+func probe(h1, h2 uint32, k int, fn func(bit uint32) bool) bool {
+	for i := 1; i < k; i++ {
+		h1, h2 = doublehash(h1, h2, i)
+		if !fn(h1) {
 			return false
 		}
 	}
@@ -204,25 +491,57 @@ func doublehash(h1, h2 uint32, i int) (uint32, uint32) {
 	return h1, h2
 }
 
+// mix64 is the SplitMix64 finalizer, a bijective function that turns
+// structured 64-bit values into well-mixed hash values.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
 // NumBits returns the number of bits of f.
 func (f *Filter) NumBits() uint64 {
 	return BlockBits * uint64(len(f.b))
 }
 
+// NumBlocks returns the number of blocks of f.
+func (f *Filter) NumBlocks() int { return len(f.b) }
+
+// K returns the number of hash functions of f.
+func (f *Filter) K() int { return f.k }
+
+// Size returns the approximate number of bytes of memory used by f.
+func (f *Filter) Size() uint64 {
+	return f.NumBits() / 8
+}
+
 func checkBinop(f, g *Filter) {
+	checkShape(f, g)
+	if f.k != g.k {
+		panic("Bloom filters do not have the same number of hash functions")
+	}
+}
+
+func checkShape(f, g *Filter) {
 	if len(f.b) != len(g.b) {
 		panic("Bloom filters do not have the same number of bits")
 	}
-	if f.k != g.k {
-		panic("Bloom filters do not have the same number of hash functions")
+	if f.premix != g.premix {
+		panic("Bloom filters do not have the same premixing setting")
+	}
+	if f.layout != g.layout {
+		panic("Bloom filters do not have the same layout")
 	}
 }
 
 // Intersect sets f to the intersection of f and g.
 //
-// Intersect panics when f and g do not have the same number of bits and
-// hash functions. Both Filters must be using the same hash function(s),
-// but Intersect cannot check this.
+// Intersect panics when f and g do not have the same number of bits,
+// hash functions, premixing setting and layout. Both Filters must be using
+// the same hash function(s), but Intersect cannot check this.
 //
 // Since Bloom filters may return false positives, Has may return true for
 // a key that was not in both f and g.
@@ -231,17 +550,85 @@ func checkBinop(f, g *Filter) {
 // considered unreliable.
 func (f *Filter) Intersect(g *Filter) {
 	checkBinop(f, g)
-	f.intersect(g)
+	kernel().intersect(f.b, g.b)
+}
+
+// IntersectOf sets dst to the intersection of f and g and returns dst.
+// It is like Intersect, but leaves f and g unchanged, unless one of them
+// is dst.
+//
+// If dst is nil, IntersectOf allocates a new Filter. Otherwise, dst must
+// have the same number of bits as f and g, and its contents and other
+// parameters are overwritten.
+//
+// IntersectOf panics under the same conditions as Intersect, or if dst has
+// the wrong number of bits.
+func IntersectOf(dst, f, g *Filter) *Filter {
+	checkBinop(f, g)
+	dst, f, g = binopDst(dst, f, g)
+	kernel().intersect(dst.b, g.b)
+	return dst
+}
+
+// UnionOf sets dst to the union of f and g and returns dst.
+// It is like Union, but leaves f and g unchanged, unless one of them is dst.
+//
+// If dst is nil, UnionOf allocates a new Filter. Otherwise, dst must
+// have the same number of bits as f and g, and its contents and other
+// parameters are overwritten.
+//
+// UnionOf panics under the same conditions as Union, or if dst has
+// the wrong number of bits.
+func UnionOf(dst, f, g *Filter) *Filter {
+	checkShape(f, g)
+	k := f.k
+	if g.k < k {
+		k = g.k
+	}
+	dst, f, g = binopDst(dst, f, g)
+	kernel().union(dst.b, g.b)
+	dst.k = k
+	return dst
+}
+
+// binopDst prepares dst for a commutative operation on f and g. It returns
+// dst, set to a copy of f or g, and the other operand.
+func binopDst(dst, f, g *Filter) (*Filter, *Filter, *Filter) {
+	if dst == g {
+		f, g = g, f
+	}
+	switch {
+	case dst == nil:
+		dst = &Filter{b: makeBlocks(uint64(len(f.b)))}
+	case len(dst.b) != len(f.b):
+		panic("blobloom: destination filter does not have the same number of bits")
+	}
+	if dst != f {
+		copy(dst.b, f.b)
+	}
+	dst.k, dst.premix, dst.layout = f.k, f.premix, f.layout
+	return dst, f, g
 }
 
 // Union sets f to the union of f and g.
 //
-// Union panics when f and g do not have the same number of bits and
-// hash functions. Both Filters must be using the same hash function(s),
-// but Union cannot check this.
+// Union panics when f and g do not have the same number of bits,
+// premixing setting and layout. Both Filters must be using the same hash
+// function(s), but Union cannot check this. UnionFolded can merge filters
+// of different sizes.
+//
+// If f and g have different numbers of hash functions, f ends up with the
+// lower number. Since the bits probed for a key with k hash functions are
+// a subset of those probed with more hash functions, this produces no false
+// negatives, but the false positive rate of f goes up: f.FPRate reports the
+// new estimate. This allows merging filters during an upgrade that changes
+// the number of hash functions.
 func (f *Filter) Union(g *Filter) {
-	checkBinop(f, g)
-	f.union(g)
+	checkShape(f, g)
+	if g.k < f.k {
+		f.k = g.k
+	}
+	kernel().union(f.b, g.b)
 }
 
 const (
@@ -252,15 +639,15 @@ const (
 // A block is a fixed-size Bloom filter, used as a shard of a Filter.
 type block [blockWords]uint32
 
-func getblock(b []block, h2 uint32) *block {
-	i := reducerange(h2, uint32(len(b)))
+func getblock(b []block, blk uint32) *block {
+	i := reducerange(blk, uint64(len(b)))
 	return &b[i]
 }
 
-// reducerange maps i to an integer in the range [0,n).
+// reducerange maps i to an integer in the range [0,n), for n <= 2³².
 // https://lemire.me/blog/2016/06/27/a-fast-alternative-to-the-modulo-reduction/
-func reducerange(i, n uint32) uint32 {
-	return uint32((uint64(i) * uint64(n)) >> 32)
+func reducerange(i uint32, n uint64) uint64 {
+	return (uint64(i) * n) >> 32
 }
 
 // getbit reports whether bit (i modulo BlockBits) is set.
@@ -275,3 +662,12 @@ func (b *block) setbit(i uint32) {
 	bit := uint32(1) << (i % wordSize)
 	(*b)[(i/wordSize)%blockWords] |= bit
 }
+
+// testAndSet sets bit (i modulo BlockBits) and reports whether it was set.
+func (b *block) testAndSet(i uint32) bool {
+	bit := uint32(1) << (i % wordSize)
+	w := &(*b)[(i/wordSize)%blockWords]
+	old := *w
+	*w = old | bit
+	return old&bit != 0
+}
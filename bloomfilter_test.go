@@ -43,6 +43,9 @@ func TestSimple(t *testing.T) {
 		f := New(config.nbits, config.nhashes)
 		assert.GreaterOrEqual(t, f.NumBits(), config.nbits)
 		assert.LessOrEqual(t, f.NumBits(), config.nbits+BlockBits)
+		assert.Equal(t, f.NumBits()/8, f.Size())
+		assert.EqualValues(t, f.NumBits()/BlockBits, f.NumBlocks())
+		assert.Equal(t, config.nhashes, f.K())
 		assert.True(t, f.Empty())
 
 		for _, k := range keys {
@@ -121,6 +124,25 @@ func TestUse(t *testing.T) {
 	t.Logf("FPR = %.5f\n", fpr)
 }
 
+func TestFilterTestAndAdd(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(2000, 0x7e57a)
+	hashes = append(hashes, hashes[:100]...)
+
+	for _, premix := range []bool{false, true} {
+		f := New(1<<16, 6)
+		ref := New(1<<16, 6)
+		f.premix, ref.premix = premix, premix
+
+		for _, h := range hashes {
+			assert.Equal(t, ref.Has(h), f.TestAndAdd(h))
+			ref.Add(h)
+		}
+		assert.True(t, f.Equals(ref))
+	}
+}
+
 // Test robustness against 32-bit hash functions.
 func TestHash32(t *testing.T) {
 	t.Parallel()
@@ -166,13 +188,18 @@ func TestReducerange(t *testing.T) {
 	t.Parallel()
 
 	for i := 0; i < 40000; i++ {
-		m := rand.Uint32()
+		m := uint64(rand.Uint32())
 		j := reducerange(rand.Uint32(), m)
 		if m == 0 {
-			assert.Equal(t, j, 0)
+			assert.EqualValues(t, j, 0)
 		}
 		assert.Less(t, j, m)
 	}
+
+	// The maximum number of blocks.
+	for _, i := range []uint32{0, 1, 1 << 31, 1<<32 - 1} {
+		assert.EqualValues(t, i, reducerange(i, 1<<32))
+	}
 }
 
 func TestCardinality(t *testing.T) {
@@ -291,10 +318,41 @@ func TestUnion(t *testing.T) {
 	g.Union(f)
 	assert.Equal(t, u, g)
 
-	assert.Panics(t, func() { f.Union(New(n, 4)) })
 	assert.Panics(t, func() { f.Union(New(n+BlockBits, 5)) })
 }
 
+func TestUnionDifferentK(t *testing.T) {
+	t.Parallel()
+
+	const n = 1e4
+	hashes := randomU64(2*n, 0x1c4a9)
+
+	f := New(20*n, 9)
+	g := New(20*n, 6)
+	for _, h := range hashes[:n/2] {
+		f.Add(h)
+	}
+	for _, h := range hashes[n/2 : n] {
+		g.Add(h)
+	}
+
+	fprBefore := f.FPRate(n)
+	f.Union(g)
+	assert.Equal(t, 6, f.k)
+	assert.Greater(t, f.FPRate(n), fprBefore)
+
+	for _, h := range hashes[:n] {
+		assert.True(t, f.Has(h))
+	}
+
+	// The other way around.
+	g.Union(New(20*n, 12))
+	assert.Equal(t, 6, g.k)
+	for _, h := range hashes[n/2 : n] {
+		assert.True(t, g.Has(h))
+	}
+}
+
 func randomU64(n int, seed int64) []uint64 {
 	r := rand.New(rand.NewSource(seed))
 	p := make([]uint64, n)
@@ -334,3 +392,277 @@ func TestBlockLayout(t *testing.T) {
 	expect := "aa7f8c411600fa387f0c10641eab428a7ed2f27a86171ac69f0e2087b2aa9140"
 	assert.Equal(t, expect, hex.EncodeToString(h.Sum(nil)))
 }
+
+// Sequential integers are not hashes, but premixing makes them usable.
+func TestPremix(t *testing.T) {
+	t.Parallel()
+
+	const n = 10000
+
+	cfg := Config{Capacity: n, FPRate: .01, Premix: true}
+	f := NewOptimized(cfg)
+	for i := uint64(0); i < n; i++ {
+		f.Add(i)
+	}
+
+	fp := 0
+	for i := uint64(n); i < 2*n; i++ {
+		if f.Has(i) {
+			fp++
+		}
+	}
+	fpr := float64(fp) / n
+	t.Logf("FPR = %f", fpr)
+	assert.Less(t, fpr, 1.5*cfg.FPRate)
+
+	cfg.Premix = false
+	assert.False(t, f.Equals(NewOptimized(cfg)))
+	assert.Panics(t, func() { f.Union(NewOptimized(cfg)) })
+}
+
+func TestDiffIndex(t *testing.T) {
+	t.Parallel()
+
+	f, g := New(8*BlockBits, 3), New(8*BlockBits, 3)
+	blk, word := f.DiffIndex(g)
+	assert.Equal(t, -1, blk)
+	assert.Equal(t, -1, word)
+	assert.True(t, f.Equals(g))
+	assert.True(t, f.EqualsConstantTime(g))
+
+	g.b[5][7] = 1
+	g.b[6][2] = 1
+	blk, word = f.DiffIndex(g)
+	assert.Equal(t, 5, blk)
+	assert.Equal(t, 7, word)
+	assert.False(t, f.Equals(g))
+	assert.False(t, f.EqualsConstantTime(g))
+
+	blk, word = f.DiffIndex(New(4*BlockBits, 3))
+	assert.Equal(t, 4, blk)
+	assert.Equal(t, 0, word)
+	assert.False(t, f.Equals(New(4*BlockBits, 3)))
+	assert.False(t, f.EqualsConstantTime(New(4*BlockBits, 3)))
+
+	// Parameters other than the bits are not compared.
+	blk, _ = f.DiffIndex(New(8*BlockBits, 5))
+	assert.Equal(t, -1, blk)
+	assert.False(t, f.Equals(New(8*BlockBits, 5)))
+	assert.False(t, f.EqualsConstantTime(New(8*BlockBits, 5)))
+}
+
+func TestHasConstantTime(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(4000, 0xc0175)
+	f := NewOptimized(Config{Capacity: 2000, FPRate: .01, Premix: true})
+	for _, h := range hashes[:2000] {
+		f.Add(h)
+	}
+	for _, h := range hashes {
+		assert.Equal(t, f.Has(h), f.HasConstantTime(h))
+	}
+}
+
+// Simulate the largest possible filter, where all keys that map to the same
+// block have the same lower half. In LayoutV0, they get correlated bits.
+func TestLayoutCorrelation(t *testing.T) {
+	t.Parallel()
+
+	const (
+		nkeys  = 20
+		ntests = 1e5
+	)
+	r := rand.New(rand.NewSource(0x1a70))
+	key := func() uint64 { return r.Uint64()<<32 | 0xdeadbeef }
+
+	fprs := make([]float64, 2)
+	for _, layout := range []Layout{LayoutV0, LayoutV1} {
+		f := NewOptimized(Config{
+			Capacity: nkeys,
+			FPRate:   .001,
+			MaxBits:  BlockBits,
+			Layout:   layout,
+		})
+		for i := 0; i < nkeys; i++ {
+			f.Add(key())
+		}
+
+		fp := 0
+		for i := 0; i < ntests; i++ {
+			if f.Has(key()) {
+				fp++
+			}
+		}
+		fprs[layout] = float64(fp) / ntests
+		t.Logf("layout %d: FPR = %f, estimate %f", layout, fprs[layout], f.FPRate(nkeys))
+	}
+
+	assert.Greater(t, fprs[LayoutV0], 3*fprs[LayoutV1])
+	assert.Panics(t, func() { NewOptimized(Config{Capacity: 1, FPRate: .1, Layout: 2}) })
+}
+
+func TestEstimateUnionCardinality(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(3000, 0x0c0)
+	f, g := New(1<<15, 4), New(1<<15, 4)
+	for _, h := range hashes[:2000] {
+		f.Add(h)
+	}
+	for _, h := range hashes[1000:] {
+		g.Add(h)
+	}
+	fb, gb := append([]block(nil), f.b...), append([]block(nil), g.b...)
+
+	est := EstimateUnionCardinality(f, g)
+	assert.Equal(t, fb, f.b)
+	assert.Equal(t, gb, g.b)
+	assert.InDelta(t, 3000, est, 150)
+
+	f.Union(g)
+	assert.Equal(t, f.Cardinality(), est)
+
+	assert.Panics(t, func() { EstimateUnionCardinality(f, New(1<<16, 4)) })
+}
+
+func TestIntersectionJaccard(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(4000, 0x1ace)
+	f, g := New(1<<16, 5), New(1<<16, 5)
+	assert.Equal(t, 0.0, Jaccard(f, g))
+
+	// 1000 in f only, 2000 shared, 1000 in g only.
+	for _, h := range hashes[:3000] {
+		f.Add(h)
+	}
+	for _, h := range hashes[1000:] {
+		g.Add(h)
+	}
+
+	assert.InDelta(t, 2000, EstimateIntersectionCardinality(f, g), 150)
+	assert.InDelta(t, .5, Jaccard(f, g), .05)
+	assert.InDelta(t, 1, Jaccard(f, f), 1e-9)
+
+	h := New(1<<16, 5)
+	for _, x := range randomU64(1000, 0x1acf) {
+		h.Add(x)
+	}
+	assert.InDelta(t, 0, EstimateIntersectionCardinality(f, h), 100)
+	assert.InDelta(t, 0, Jaccard(f, h), .03)
+}
+
+func TestUnionOfIntersectOf(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(3000, 0x0f)
+	newFilter := func(hashes []uint64) *Filter {
+		f := New(1<<15, 4)
+		f.premix = true
+		for _, h := range hashes {
+			f.Add(h)
+		}
+		return f
+	}
+	f, g := newFilter(hashes[:2000]), newFilter(hashes[1000:])
+	fb, gb := append([]block(nil), f.b...), append([]block(nil), g.b...)
+
+	union := newFilter(hashes[:2000])
+	union.Union(g)
+	inter := newFilter(hashes[:2000])
+	inter.Intersect(g)
+
+	u := UnionOf(nil, f, g)
+	assert.True(t, union.Equals(u))
+	i := IntersectOf(nil, f, g)
+	assert.True(t, inter.Equals(i))
+	assert.Equal(t, fb, f.b)
+	assert.Equal(t, gb, g.b)
+
+	// Reused destination.
+	dst := New(1<<15, 7)
+	assert.Equal(t, dst, IntersectOf(dst, f, g))
+	assert.True(t, inter.Equals(dst))
+	assert.Equal(t, dst, UnionOf(dst, f, g))
+	assert.True(t, union.Equals(dst))
+
+	// Aliased destination.
+	assert.True(t, union.Equals(UnionOf(g, f, g)))
+	assert.Equal(t, fb, f.b)
+	assert.True(t, union.Equals(g))
+
+	assert.Panics(t, func() { UnionOf(New(1<<16, 4), f, g) })
+}
+
+func TestFold(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(2000, 0xf01d)
+	for _, premix := range []bool{false, true} {
+		f := New(1024*BlockBits, 6)
+		f.premix = premix
+		for _, h := range hashes[:1000] {
+			f.Add(h)
+		}
+		nblocks := len(f.b)
+		card := f.Cardinality()
+
+		f.Fold(1)
+		assert.Len(t, f.b, nblocks)
+
+		f.Fold(8)
+		assert.Len(t, f.b, nblocks/8)
+		for _, h := range hashes[:1000] {
+			assert.True(t, f.Has(h))
+		}
+		assert.InDelta(t, card, f.Cardinality(), 20)
+
+		// The folded filter is the same as one built at the smaller size.
+		g := New(f.NumBits(), f.k)
+		g.premix = premix
+		for _, h := range hashes[:1000] {
+			g.Add(h)
+		}
+		assert.True(t, g.Equals(f))
+
+		assert.Panics(t, func() { f.Fold(0) })
+		assert.Panics(t, func() { f.Fold(len(f.b) + 1) })
+	}
+}
+
+func TestUnionFolded(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(2000, 0xf01e)
+	newFilter := func(nblocks uint64, hashes []uint64) *Filter {
+		f := New(nblocks*BlockBits, 5)
+		for _, h := range hashes {
+			f.Add(h)
+		}
+		return f
+	}
+	ref := newFilter(64, hashes)
+
+	f := newFilter(64, hashes[:1000])
+	g := newFilter(256, hashes[1000:])
+	gb := append([]block(nil), g.b...)
+	f.UnionFolded(g)
+	assert.True(t, ref.Equals(f))
+	assert.Equal(t, gb, g.b)
+
+	f = newFilter(512, hashes[:1000])
+	g = newFilter(64, hashes[1000:])
+	f.UnionFolded(g)
+	assert.True(t, ref.Equals(f))
+
+	f = newFilter(64, hashes[:1000])
+	f.UnionFolded(newFilter(64, hashes[1000:]))
+	assert.True(t, ref.Equals(f))
+
+	assert.Panics(t, func() { f.UnionFolded(New(96*BlockBits, 5)) })
+	g = New(16*BlockBits, 5)
+	g.premix = true
+	assert.Panics(t, func() { f.UnionFolded(g) })
+	assert.Len(t, f.b, 64, "modified before panicking")
+}
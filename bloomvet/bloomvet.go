@@ -0,0 +1,389 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bloomvet defines an Analyzer that reports common misuse of
+// the blobloom package.
+//
+// It reports:
+//   - constants and integer conversions passed as hash values to Add or Has,
+//     which indicate that raw IDs are used instead of hashes;
+//   - Union or Intersect of filters that were constructed in the same function
+//     with different numbers of bits, and Intersect of filters constructed
+//     with different numbers of hashes, which panic at run time;
+//   - random hash/maphash seeds in packages that dump Bloom filters:
+//     such seeds cannot be stored, so a loaded filter is useless.
+package bloomvet
+
+import (
+	"bytes"
+	"go/ast"
+	"go/constant"
+	"go/printer"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const pkgPath = "github.com/greatroar/blobloom"
+
+// Analyzer reports misuse of the blobloom package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "bloomvet",
+	Doc:      "report common misuse of github.com/greatroar/blobloom",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	var (
+		dumps    bool
+		seeds    []*ast.CallExpr
+		premixed = premixedFilters(pass)
+	)
+
+	insp.Nodes([]ast.Node{(*ast.FuncDecl)(nil), (*ast.CallExpr)(nil)},
+		func(n ast.Node, push bool) bool {
+			if !push {
+				return true
+			}
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if n.Body != nil {
+					checkBinops(pass, n.Body)
+				}
+			case *ast.CallExpr:
+				switch fn := typeutil.Callee(pass.TypesInfo, n).(type) {
+				case *types.Func:
+					switch name := fn.FullName(); name {
+					case pkgPath + ".Dump", pkgPath + ".DumpSync":
+						dumps = true
+					case "hash/maphash.MakeSeed":
+						seeds = append(seeds, n)
+					default:
+						if isHashMethod(fn) && len(n.Args) == 1 && !premixed[receiver(pass, n)] {
+							checkHashArg(pass, fn, n.Args[0])
+						}
+					}
+				}
+			}
+			return true
+		})
+
+	if dumps {
+		for _, call := range seeds {
+			pass.Reportf(call.Pos(), "random maphash seed in a package that dumps Bloom filters: "+
+				"the seed cannot be stored, so loaded filters will not match")
+		}
+	}
+	return nil, nil
+}
+
+// isHashMethod reports whether fn is Add or Has on a blobloom filter.
+func isHashMethod(fn *types.Func) bool {
+	if fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() == nil {
+		return false
+	}
+	switch fn.Name() {
+	case "Add", "Has", "HasConstantTime":
+		return true
+	}
+	return false
+}
+
+// receiver returns the variable that the method call is made on,
+// or nil if it is not a variable.
+func receiver(pass *analysis.Pass, call *ast.CallExpr) types.Object {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	id, ok := ast.Unparen(sel.X).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	return pass.TypesInfo.ObjectOf(id)
+}
+
+// premixedFilters returns the variables that are assigned a filter
+// constructed with Config.Premix anywhere in the package.
+// Add and Has may be passed IDs instead of hashes for such filters.
+func premixedFilters(pass *analysis.Pass) map[types.Object]bool {
+	premixed := make(map[types.Object]bool)
+	record := func(lhs []*ast.Ident, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, x := range rhs {
+			call, ok := ast.Unparen(x).(*ast.CallExpr)
+			if ok && isConstructor(pass, call) && mayPremix(pass, call) {
+				premixed[pass.TypesInfo.ObjectOf(lhs[i])] = true
+			}
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				var lhs []*ast.Ident
+				for _, x := range n.Lhs {
+					id, _ := x.(*ast.Ident)
+					lhs = append(lhs, id)
+				}
+				record(lhs, n.Rhs)
+			case *ast.ValueSpec:
+				record(n.Names, n.Values)
+			}
+			return true
+		})
+	}
+	delete(premixed, nil)
+	return premixed
+}
+
+// mayPremix reports whether the constructor call may set Config.Premix.
+// Only a Config literal that leaves Premix out or sets it to false is
+// known not to.
+func mayPremix(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if len(call.Args) != 1 {
+		return false // New or NewSync.
+	}
+	lit, ok := ast.Unparen(call.Args[0]).(*ast.CompositeLit)
+	if !ok {
+		return true
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Premix" {
+			continue
+		}
+		v := pass.TypesInfo.Types[kv.Value].Value
+		return v == nil || constant.BoolVal(v)
+	}
+	return false
+}
+
+func checkHashArg(pass *analysis.Pass, fn *types.Func, arg ast.Expr) {
+	arg = ast.Unparen(arg)
+	tv := pass.TypesInfo.Types[arg]
+
+	if tv.Value != nil {
+		pass.Reportf(arg.Pos(), "constant %s passed to %s is not a hash value", tv.Value, fn.Name())
+		return
+	}
+
+	conv, ok := arg.(*ast.CallExpr)
+	if !ok || len(conv.Args) != 1 || !pass.TypesInfo.Types[conv.Fun].IsType() {
+		return
+	}
+	from := pass.TypesInfo.TypeOf(conv.Args[0])
+	if b, ok := from.Underlying().(*types.Basic); ok && b.Info()&types.IsInteger != 0 {
+		pass.Reportf(arg.Pos(), "conversion from %s passed to %s looks like an ID, not a hash value; "+
+			"hash it or set Config.Premix", from, fn.Name())
+	}
+}
+
+// checkBinops reports calls to Union and Intersect in body on filters
+// that were constructed with different arguments in body, such that the
+// call panics: a different number of bits, or for Intersect, a different
+// number of hashes. Union of filters with different numbers of hashes is
+// allowed.
+func checkBinops(pass *analysis.Pass, body *ast.BlockStmt) {
+	// Maps variables to the constructor call expressions assigned to them.
+	ctors := make(map[types.Object]*ast.CallExpr)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				break
+			}
+			for i, rhs := range n.Rhs {
+				id, ok := n.Lhs[i].(*ast.Ident)
+				if !ok {
+					continue
+				}
+				obj := pass.TypesInfo.ObjectOf(id)
+				if call, ok := ast.Unparen(rhs).(*ast.CallExpr); ok && isConstructor(pass, call) {
+					ctors[obj] = call
+				} else {
+					delete(ctors, obj)
+				}
+			}
+
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || len(n.Args) != 1 {
+				break
+			}
+			fn, ok := typeutil.Callee(pass.TypesInfo, n).(*types.Func)
+			if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+				break
+			}
+			if fn.Name() != "Union" && fn.Name() != "Intersect" {
+				break
+			}
+
+			f, g := ctorOf(pass, ctors, sel.X), ctorOf(pass, ctors, n.Args[0])
+			if f == nil || g == nil || len(f.Args) != len(g.Args) {
+				break
+			}
+			var panics bool
+			switch info := pass.TypesInfo; len(f.Args) {
+			case 1: // NewOptimized or NewSyncOptimized: any change may resize.
+				panics = differ(info, f.Args[0], g.Args[0])
+			case 2: // New or NewSync.
+				panics = differ(info, f.Args[0], g.Args[0]) ||
+					fn.Name() == "Intersect" && differ(info, f.Args[1], g.Args[1])
+			}
+			if panics {
+				pass.Reportf(n.Pos(), "%s of filters constructed with different parameters: %s and %s",
+					fn.Name(), render(pass.Fset, f), render(pass.Fset, g))
+			}
+		}
+		return true
+	})
+}
+
+func ctorOf(pass *analysis.Pass, ctors map[types.Object]*ast.CallExpr, x ast.Expr) *ast.CallExpr {
+	id, ok := ast.Unparen(x).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	return ctors[pass.TypesInfo.ObjectOf(id)]
+}
+
+func isConstructor(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return false
+	}
+	switch fn.Name() {
+	case "New", "NewOptimized", "NewSync", "NewSyncOptimized":
+		return true
+	}
+	return false
+}
+
+// differ reports whether x and y are known to have different values:
+// unequal constants, different variables, or Config literals with such
+// fields. Other expressions are assumed to be equal, to avoid false
+// positives.
+func differ(info *types.Info, x, y ast.Expr) bool {
+	x, y = ast.Unparen(x), ast.Unparen(y)
+	if vx, vy := info.Types[x].Value, info.Types[y].Value; vx != nil || vy != nil {
+		return vx != nil && vy != nil && !constant.Compare(vx, token.EQL, vy)
+	}
+	if ox, oy := variable(info, x), variable(info, y); ox != nil && oy != nil {
+		return ox != oy
+	}
+
+	lx, ok := x.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	ly, ok := y.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	fx, fy := fields(lx), fields(ly)
+	if fx == nil || fy == nil {
+		return false
+	}
+	for name, vx := range fx {
+		if vy, ok := fy[name]; ok && differ(info, vx, vy) || !ok && nonzero(info, vx) {
+			return true
+		}
+	}
+	for name, vy := range fy {
+		if _, ok := fx[name]; !ok && nonzero(info, vy) {
+			return true
+		}
+	}
+	return false
+}
+
+// variable returns the variable that x refers to, if any.
+func variable(info *types.Info, x ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch x := x.(type) {
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		if _, ok := info.Selections[x]; ok {
+			return nil // Field of a value that may vary.
+		}
+		id = x.Sel // Qualified identifier.
+	}
+	if id == nil {
+		return nil
+	}
+	v, _ := info.ObjectOf(id).(*types.Var)
+	return v
+}
+
+// fields maps the field names in a keyed struct literal to their values.
+// It returns nil for literals with unkeyed fields.
+func fields(lit *ast.CompositeLit) map[string]ast.Expr {
+	m := make(map[string]ast.Expr, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		m[key.Name] = kv.Value
+	}
+	return m
+}
+
+// nonzero reports whether x is a constant other than the zero value,
+// so that it differs from a field left out of a struct literal.
+func nonzero(info *types.Info, x ast.Expr) bool {
+	v := info.Types[x].Value
+	if v == nil {
+		return false
+	}
+	switch v.Kind() {
+	case constant.Bool:
+		return constant.BoolVal(v)
+	case constant.String:
+		return constant.StringVal(v) != ""
+	case constant.Int, constant.Float, constant.Complex:
+		return constant.Sign(v) != 0
+	}
+	return false
+}
+
+func render(fset *token.FileSet, x ast.Expr) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, fset, x)
+	return buf.String()
+}
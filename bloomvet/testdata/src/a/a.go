@@ -0,0 +1,85 @@
+package a
+
+import (
+	"hash/maphash"
+	"io"
+
+	"github.com/greatroar/blobloom"
+)
+
+func hashes(ids []uint32, h maphash.Hash) {
+	f := blobloom.New(1<<20, 5)
+
+	f.Add(42)               // want `constant 42 passed to Add is not a hash value`
+	f.Has((1 << 40) + 1)    // want `constant 1099511627777 passed to Has is not a hash value`
+	f.Add(uint64(ids[0]))   // want `conversion from uint32 passed to Add looks like an ID`
+	f.Has(uint64(len(ids))) // want `conversion from int passed to Has looks like an ID`
+
+	f.Add(h.Sum64())
+	f.Has(h.Sum64())
+}
+
+func union() {
+	f := blobloom.NewOptimized(blobloom.Config{Capacity: 1e4, FPRate: .01})
+	g := blobloom.NewOptimized(blobloom.Config{Capacity: 1e4, FPRate: .01})
+	h := blobloom.NewOptimized(blobloom.Config{Capacity: 1e5, FPRate: .01})
+
+	f.Union(g)
+	f.Union(h)     // want `Union of filters constructed with different parameters`
+	g.Intersect(h) // want `Intersect of filters constructed with different parameters`
+
+	h = blobloom.New(1<<20, 5)
+	i := blobloom.New(1<<20, 5)
+	h.Union(i)
+	h = other()
+	h.Union(i)
+}
+
+func unionArgs(m, n uint64, j, k int) {
+	const mib = 1 << 23
+
+	// Equal values written differently.
+	f := blobloom.New(1<<23, 5)
+	g := blobloom.New(mib, 5)
+	f.Intersect(g)
+	f = blobloom.NewOptimized(blobloom.Config{Capacity: 1e4, FPRate: .01, Premix: false})
+	g = blobloom.NewOptimized(blobloom.Config{FPRate: 0.01, Capacity: 10000})
+	f.Intersect(g)
+
+	// Union allows different numbers of hashes, Intersect doesn't.
+	f = blobloom.New(m, j)
+	g = blobloom.New(m, k)
+	f.Union(g)
+	f.Intersect(g) // want `Intersect of filters constructed with different parameters: blobloom.New\(m, j\) and blobloom.New\(m, k\)`
+
+	// Different variables and non-constant expressions.
+	f = blobloom.New(m, k)
+	g = blobloom.New(n, k)
+	f.Union(g) // want `Union of filters constructed with different parameters`
+	f = blobloom.New(m+1, k)
+	g = blobloom.New(m+1, k)
+	f.Union(g)
+	f = blobloom.NewOptimized(blobloom.Config{Capacity: m, FPRate: .01})
+	g = blobloom.NewOptimized(blobloom.Config{Capacity: n, FPRate: .01})
+	f.Union(g) // want `Union of filters constructed with different parameters`
+}
+
+func premixed(ids []uint32, config blobloom.Config) {
+	f := blobloom.NewOptimized(blobloom.Config{Capacity: 1e4, FPRate: .01, Premix: true})
+	f.Add(uint64(ids[0]))
+	f.Has(uint64(ids[1]))
+
+	var g = blobloom.NewOptimized(config)
+	g.Add(uint64(ids[0]))
+
+	h := blobloom.NewOptimized(blobloom.Config{Capacity: 1e4, FPRate: .01, Premix: false})
+	h.Add(uint64(ids[0])) // want `conversion from uint32 passed to Add looks like an ID`
+}
+
+func other() *blobloom.Filter { return nil }
+
+func dump(w io.Writer, f *blobloom.Filter) {
+	seed := maphash.MakeSeed() // want `random maphash seed in a package that dumps Bloom filters`
+	_ = seed
+	blobloom.Dump(w, f, "")
+}
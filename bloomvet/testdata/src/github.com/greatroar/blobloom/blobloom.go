@@ -0,0 +1,20 @@
+// Package blobloom is a stub of the real package for testing.
+package blobloom
+
+import "io"
+
+type Config struct {
+	Capacity uint64
+	FPRate   float64
+	Premix   bool
+}
+
+type Filter struct{}
+
+func New(nbits uint64, nhashes int) *Filter                { return nil }
+func NewOptimized(config Config) *Filter                   { return nil }
+func (f *Filter) Add(h uint64)                             {}
+func (f *Filter) Has(h uint64) bool                        { return false }
+func (f *Filter) Union(g *Filter)                          {}
+func (f *Filter) Intersect(g *Filter)                      {}
+func Dump(w io.Writer, f *Filter, c string) (int64, error) { return 0, nil }
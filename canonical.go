@@ -0,0 +1,208 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Blocklists are typically built by one party and queried by another.
+// If the two normalize hostnames or URLs differently, keys that are in
+// the list will be reported as absent. The functions in this file provide
+// a canonical form to hash on both sides.
+
+// CanonicalHost returns the canonical form of a hostname: lowercase,
+// without a trailing dot, with internationalized labels encoded in
+// punycode ("xn--..."). IP addresses are returned in their standard form.
+//
+// Lowercasing uses simple Unicode case folding. CanonicalHost does not
+// implement the full IDNA mapping, so exotic hostnames may differ from
+// what a browser would produce, but producers and consumers that both use
+// CanonicalHost agree.
+func CanonicalHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+
+	if !utf8.ValidString(host) {
+		return "", fmt.Errorf("blobloom: invalid UTF-8 in hostname %q", host)
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", fmt.Errorf("blobloom: empty hostname")
+	}
+
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if label == "" {
+			return "", fmt.Errorf("blobloom: empty label in hostname %q", host)
+		}
+		if !isASCII(label) {
+			label = "xn--" + punycode(label)
+		}
+		if len(label) > 63 {
+			return "", fmt.Errorf("blobloom: label too long in hostname %q", host)
+		}
+		labels[i] = label
+	}
+	return strings.Join(labels, "."), nil
+}
+
+var defaultPorts = map[string]string{
+	"ftp":   "21",
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+}
+
+// CanonicalURL returns the canonical form of an absolute URL. The scheme
+// and host are lowercased, the host is canonicalized by CanonicalHost,
+// default ports are removed, as are user information and the fragment,
+// and an empty path is replaced by "/".
+func CanonicalURL(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", fmt.Errorf("blobloom: %w", err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("blobloom: URL %q has no host", rawurl)
+	}
+
+	host, err := CanonicalHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	switch {
+	case port != "" && port != defaultPorts[u.Scheme]:
+		host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		host = "[" + host + "]"
+	}
+
+	u.Host = host
+	u.User = nil
+	u.Fragment = ""
+	if u.Path == "" {
+		u.Path, u.RawPath = "/", ""
+	}
+	return u.String(), nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Punycode parameters, from RFC 3492.
+const (
+	punyBase        = 36
+	punyTmin        = 1
+	punyTmax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// punycode encodes s according to RFC 3492, without the "xn--" prefix.
+func punycode(s string) string {
+	runes := []rune(s)
+	out := make([]byte, 0, 2*len(s))
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	b := len(out)
+	if b > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := rune(punyInitialN), 0, punyInitialBias
+	for h := b; h < len(runes); {
+		m := rune(utf8.MaxRune + 1)
+		for _, r := range runes {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+		delta += int(m-n) * (h + 1)
+		n = m
+
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := k - bias
+				if t < punyTmin {
+					t = punyTmin
+				} else if t > punyTmax {
+					t = punyTmax
+				}
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyDigit(q))
+			bias = punyAdapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return string(out)
+}
+
+func punyAdapt(delta, npoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / npoints
+
+	k := 0
+	for delta > (punyBase-punyTmin)*punyTmax/2 {
+		delta /= punyBase - punyTmin
+		k += punyBase
+	}
+	return k + (punyBase-punyTmin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return 'a' + byte(d)
+	}
+	return '0' + byte(d-26)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalHost(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct{ in, out string }{
+		{"Example.COM.", "example.com"},
+		{"bücher.example", "xn--bcher-kva.example"},
+		{"MÜNCHEN.de", "xn--mnchen-3ya.de"},
+		{"xn--mnchen-3ya.de", "xn--mnchen-3ya.de"},
+		// Examples from RFC 3492, section 7.1.
+		{"他们为什么不说中文", "xn--ihqwcrb4cv8a8dqg056pqjye"},
+		{"почемужеонинеговорятпорусски", "xn--b1abfaaepdrnnbgefbadotcwatmq2g4l"},
+		{"3年b組金八先生", "xn--3b-ww4c5e180e575a65lsy2b"},
+		{"192.0.2.1", "192.0.2.1"},
+		{"[2001:DB8::0:1]", "2001:db8::1"},
+	} {
+		out, err := CanonicalHost(c.in)
+		assert.NoError(t, err, c.in)
+		assert.Equal(t, c.out, out, c.in)
+	}
+
+	for _, in := range []string{"", ".", "a..b", "\xff.com"} {
+		_, err := CanonicalHost(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct{ in, out string }{
+		{"HTTP://Example.com", "http://example.com/"},
+		{"http://example.com:80/a?b=c#frag", "http://example.com/a?b=c"},
+		{"https://user:pw@bücher.example:443/", "https://xn--bcher-kva.example/"},
+		{"https://example.com:8443/x", "https://example.com:8443/x"},
+		{"http://[2001:db8::1]:80/", "http://[2001:db8::1]/"},
+		{"http://[2001:db8::1]:8080/", "http://[2001:db8::1]:8080/"},
+	} {
+		out, err := CanonicalURL(c.in)
+		assert.NoError(t, err, c.in)
+		assert.Equal(t, c.out, out, c.in)
+	}
+
+	for _, in := range []string{"example.com/path", "http://a..b/", "%"} {
+		_, err := CanonicalURL(in)
+		assert.Error(t, err, in)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// A Cascade combines filters into a single membership decision, such as
+// a blocklist with an allowlist of exceptions.
+//
+// Each filter in a Cascade is added as an include or exclude stage.
+// A key is a member of the Cascade if the last stage whose filter has it
+// is an include stage. For a blocklist with exceptions, add the blocklist
+// with Include, then the allowlist with Exclude.
+//
+// Both kinds of stage can err. A false positive in an include stage can
+// make a key a member; a false positive in a later exclude stage can drop
+// a true member. FPRate and FNRate quantify both.
+type Cascade struct {
+	stages []cascadeStage
+}
+
+type cascadeStage struct {
+	name    string
+	f       *Filter
+	include bool
+}
+
+// Include appends a stage that makes keys in f members. It returns c.
+func (c *Cascade) Include(name string, f *Filter) *Cascade {
+	c.stages = append(c.stages, cascadeStage{name, f, true})
+	return c
+}
+
+// Exclude appends a stage that makes keys in f non-members. It returns c.
+func (c *Cascade) Exclude(name string, f *Filter) *Cascade {
+	c.stages = append(c.stages, cascadeStage{name, f, false})
+	return c
+}
+
+// Has reports whether a key with hash value h is a member of c.
+func (c *Cascade) Has(h uint64) bool {
+	for i := len(c.stages) - 1; i >= 0; i-- {
+		if s := &c.stages[i]; s.f.Has(h) {
+			return s.include
+		}
+	}
+	return false
+}
+
+// Explain returns a human-readable account of how Has decides for h,
+// listing each stage with its answer and marking the deciding stage.
+func (c *Cascade) Explain(h uint64) string {
+	var sb strings.Builder
+	decided := false
+	for i := len(c.stages) - 1; i >= 0; i-- {
+		s := &c.stages[i]
+		kind := "exclude"
+		if s.include {
+			kind = "include"
+		}
+		has := s.f.Has(h)
+		fmt.Fprintf(&sb, "%d %s (%s): %t", i, s.name, kind, has)
+		if has && !decided {
+			fmt.Fprintf(&sb, " => %t", s.include)
+			decided = true
+		}
+		sb.WriteByte('\n')
+	}
+	if !decided {
+		sb.WriteString("no stage matched => false\n")
+	}
+	return sb.String()
+}
+
+// stageFPRates returns the estimated false positive rates of the stages,
+// based on their estimated cardinalities.
+func (c *Cascade) stageFPRates() []float64 {
+	p := make([]float64, len(c.stages))
+	for i, s := range c.stages {
+		// Cardinality is +Inf for a filter with a full block, which
+		// cannot be converted to uint64. Clamp it to the number of bits,
+		// where the estimated false positive rate is close to one.
+		nkeys := math.Min(s.f.Cardinality(), float64(s.f.NumBits()))
+		p[i] = s.f.FPRate(uint64(nkeys))
+	}
+	return p
+}
+
+// FPRate returns the estimated probability that Has reports a key as a
+// member when the key is in none of the underlying sets.
+func (c *Cascade) FPRate() float64 {
+	p := c.stageFPRates()
+	// P(stage i is the last stage to match, and it is an include stage).
+	fpr, none := 0.0, 1.0
+	for i := len(p) - 1; i >= 0; i-- {
+		if c.stages[i].include {
+			fpr += none * p[i]
+		}
+		none *= 1 - p[i]
+	}
+	return fpr
+}
+
+// FNRate returns the estimated probability that Has reports a key as
+// a non-member when the key is in the set of the first include stage
+// and in none of the later sets. This is the worst case over all include
+// stages, since it is exposed to false positives of all exclude stages.
+func (c *Cascade) FNRate() float64 {
+	p := c.stageFPRates()
+	first := len(p)
+	for i, s := range c.stages {
+		if s.include {
+			first = i
+			break
+		}
+	}
+	// P(some later exclude stage is the last stage to match).
+	fnr, none := 0.0, 1.0
+	for i := len(p) - 1; i > first; i-- {
+		if !c.stages[i].include {
+			fnr += none * p[i]
+		}
+		none *= 1 - p[i]
+	}
+	return fnr
+}
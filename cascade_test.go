@@ -0,0 +1,83 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCascade(t *testing.T) {
+	t.Parallel()
+
+	const n = 5000
+	hashes := randomU64(3*n, 0xca5cade)
+	blocked, allowed, others := hashes[:n], hashes[n:n+n/10], hashes[2*n:]
+
+	config := Config{Capacity: n, FPRate: 1e-2}
+	blocklist, allowlist := NewOptimized(config), NewOptimized(config)
+	for _, h := range blocked {
+		blocklist.Add(h)
+	}
+	for _, h := range allowed {
+		// Exceptions are in both lists.
+		blocklist.Add(h)
+		allowlist.Add(h)
+	}
+
+	c := new(Cascade).Include("blocklist", blocklist).Exclude("allowlist", allowlist)
+
+	for _, h := range allowed {
+		assert.False(t, c.Has(h))
+	}
+	fn := 0
+	for _, h := range blocked {
+		if !c.Has(h) {
+			fn++
+		}
+	}
+	fp := 0
+	for _, h := range others {
+		if c.Has(h) {
+			fp++
+		}
+	}
+
+	t.Logf("FNR = %f, estimate %f", float64(fn)/n, c.FNRate())
+	t.Logf("FPR = %f, estimate %f", float64(fp)/n, c.FPRate())
+	assert.InDelta(t, c.FNRate(), float64(fn)/n, .01)
+	assert.InDelta(t, c.FPRate(), float64(fp)/n, .01)
+	assert.Less(t, c.FNRate(), c.FPRate())
+
+	e := c.Explain(allowed[0])
+	assert.Equal(t, "1 allowlist (exclude): true => false\n0 blocklist (include): true\n", e)
+	e = c.Explain(blocked[0])
+	assert.True(t, strings.HasSuffix(e, "0 blocklist (include): true => true\n"), e)
+
+	// A full block makes the Cardinality of a stage +Inf.
+	full := NewOptimized(config)
+	for i := range full.b[0] {
+		full.b[0][i] = ^uint32(0)
+	}
+	c = new(Cascade).Include("full", full)
+	assert.True(t, math.IsInf(full.Cardinality(), 1))
+	assert.InDelta(t, 1, c.FPRate(), .01)
+
+	assert.False(t, new(Cascade).Has(0))
+	assert.Equal(t, "no stage matched => false\n", new(Cascade).Explain(0))
+}
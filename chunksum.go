@@ -0,0 +1,201 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"sync/atomic"
+)
+
+// Number of blocks covered by each checksum in the chunk checksum section
+// of a dump (256KiB).
+const chunkSumBlocks = 4096
+
+// A chunkSummer computes the CRC-32C of each chunk of chunkSumBlocks
+// blocks, as the blocks are passed to it one at a time.
+type chunkSummer struct {
+	sums []uint32
+	crc  uint32
+	n    int // Blocks in the current chunk.
+}
+
+func (s *chunkSummer) add(blk []byte) {
+	s.crc = crc32.Update(s.crc, castagnoli, blk)
+	if s.n++; s.n == chunkSumBlocks {
+		s.sums = append(s.sums, s.crc)
+		s.crc, s.n = 0, 0
+	}
+}
+
+// finish returns the checksums, including that of a final partial chunk.
+func (s *chunkSummer) finish() []uint32 {
+	if s.n > 0 {
+		s.sums = append(s.sums, s.crc)
+		s.crc, s.n = 0, 0
+	}
+	return s.sums
+}
+
+// numChunks returns the number of chunk checksums for nblocks blocks.
+func numChunks(nblocks uint64) uint64 {
+	return (nblocks + chunkSumBlocks - 1) / chunkSumBlocks
+}
+
+// chunkSumsSize is the size of the chunk checksum section for nblocks blocks:
+// four bytes per chunk, padded to a whole number of blocks.
+func chunkSumsSize(nblocks uint64) int64 {
+	const blockBytes = BlockBits / 8
+	return int64(4*numChunks(nblocks)+blockBytes-1) / blockBytes * blockBytes
+}
+
+// appendChunkSums appends the chunk checksum section for nblocks blocks,
+// holding sums, to buf.
+func appendChunkSums(buf []byte, sums []uint32, nblocks uint64) []byte {
+	off := len(buf)
+	buf = append(buf, make([]byte, chunkSumsSize(nblocks))...)
+	for i, sum := range sums {
+		binary.LittleEndian.PutUint32(buf[off+4*i:], sum)
+	}
+	return buf
+}
+
+// chunkSumsOK reports whether the blocks in p, which holds their
+// little-endian encoding, match the chunk checksum section.
+func chunkSumsOK(p, section []byte) bool {
+	const chunkBytes = chunkSumBlocks * BlockBits / 8
+	for i := 0; len(p) > 0; i++ {
+		n := chunkBytes
+		if n > len(p) {
+			n = len(p)
+		}
+		if crc32.Checksum(p[:n], castagnoli) != binary.LittleEndian.Uint32(section[4*i:]) {
+			return false
+		}
+		p = p[n:]
+	}
+	return true
+}
+
+// A BlockRange is the range of blocks with indices in [Start, End).
+type BlockRange struct {
+	Start, End uint64
+}
+
+// A CorruptBlocksError is returned by Loader.Load and Loader.LoadSync for
+// a dump in which blocks fail their chunk checksums, which localize the
+// corruption (see DumpOptions.ChunkChecksums). It wraps ErrChecksum.
+type CorruptBlocksError struct {
+	Blocks []BlockRange // Corrupt blocks, in increasing order.
+}
+
+func (e *CorruptBlocksError) Error() string {
+	var n uint64
+	for _, r := range e.Blocks {
+		n += r.End - r.Start
+	}
+	return fmt.Sprintf("blobloom: checksum mismatch in %d blocks, starting at block %d",
+		n, e.Blocks[0].Start)
+}
+
+func (e *CorruptBlocksError) Unwrap() error { return ErrChecksum }
+
+// A CorruptAction tells a Loader what to do with corrupt blocks.
+type CorruptAction uint8
+
+const (
+	// CorruptReject makes Load fail with a *CorruptBlocksError.
+	// This is the default.
+	CorruptReject CorruptAction = iota
+
+	// CorruptZero clears all bits in corrupt blocks. The filter then
+	// has false negatives for keys that were added to those blocks.
+	CorruptZero
+
+	// CorruptFill sets all bits in corrupt blocks. The filter then
+	// reports all keys that map to those blocks as present, so it keeps
+	// having no false negatives.
+	CorruptFill
+)
+
+// OnCorrupt sets what Load and LoadSync do with blocks that fail their
+// chunk checksums, for dumps that have them. It returns l.
+//
+// Unless action is CorruptReject, Load and LoadSync succeed and the corrupt
+// blocks are reported by CorruptBlocks. When loading into an existing
+// filter, the action also applies to that filter's own bits in those blocks.
+// The checksum over the entire dump (see DumpOptions.EmbedChecksum)
+// is not checked when corrupt blocks have been found.
+func (l *Loader) OnCorrupt(action CorruptAction) *Loader {
+	l.onCorrupt = action
+	return l
+}
+
+// CorruptBlocks returns the blocks that failed their chunk checksums
+// in the last call to Load or LoadSync.
+func (l *Loader) CorruptBlocks() []BlockRange { return l.corrupt }
+
+// verifyChunks reads the chunk checksum section, if the dump has one,
+// checks the checksums of the blocks loaded into b against it and
+// applies l.onCorrupt.
+func (l *Loader) verifyChunks(b []block) error {
+	if l.chunks == nil {
+		return nil
+	}
+	sums := l.chunks.finish()
+	section := make([]byte, chunkSumsSize(l.nblocks))
+	if err := l.fill(section); err != nil {
+		return err
+	}
+
+	l.corrupt = nil
+	for i, sum := range sums {
+		if binary.LittleEndian.Uint32(section[4*i:]) == sum {
+			continue
+		}
+		start := uint64(i) * chunkSumBlocks
+		end := start + chunkSumBlocks
+		if end > l.nblocks {
+			end = l.nblocks
+		}
+		if n := len(l.corrupt); n > 0 && l.corrupt[n-1].End == start {
+			l.corrupt[n-1].End = end
+		} else {
+			l.corrupt = append(l.corrupt, BlockRange{start, end})
+		}
+	}
+
+	switch {
+	case len(l.corrupt) == 0:
+		return nil
+	case l.onCorrupt == CorruptReject:
+		return &CorruptBlocksError{Blocks: l.corrupt}
+	}
+
+	var x uint32
+	if l.onCorrupt == CorruptFill {
+		x = ^uint32(0)
+	}
+	for _, r := range l.corrupt {
+		for i := r.Start; i < r.End; i++ {
+			// Atomic, because b may belong to a SyncFilter.
+			for j := range b[i] {
+				atomic.StoreUint32(&b[i][j], x)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkChecksums(t *testing.T) {
+	t.Parallel()
+
+	const nblocks = 3*chunkSumBlocks + 100
+	f := New(nblocks*BlockBits, 4)
+	hashes := randomU64(50000, 0xc5c5)
+	for _, h := range hashes {
+		f.Add(h)
+	}
+
+	for _, embed := range []bool{false, true} {
+		opts := DumpOptions{ChunkChecksums: true, EmbedChecksum: embed}
+
+		var buf bytes.Buffer
+		n, err := DumpWithOptions(&buf, f, opts)
+		require.NoError(t, err)
+		assert.Equal(t, f.MarshaledSize(opts), n)
+		assert.Equal(t, f.MarshaledSize(DumpOptions{EmbedChecksum: embed})+BlockBits/8, n)
+		p := append([]byte(nil), buf.Bytes()...)
+
+		appended, err := AppendDump(nil, f, opts)
+		require.NoError(t, err)
+		assert.Equal(t, p, appended)
+
+		g, err := LoadBytes(p)
+		require.NoError(t, err)
+		assert.True(t, f.Equals(g))
+
+		l, err := NewLoader(bytes.NewReader(p))
+		require.NoError(t, err)
+		g, err = l.LoadParallel(nil, 4)
+		require.NoError(t, err)
+		assert.True(t, f.Equals(g))
+		assert.Nil(t, l.CorruptBlocks())
+
+		// Corrupt chunks 0, 1 and the last, partial chunk.
+		q := append([]byte(nil), p...)
+		for _, i := range []int{17, chunkSumBlocks + 5, nblocks - 1} {
+			q[BlockBits/8*(i+1)+3] ^= 0x10
+		}
+		want := []BlockRange{{0, 2 * chunkSumBlocks}, {3 * chunkSumBlocks, nblocks}}
+		corrupt := func(i int) bool {
+			return i < 2*chunkSumBlocks || i >= 3*chunkSumBlocks
+		}
+
+		_, err = LoadBytes(q)
+		assert.True(t, errors.Is(err, ErrChecksum))
+
+		l, err = NewLoader(bytes.NewReader(q))
+		require.NoError(t, err)
+		_, err = l.Load(nil)
+		var cerr *CorruptBlocksError
+		require.True(t, errors.As(err, &cerr))
+		assert.Equal(t, want, cerr.Blocks)
+		assert.True(t, errors.Is(err, ErrChecksum))
+
+		// Repair, in a stream of two dumps.
+		r := bytes.NewReader(append(append([]byte(nil), q...), p...))
+		l, err = NewLoader(r)
+		require.NoError(t, err)
+		g, err = l.OnCorrupt(CorruptZero).Load(nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, l.CorruptBlocks())
+		for i := range g.b {
+			if corrupt(i) {
+				assert.Equal(t, block{}, g.b[i])
+			} else {
+				assert.Equal(t, f.b[i], g.b[i])
+			}
+		}
+
+		l, err = NewLoader(r)
+		require.NoError(t, err)
+		g, err = l.OnCorrupt(CorruptZero).Load(nil)
+		require.NoError(t, err)
+		assert.Nil(t, l.CorruptBlocks())
+		assert.True(t, f.Equals(g))
+		assert.Zero(t, r.Len())
+
+		l, err = NewLoader(bytes.NewReader(q))
+		require.NoError(t, err)
+		s, err := l.OnCorrupt(CorruptFill).LoadSync(nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, l.CorruptBlocks())
+		for _, h := range hashes {
+			assert.True(t, s.Has(h))
+		}
+		assert.Equal(t, ^uint32(0), s.b[0][0])
+		assert.Equal(t, f.b[2*chunkSumBlocks], s.b[2*chunkSumBlocks])
+	}
+}
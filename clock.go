@@ -0,0 +1,51 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "time"
+
+// A Clock tells time for the time-based components of this package,
+// such as RotatingFilter, TimeWindowFilter and History. Tests can replace
+// the default, SystemClock, with a fake to make these deterministic;
+// package blobloomtest provides one.
+type Clock interface {
+	Now() time.Time
+
+	// NewTicker returns a Ticker that delivers the time on its channel
+	// every d, as time.NewTicker does.
+	NewTicker(d time.Duration) Ticker
+}
+
+// A Ticker is the Clock counterpart of a time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock is the Clock that uses the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct{ t *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
@@ -0,0 +1,609 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A Cluster distributes the blocks of a Bloom filter over a set of nodes,
+// so that the filter can grow beyond the memory of a single machine.
+//
+// The blocks are grouped into partitions of contiguous blocks, which are
+// assigned to nodes by consistent hashing. Each key maps to a single block,
+// so Add and Has are routed to the owners of that block's partition.
+// When nodes join or leave, only partitions whose owners change are
+// transferred. A Cluster answers exactly as a Filter of the same Config would.
+//
+// The Cluster itself holds no filter data and does no networking;
+// that is up to the Node implementations. A Cluster is safe for concurrent
+// use. Add and Has block while nodes join or leave.
+type Cluster struct {
+	mu sync.RWMutex
+
+	shape      FilterView // Without blocks. Describes the full filter.
+	partBlocks uint64     // Blocks per partition.
+	npart      int
+	opts       ClusterOptions
+
+	nodes  map[string]Node
+	ring   []ringPoint
+	owners [][]string // Names of the owners of each partition, primary first.
+}
+
+// ClusterOptions are options for NewCluster.
+type ClusterOptions struct {
+	// Trigger the "contains filtered or unexported fields" message for
+	// forward compatibility and force the caller to use named fields.
+	_ struct{}
+
+	// Number of partitions. Defaults to 256 if zero. If the filter has
+	// fewer blocks than this, each block is a partition.
+	Partitions int
+
+	// Number of nodes that store each partition. Defaults to one.
+	// Add goes to all replicas, Has to the first. If that fails,
+	// Has fails over to the next replica.
+	Replicas int
+
+	// If HedgeDelay is positive, Has sends a hedged request to the next
+	// replica whenever the previous one has not answered within this delay,
+	// and returns the first successful answer. This bounds the latency of
+	// Has when a node is slow, at the cost of extra requests.
+	HedgeDelay time.Duration
+
+	// If FailOpen is true and all replicas fail, Has returns true along
+	// with the error. Use this when a false positive is preferable
+	// to a failed lookup.
+	FailOpen bool
+
+	// Number of points per node on the consistent hashing ring.
+	// More points give a more even distribution. Defaults to 64.
+	VirtualNodes int
+
+	// Clock for hedging, RepairEvery and ReadThrough.Run.
+	// Defaults to SystemClock.
+	Clock Clock
+}
+
+// A Node stores partitions of a Cluster's filter. Its methods are called
+// by the Cluster, possibly concurrently. Partitions are identified by
+// their index.
+//
+// A Node that is accessed over a network would typically forward calls
+// to a LocalNode on the remote machine.
+type Node interface {
+	// Add adds keys with the given hash values, all of which map
+	// to partition part.
+	Add(part int, hashes []uint64) error
+
+	// Has sets found[i] to whether hashes[i] has been added.
+	// All of the hashes map to partition part.
+	Has(part int, hashes []uint64, found []bool) error
+
+	// Export returns the contents of partition part,
+	// in the block encoding used by Dump.
+	Export(part int) ([]byte, error)
+
+	// Import merges data, as returned by Export, into partition part.
+	Import(part int, data []byte) error
+
+	// Drop discards partition part.
+	Drop(part int) error
+
+	// Fingerprint returns a hash of the contents of partition part.
+	// Replicas with the same contents must return the same fingerprint.
+	Fingerprint(part int) (uint64, error)
+}
+
+// NewCluster returns a Cluster, without nodes, for a filter with
+// parameters as computed by NewOptimized(config).
+func NewCluster(config Config, opts ClusterOptions) *Cluster {
+	config.Layout.check()
+	nbits, nhashes := fixBitsAndHashes(Optimize(config))
+	nblocks := nbits / BlockBits
+
+	if opts.Partitions <= 0 {
+		opts.Partitions = 256
+	}
+	if opts.Replicas <= 0 {
+		opts.Replicas = 1
+	}
+	if opts.VirtualNodes <= 0 {
+		opts.VirtualNodes = 64
+	}
+	if opts.Clock == nil {
+		opts.Clock = SystemClock
+	}
+
+	npart := uint64(opts.Partitions)
+	if npart > nblocks {
+		npart = nblocks
+	}
+	partBlocks := (nblocks + npart - 1) / npart
+	npart = (nblocks + partBlocks - 1) / partBlocks
+
+	return &Cluster{
+		shape: FilterView{
+			nblocks: nblocks,
+			k:       nhashes,
+			premix:  config.Premix,
+			layout:  config.Layout,
+		},
+		partBlocks: partBlocks,
+		npart:      int(npart),
+		opts:       opts,
+		nodes:      make(map[string]Node),
+		owners:     make([][]string, npart),
+	}
+}
+
+var errNoNodes = errors.New("blobloom: cluster has no nodes")
+
+// Add inserts keys with the given hash values into c.
+func (c *Cluster) Add(hashes ...uint64) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.nodes) == 0 {
+		return errNoNodes
+	}
+
+	byPart := make(map[int][]uint64)
+	for _, h := range hashes {
+		p := c.partition(h)
+		byPart[p] = append(byPart[p], h)
+	}
+	for p, hs := range byPart {
+		for _, name := range c.owners[p] {
+			if err := c.nodes[name].Add(p, hs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+func (c *Cluster) Has(h uint64) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.nodes) == 0 {
+		return false, errNoNodes
+	}
+
+	p := c.partition(h)
+	owners := c.owners[p]
+	nodes := make([]Node, len(owners))
+	for i, name := range owners {
+		nodes[i] = c.nodes[name]
+	}
+
+	var (
+		found bool
+		err   error
+	)
+	if c.opts.HedgeDelay > 0 && len(nodes) > 1 {
+		found, err = hasHedged(nodes, p, h, c.opts.HedgeDelay, c.opts.Clock)
+	} else {
+		found, err = hasFailover(nodes, p, h)
+	}
+	if err != nil && c.opts.FailOpen {
+		found = true
+	}
+	return found, err
+}
+
+func hasOne(n Node, p int, h uint64) (bool, error) {
+	var found [1]bool
+	err := n.Has(p, []uint64{h}, found[:])
+	return found[0], err
+}
+
+// hasFailover asks nodes in order until one answers.
+func hasFailover(nodes []Node, p int, h uint64) (found bool, err error) {
+	for i, n := range nodes {
+		f, e := hasOne(n, p, h)
+		if e == nil {
+			return f, nil
+		}
+		if i == 0 {
+			err = e
+		}
+	}
+	return false, err
+}
+
+// hasHedged asks nodes in order, moving on to the next one when the
+// previous ones have failed or not answered within delay.
+func hasHedged(nodes []Node, p int, h uint64, delay time.Duration, clock Clock) (found bool, err error) {
+	type result struct {
+		found bool
+		err   error
+	}
+	// Buffered, so that stragglers don't block after we return.
+	results := make(chan result, len(nodes))
+
+	launched, failed := 0, 0
+	var (
+		timer Ticker // Only the first tick is used.
+		hedge <-chan time.Time
+	)
+	stop := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, hedge = nil, nil
+		}
+	}
+	defer stop()
+
+	launch := func() {
+		n := nodes[launched]
+		launched++
+		go func() {
+			f, e := hasOne(n, p, h)
+			results <- result{f, e}
+		}()
+
+		stop()
+		if launched < len(nodes) {
+			timer = clock.NewTicker(delay)
+			hedge = timer.C()
+		}
+	}
+
+	launch()
+	for {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				return r.found, nil
+			}
+			if failed++; failed == 1 {
+				err = r.err
+			}
+			if launched < len(nodes) {
+				launch()
+			} else if failed == launched {
+				return false, err
+			}
+		case <-hedge:
+			launch()
+		}
+	}
+}
+
+// Owners returns the names of the nodes that store the key with hash value h,
+// primary first.
+func (c *Cluster) Owners(h uint64) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.owners[c.partition(h)]...)
+}
+
+// exportPartition exports partition p from its owners, failing over
+// to the next replica if one fails.
+func (c *Cluster) exportPartition(p int) (data []byte, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.nodes) == 0 {
+		return nil, errNoNodes
+	}
+	for i, name := range c.owners[p] {
+		d, e := c.nodes[name].Export(p)
+		if e == nil {
+			return d, nil
+		}
+		if i == 0 {
+			err = e
+		}
+	}
+	return nil, err
+}
+
+func (c *Cluster) partition(h uint64) int {
+	return int(c.shape.BlockOf(h) / c.partBlocks)
+}
+
+// Join adds a node to c and transfers to it the partitions it now owns.
+//
+// If a transfer fails, Join returns the error and c is left unchanged.
+// Errors dropping partitions from their previous owners are returned,
+// but do not prevent the node from joining.
+func (c *Cluster) Join(name string, n Node) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.nodes[name]; ok {
+		return fmt.Errorf("blobloom: node %q already in cluster", name)
+	}
+	nodes := make(map[string]Node, len(c.nodes)+1)
+	for k, v := range c.nodes {
+		nodes[k] = v
+	}
+	nodes[name] = n
+	return c.rebalance(nodes)
+}
+
+// Leave removes a node from c, after transferring its partitions
+// to their new owners. The node must still be reachable.
+//
+// If a transfer fails, Leave returns the error and c is left unchanged.
+func (c *Cluster) Leave(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.nodes[name]; !ok {
+		return fmt.Errorf("blobloom: node %q not in cluster", name)
+	}
+	nodes := make(map[string]Node, len(c.nodes))
+	for k, v := range c.nodes {
+		if k != name {
+			nodes[k] = v
+		}
+	}
+	return c.rebalance(nodes)
+}
+
+// rebalance switches c to the given set of nodes.
+func (c *Cluster) rebalance(nodes map[string]Node) error {
+	ring := makeRing(nodes, c.opts.VirtualNodes)
+	owners := make([][]string, c.npart)
+
+	// Copy partitions to their new owners first,
+	// so that a failure leaves c unchanged.
+	for p := range owners {
+		owners[p] = ring.owners(partitionPoint(p), c.opts.Replicas)
+		old := c.owners[p]
+		if len(old) == 0 {
+			continue
+		}
+		var data []byte
+		for _, name := range owners[p] {
+			if contains(old, name) {
+				continue
+			}
+			if data == nil {
+				var err error
+				if data, err = c.nodes[old[0]].Export(p); err != nil {
+					return err
+				}
+			}
+			if err := nodes[name].Import(p, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	var err error
+	for p := range owners {
+		for _, name := range c.owners[p] {
+			if contains(owners[p], name) {
+				continue
+			}
+			if e := c.nodes[name].Drop(p); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+
+	c.nodes, c.ring, c.owners = nodes, ring, owners
+	return err
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+type ringPoint struct {
+	pos  uint64
+	node string
+}
+
+// A ring is a consistent hashing ring, sorted by position.
+type ring []ringPoint
+
+func makeRing(nodes map[string]Node, vnodes int) ring {
+	r := make(ring, 0, len(nodes)*vnodes)
+	for name := range nodes {
+		h := fnv.New64a()
+		h.Write([]byte(name))
+		base := h.Sum64()
+		for i := 0; i < vnodes; i++ {
+			r = append(r, ringPoint{mix64(base ^ mix64(uint64(i)+1)), name})
+		}
+	}
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].pos != r[j].pos {
+			return r[i].pos < r[j].pos
+		}
+		return r[i].node < r[j].node
+	})
+	return r
+}
+
+// owners returns up to n distinct nodes, starting at pos on the ring.
+func (r ring) owners(pos uint64, n int) []string {
+	var names []string
+	start := sort.Search(len(r), func(i int) bool { return r[i].pos >= pos })
+	for i := 0; i < len(r) && len(names) < n; i++ {
+		name := r[(start+i)%len(r)].node
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func partitionPoint(p int) uint64 { return mix64(uint64(p) ^ 0x9e3779b97f4a7c15) }
+
+// A LocalNode is a Node that stores its partitions in memory.
+type LocalNode struct {
+	c *Cluster
+
+	mu    sync.RWMutex
+	parts map[int]*FilterView
+}
+
+// NewLocalNode returns an empty LocalNode for storing partitions of c.
+//
+// On a remote machine, it suffices to construct a Cluster with the same
+// Config and ClusterOptions, without nodes, to create a LocalNode.
+func (c *Cluster) NewLocalNode() *LocalNode {
+	return &LocalNode{c: c, parts: make(map[int]*FilterView)}
+}
+
+// part returns partition p, allocating it if create is true.
+// The caller must hold n.mu, exclusively if create is true.
+func (n *LocalNode) part(p int, create bool) (*FilterView, error) {
+	if p < 0 || p >= n.c.npart {
+		return nil, fmt.Errorf("blobloom: partition %d out of range", p)
+	}
+	v := n.parts[p]
+	if v == nil && create {
+		shape := &n.c.shape
+		first := uint64(p) * n.c.partBlocks
+		nblocks := n.c.partBlocks
+		if first+nblocks > shape.nblocks {
+			nblocks = shape.nblocks - first
+		}
+		v = &FilterView{
+			b:       makeBlocks(nblocks),
+			first:   first,
+			nblocks: shape.nblocks,
+			k:       shape.k,
+			premix:  shape.premix,
+			layout:  shape.layout,
+		}
+		n.parts[p] = v
+	}
+	return v, nil
+}
+
+// Add implements Node.
+func (n *LocalNode) Add(part int, hashes []uint64) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	v, err := n.part(part, true)
+	if err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		if !v.Owns(h) {
+			return fmt.Errorf("blobloom: hash %#x not in partition %d", h, part)
+		}
+		v.add(h)
+	}
+	return nil
+}
+
+// Has implements Node.
+func (n *LocalNode) Has(part int, hashes []uint64, found []bool) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	v, err := n.part(part, false)
+	if err != nil {
+		return err
+	}
+	for i, h := range hashes {
+		found[i] = v != nil && v.Owns(h) && v.Has(h)
+	}
+	return nil
+}
+
+// Export implements Node.
+func (n *LocalNode) Export(part int) ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	v, err := n.part(part, true)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, len(v.b)*BlockBits/8)
+	for i := range v.b {
+		data = appendBlock(data, &v.b[i])
+	}
+	return data, nil
+}
+
+// Import implements Node.
+func (n *LocalNode) Import(part int, data []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	v, err := n.part(part, true)
+	if err != nil {
+		return err
+	}
+	if len(data) != len(v.b)*BlockBits/8 {
+		return errorf(ErrShapeMismatch, "blobloom: wrong data size %d for partition %d", len(data), part)
+	}
+	for i := range v.b {
+		for j := range v.b[i] {
+			v.b[i][j] |= binary.LittleEndian.Uint32(data[(i*blockWords+j)*4:])
+		}
+	}
+	return nil
+}
+
+// Drop implements Node.
+func (n *LocalNode) Drop(part int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.parts, part)
+	return nil
+}
+
+// Fingerprint implements Node. It returns the FNV-1a hash of the data
+// that Export would return.
+func (n *LocalNode) Fingerprint(part int) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	v, err := n.part(part, true)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	buf := make([]byte, 0, BlockBits/8)
+	for i := range v.b {
+		h.Write(appendBlock(buf, &v.b[i]))
+	}
+	return h.Sum64(), nil
+}
+
+// NumPartitions returns the number of partitions stored in n.
+func (n *LocalNode) NumPartitions() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return len(n.parts)
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCluster(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 2e4, FPRate: 1e-3, Premix: true}
+	c := NewCluster(config, ClusterOptions{Partitions: 64})
+	f := NewOptimized(config)
+	hashes := randomU64(4e4, 0xc1a5)
+
+	_, err := c.Has(0)
+	assert.Error(t, err)
+
+	nodes := make(map[string]*LocalNode)
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprint("node", i)
+		nodes[name] = c.NewLocalNode()
+		require.NoError(t, c.Join(name, nodes[name]))
+	}
+
+	require.NoError(t, c.Add(hashes[:1e4]...))
+	for _, h := range hashes[:1e4] {
+		f.Add(h)
+	}
+
+	check := func() {
+		t.Helper()
+		total := 0
+		for _, n := range nodes {
+			total += n.NumPartitions()
+		}
+		assert.LessOrEqual(t, total, c.npart)
+
+		for _, h := range hashes {
+			found, err := c.Has(h)
+			require.NoError(t, err)
+			assert.Equal(t, f.Has(h), found)
+		}
+	}
+	check()
+
+	nodes["node3"] = c.NewLocalNode()
+	require.NoError(t, c.Join("node3", nodes["node3"]))
+	assert.NotZero(t, nodes["node3"].NumPartitions())
+	check()
+
+	require.NoError(t, c.Leave("node1"))
+	assert.Zero(t, nodes["node1"].NumPartitions())
+	delete(nodes, "node1")
+	check()
+
+	require.NoError(t, c.Add(hashes[1e4:2e4]...))
+	for _, h := range hashes[1e4:2e4] {
+		f.Add(h)
+	}
+	check()
+
+	assert.Error(t, c.Join("node0", c.NewLocalNode()))
+	assert.Error(t, c.Leave("node1"))
+}
+
+func TestClusterReplicas(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1e4, FPRate: 1e-2}
+	c := NewCluster(config, ClusterOptions{Replicas: 2})
+	hashes := randomU64(1e4, 0xe9)
+
+	a, b := c.NewLocalNode(), c.NewLocalNode()
+	require.NoError(t, c.Join("a", a))
+	require.NoError(t, c.Add(hashes[:100]...))
+	require.NoError(t, c.Join("b", b))
+	assert.Equal(t, c.npart, a.NumPartitions())
+	assert.Equal(t, c.npart, b.NumPartitions())
+
+	for _, h := range hashes[:100] {
+		assert.Len(t, c.Owners(h), 2)
+		// Both replicas have the key, including those copied to b.
+		for _, n := range []*LocalNode{a, b} {
+			var found [1]bool
+			require.NoError(t, n.Has(c.partition(h), []uint64{h}, found[:]))
+			assert.True(t, found[0])
+		}
+	}
+}
+
+// A failingNode fails every Import.
+type failingNode struct{ *LocalNode }
+
+func (failingNode) Import(int, []byte) error { return errors.New("import failed") }
+
+func TestClusterJoinFailure(t *testing.T) {
+	t.Parallel()
+
+	c := NewCluster(Config{Capacity: 1e4, FPRate: 1e-2}, ClusterOptions{})
+	require.NoError(t, c.Join("a", c.NewLocalNode()))
+	h := randomU64(1, 0xf)[0]
+	require.NoError(t, c.Add(h))
+
+	assert.Error(t, c.Join("b", failingNode{c.NewLocalNode()}))
+	assert.Equal(t, []string{"a"}, c.Owners(h))
+	found, err := c.Has(h)
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+// A flakyNode is a LocalNode with adjustable latency and failures for Has.
+type flakyNode struct {
+	*LocalNode
+	delay time.Duration
+	fail  bool
+}
+
+func (n *flakyNode) Has(part int, hashes []uint64, found []bool) error {
+	time.Sleep(n.delay)
+	if n.fail {
+		return errors.New("flaky node failed")
+	}
+	return n.LocalNode.Has(part, hashes, found)
+}
+
+func TestClusterHedging(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1000, FPRate: 1e-2}
+	hashes := randomU64(100, 0x4ed9e)
+
+	for _, hedge := range []time.Duration{0, time.Millisecond} {
+		c := NewCluster(config, ClusterOptions{Replicas: 2, HedgeDelay: hedge})
+		a := &flakyNode{LocalNode: c.NewLocalNode()}
+		b := &flakyNode{LocalNode: c.NewLocalNode()}
+		require.NoError(t, c.Join("a", a))
+		require.NoError(t, c.Join("b", b))
+		require.NoError(t, c.Add(hashes[:50]...))
+
+		check := func() {
+			t.Helper()
+			for _, h := range hashes[:50] {
+				found, err := c.Has(h)
+				require.NoError(t, err)
+				assert.True(t, found)
+			}
+		}
+
+		// Failover.
+		a.fail = true
+		check()
+		a.fail, b.fail = false, true
+		check()
+
+		a.fail = true
+		_, err := c.Has(hashes[0])
+		assert.Error(t, err)
+		c.opts.FailOpen = true
+		found, err := c.Has(hashes[99])
+		assert.Error(t, err)
+		assert.True(t, found)
+		c.opts.FailOpen = false
+		a.fail, b.fail = false, false
+
+		if hedge == 0 {
+			continue
+		}
+
+		// One node is slow: hedged requests keep latency low.
+		a.delay = time.Second
+		start := time.Now()
+		for _, h := range hashes[:5] {
+			found, err := c.Has(h)
+			require.NoError(t, err)
+			assert.True(t, found)
+		}
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// A CountMin is a count-min sketch (Cormode and Muthukrishnan, 2005).
+// It estimates how many times keys have been added to it.
+//
+// A CountMin takes the same 64-bit hash values as a Filter,
+// so a single hash computation serves both.
+type CountMin struct {
+	c      []uint32 // depth rows of width counters.
+	width  uint32
+	depth  int
+	premix bool
+}
+
+// A CountMinConfig holds the parameters for NewCountMinOptimized.
+type CountMinConfig struct {
+	// Estimates exceed the true count by at most Epsilon times the total
+	// count of all keys, with probability at least 1-Delta.
+	Epsilon, Delta float64
+
+	// Whether to scramble hash values, as for a Filter. See Config.Premix.
+	Premix bool
+}
+
+// NewCountMin constructs a count-min sketch with depth rows of width
+// counters each. Both must be positive and width must fit in 32 bits.
+func NewCountMin(width, depth int) *CountMin {
+	if width <= 0 || uint64(width) > math.MaxUint32 {
+		panic("blobloom: count-min width out of range")
+	}
+	if depth <= 0 {
+		panic("blobloom: count-min depth must be positive")
+	}
+	if width > int(^uint(0)>>1)/depth {
+		panic("blobloom: count-min sketch too large")
+	}
+	return &CountMin{
+		c:     make([]uint32, width*depth),
+		width: uint32(width),
+		depth: depth,
+	}
+}
+
+// NewCountMinOptimized constructs a count-min sketch with the error bounds
+// given by config.
+func NewCountMinOptimized(config CountMinConfig) *CountMin {
+	eps, delta := config.Epsilon, config.Delta
+	if !(eps > 0 && eps < 1) || !(delta > 0 && delta < 1) {
+		panic("blobloom: Epsilon and Delta must be between 0 and 1")
+	}
+	width := math.Ceil(math.E / eps)
+	if width > math.MaxUint32 {
+		panic("blobloom: Epsilon too small")
+	}
+	depth := math.Ceil(math.Log(1 / delta))
+
+	s := NewCountMin(int(width), int(depth))
+	s.premix = config.Premix
+	return s
+}
+
+// Add increments the count of a key with hash value h.
+func (s *CountMin) Add(h uint64) { s.AddN(h, 1) }
+
+// AddN adds n to the count of a key with hash value h.
+// Counters saturate at the maximum uint32.
+func (s *CountMin) AddN(h uint64, n uint32) {
+	h1, h2 := s.hashes(h)
+	for i, row := 0, s.c; i < s.depth; i++ {
+		c := &row[reducerange(h1, uint64(s.width))]
+		*c = addSaturate(*c, n)
+		row = row[s.width:]
+		h1, h2 = doublehash(h1, h2, i+1)
+	}
+}
+
+// Count estimates the number of times a key with hash value h has been
+// added. The estimate is never too low, but may be too high,
+// because of hash collisions.
+func (s *CountMin) Count(h uint64) uint32 {
+	h1, h2 := s.hashes(h)
+	min := uint32(math.MaxUint32)
+	for i, row := 0, s.c; i < s.depth; i++ {
+		if c := row[reducerange(h1, uint64(s.width))]; c < min {
+			min = c
+		}
+		row = row[s.width:]
+		h1, h2 = doublehash(h1, h2, i+1)
+	}
+	return min
+}
+
+// Merge adds the counts of t to those of s.
+// The sketches must have the same dimensions and premixing.
+func (s *CountMin) Merge(t *CountMin) {
+	if s.width != t.width || s.depth != t.depth || s.premix != t.premix {
+		panic("blobloom: count-min sketches have different shapes")
+	}
+	for i, c := range t.c {
+		s.c[i] = addSaturate(s.c[i], c)
+	}
+}
+
+// Reset sets all counts in s to zero.
+func (s *CountMin) Reset() {
+	for i := range s.c {
+		s.c[i] = 0
+	}
+}
+
+// Width returns the number of counters per row of s.
+func (s *CountMin) Width() int { return int(s.width) }
+
+// Depth returns the number of rows of s.
+func (s *CountMin) Depth() int { return s.depth }
+
+func (s *CountMin) hashes(h uint64) (h1, h2 uint32) {
+	if s.premix {
+		h = mix64(h)
+	}
+	return uint32(h >> 32), uint32(h)
+}
+
+func addSaturate(a, b uint32) uint32 {
+	if c := a + b; c >= a {
+		return c
+	}
+	return math.MaxUint32
+}
+
+// DumpCountMin writes s to w, with an optional comment string, in the
+// format that a Loader accepts. It returns the number of bytes written to w.
+func DumpCountMin(w io.Writer, s *CountMin, comment string) (int64, error) {
+	if err := checkDumpComment(comment); err != nil {
+		return 0, err
+	}
+
+	size := 64 + 4*uint64(len(s.c))
+	if size > dumpBufSize {
+		size = dumpBufSize
+	}
+	buf := appendHeader(make([]byte, 0, size), int(s.width), s.depth, s.premix, 0, comment)
+	buf[10] = byte(KindCountMin)
+
+	var n int64
+	for _, c := range s.c {
+		if len(buf) == cap(buf) {
+			k, err := w.Write(buf)
+			n += int64(k)
+			if err != nil {
+				return n, err
+			}
+			buf = buf[:0]
+		}
+		buf = buf[:len(buf)+4]
+		binary.LittleEndian.PutUint32(buf[len(buf)-4:], c)
+	}
+
+	k, err := w.Write(buf)
+	n += int64(k)
+	return n, err
+}
+
+// LoadCountMin reads a CountMin from the Loader.
+// It returns an error if the dump holds a different Kind of sketch.
+func (l *Loader) LoadCountMin() (*CountMin, error) {
+	if err := l.checkKind(KindCountMin); err != nil {
+		return nil, err
+	}
+	if l.nblocks*uint64(l.nhashes) > uint64(^uint(0)>>1) {
+		return nil, errorf(ErrTooLarge, "blobloom: count-min sketch too large")
+	}
+
+	s := NewCountMin(int(l.nblocks), l.nhashes)
+	s.premix = l.premix
+
+	for c := s.c; len(c) > 0; {
+		p := l.buf[:]
+		if len(c) < len(p)/4 {
+			p = p[:4*len(c)]
+		}
+		if err := l.fill(p); err != nil {
+			return nil, err
+		}
+		for ; len(p) > 0; p = p[4:] {
+			c[0] = binary.LittleEndian.Uint32(p)
+			c = c[1:]
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountMin(t *testing.T) {
+	t.Parallel()
+
+	for _, premix := range []bool{false, true} {
+		s := NewCountMinOptimized(CountMinConfig{
+			Epsilon: 1e-3, Delta: 1e-3, Premix: premix,
+		})
+		assert.Equal(t, 2719, s.Width())
+		assert.Equal(t, 7, s.Depth())
+
+		hashes := randomU64(2000, 0xc0a7)
+		var total uint64
+		for i, h := range hashes[:1000] {
+			s.AddN(h, uint32(i%10))
+			s.Add(h)
+			total += uint64(i%10) + 1
+		}
+
+		bound := uint32(math.Ceil(1e-3 * float64(total)))
+		for i, h := range hashes[:1000] {
+			c := s.Count(h)
+			assert.GreaterOrEqual(t, c, uint32(i%10)+1)
+			assert.LessOrEqual(t, c, uint32(i%10)+1+bound)
+		}
+		for _, h := range hashes[1000:] {
+			assert.LessOrEqual(t, s.Count(h), bound)
+		}
+
+		u := NewCountMinOptimized(CountMinConfig{
+			Epsilon: 1e-3, Delta: 1e-3, Premix: premix,
+		})
+		u.Merge(s)
+		u.Merge(s)
+		for _, h := range hashes[:10] {
+			assert.Equal(t, 2*s.Count(h), u.Count(h))
+		}
+
+		u.Reset()
+		assert.Zero(t, u.Count(hashes[0]))
+	}
+
+	assert.Panics(t, func() { NewCountMin(0, 1) })
+	assert.Panics(t, func() { NewCountMin(1, 0) })
+	assert.Panics(t, func() { NewCountMin(100, 4).Merge(NewCountMin(100, 5)) })
+}
+
+func TestCountMinSaturate(t *testing.T) {
+	t.Parallel()
+
+	s := NewCountMin(1, 1)
+	s.AddN(1, math.MaxUint32-1)
+	s.Add(2)
+	s.Add(3)
+	assert.EqualValues(t, uint32(math.MaxUint32), s.Count(4))
+}
+
+func TestCountMinDump(t *testing.T) {
+	t.Parallel()
+
+	s := NewCountMinOptimized(CountMinConfig{Epsilon: .01, Delta: .01, Premix: true})
+	hashes := randomU64(500, 0xd0)
+	for _, h := range hashes {
+		s.Add(h)
+	}
+
+	var buf bytes.Buffer
+	n, err := DumpCountMin(&buf, s, "fnv-1a")
+	require.NoError(t, err)
+	assert.EqualValues(t, 64+4*s.Width()*s.Depth(), n)
+
+	assert.Equal(t, "blobloom", string(buf.Bytes()[:8]))
+
+	dump := append([]byte(nil), buf.Bytes()...)
+	l, err := NewLoader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, KindCountMin, l.Kind())
+	assert.Equal(t, "fnv-1a", l.Comment)
+	u, err := l.LoadCountMin()
+	require.NoError(t, err)
+	assert.Equal(t, s, u)
+
+	l, err = NewLoader(bytes.NewReader(dump[:len(dump)-1]))
+	require.NoError(t, err)
+	_, err = l.LoadCountMin()
+	assert.Error(t, err)
+
+	l, err = NewLoader(bytes.NewReader(dump))
+	require.NoError(t, err)
+	_, err = l.Load(nil)
+	assert.True(t, errors.Is(err, ErrShapeMismatch))
+
+	_, err = DumpCountMin(&buf, s, string(make([]byte, 45)))
+	assert.True(t, errors.Is(err, ErrTooLarge))
+}
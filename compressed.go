@@ -0,0 +1,154 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// A CompressedFilter is a read-only Bloom filter that is stored in
+// compressed form and answers Has queries directly from that form.
+//
+// Each block is stored in one of three ways, depending on the number
+// of bits set in it: not at all, if it is empty; as a list of bit positions,
+// if it has few bits set; or as an ordinary bitmap. For a sparsely filled
+// Filter, this can save a lot of memory, at the cost of slower lookups.
+// For a Filter filled to capacity, it uses slightly more memory than
+// the Filter itself.
+type CompressedFilter struct {
+	k       int
+	premix  bool
+	layout  Layout
+	nblocks int
+
+	// The data for block i starts at chunkOff[i/chunkBlocks]+blockOff[i].
+	// A block's data ends where the next block's starts.
+	chunkOff []uint64 // Length nchunks+1.
+	blockOff []uint16 // Length nblocks.
+	data     []byte
+}
+
+const (
+	// Number of blocks per chunk. The data for a chunk must fit
+	// in 64KiB, so that blockOff can use 16-bit offsets.
+	chunkBlocks = 256
+
+	// Blocks with at least this many bits set are stored as bitmaps.
+	// Smaller blocks are stored as lists of 16-bit positions.
+	maxPositions = BlockBits / 16
+)
+
+// Compress returns a CompressedFilter with the same contents as f.
+func Compress(f *Filter) *CompressedFilter {
+	c := &CompressedFilter{
+		k:        f.k,
+		premix:   f.premix,
+		layout:   f.layout,
+		nblocks:  len(f.b),
+		blockOff: make([]uint16, len(f.b)),
+	}
+
+	for i := range f.b {
+		if i%chunkBlocks == 0 {
+			c.chunkOff = append(c.chunkOff, uint64(len(c.data)))
+		}
+		chunkStart := c.chunkOff[len(c.chunkOff)-1]
+		c.blockOff[i] = uint16(uint64(len(c.data)) - chunkStart)
+
+		b := &f.b[i]
+		switch n := onescountGeneric(b); {
+		case n == 0:
+		case n < maxPositions:
+			for j, w := range b {
+				for w != 0 {
+					pos := j*wordSize + bits.TrailingZeros32(w)
+					c.data = append(c.data, byte(pos), byte(pos>>8))
+					w &= w - 1
+				}
+			}
+		default:
+			for _, w := range b {
+				c.data = append(c.data, byte(w), byte(w>>8), byte(w>>16), byte(w>>24))
+			}
+		}
+	}
+	c.chunkOff = append(c.chunkOff, uint64(len(c.data)))
+
+	// Release excess capacity.
+	c.data = append([]byte(nil), c.data...)
+	return c
+}
+
+// blockData returns the encoded data for block i.
+func (c *CompressedFilter) blockData(i int) []byte {
+	chunk := i / chunkBlocks
+	start := c.chunkOff[chunk] + uint64(c.blockOff[i])
+	end := c.chunkOff[chunk+1]
+	if (i+1)%chunkBlocks != 0 && i+1 < c.nblocks {
+		end = c.chunkOff[chunk] + uint64(c.blockOff[i+1])
+	}
+	return c.data[start:end]
+}
+
+// decodeBlock decodes the data for a block into b, which must be zero.
+func decodeBlock(b *block, p []byte) {
+	if len(p) == BlockBits/8 {
+		for j := range b {
+			b[j] = binary.LittleEndian.Uint32(p[4*j:])
+		}
+		return
+	}
+	for ; len(p) >= 2; p = p[2:] {
+		b.setbit(uint32(binary.LittleEndian.Uint16(p)))
+	}
+}
+
+// Decompress returns a new Filter with the same contents as c.
+func (c *CompressedFilter) Decompress() *Filter {
+	f := &Filter{b: makeBlocks(uint64(c.nblocks)), k: c.k, premix: c.premix, layout: c.layout}
+	for i := range f.b {
+		decodeBlock(&f.b[i], c.blockData(i))
+	}
+	return f
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+func (c *CompressedFilter) Has(h uint64) bool {
+	if c.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := c.layout.split(h)
+	i := reducerange(blk, uint64(c.nblocks))
+
+	p := c.blockData(int(i))
+	if len(p) == 0 {
+		return false
+	}
+	var b block
+	decodeBlock(&b, p)
+	return probe(h1, h2, c.k, b.getbit)
+}
+
+// NumBits returns the number of bits of the Filter that c was made from.
+func (c *CompressedFilter) NumBits() uint64 {
+	return BlockBits * uint64(c.nblocks)
+}
+
+// Size returns the approximate number of bytes of memory used by c.
+func (c *CompressedFilter) Size() uint64 {
+	return uint64(8*len(c.chunkOff) + 2*len(c.blockOff) + len(c.data))
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(1e5, 0xc0b9e55)
+
+	for _, nkeys := range []int{0, 10, 1000, 1e4, 5e4} {
+		f := NewOptimized(Config{Capacity: 5e4, FPRate: 1e-3})
+		// Make sure we test a partial last chunk.
+		assert.NotZero(t, len(f.b)%chunkBlocks)
+
+		for _, h := range hashes[:nkeys] {
+			f.Add(h)
+		}
+		c := Compress(f)
+
+		assert.True(t, f.Equals(c.Decompress()))
+		assert.Equal(t, f.NumBits(), c.NumBits())
+		for _, h := range hashes {
+			assert.Equal(t, f.Has(h), c.Has(h))
+		}
+		t.Logf("%d keys: %d bytes, compressed to %d",
+			nkeys, f.NumBits()/8, c.Size())
+	}
+
+	f := New(BlockBits, 3)
+	f.Fill()
+	assert.True(t, Compress(f).Has(12345))
+}
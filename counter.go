@@ -0,0 +1,106 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// A Counter is a concurrent counter for instrumentation. It spreads its
+// count over several cache lines, which goroutines running on different
+// processors tend to update separately, and sums them on Load. This keeps
+// a Counter from becoming a point of contention in a hot path that is
+// otherwise free of it, such as SyncFilter.Add and Has.
+//
+// The zero value is ready for use. As with sync/atomic, a Counter must be
+// 64-bit aligned on 32-bit platforms; the first word of an allocated
+// struct is.
+type Counter struct {
+	shards [counterShards]struct {
+		n uint64
+		_ [56]byte // Pad to a cache line.
+	}
+}
+
+const counterShards = 16
+
+// Add adds delta to c.
+func (c *Counter) Add(delta uint64) {
+	atomic.AddUint64(&c.shards[counterShard()].n, delta)
+}
+
+// Load returns the value of c. Concurrent calls to Add may or may not
+// be reflected in it.
+func (c *Counter) Load() (n uint64) {
+	for i := range c.shards {
+		n += atomic.LoadUint64(&c.shards[i].n)
+	}
+	return n
+}
+
+// Shard indices, assigned round-robin. A sync.Pool keeps a cache per
+// processor, so goroutines on the same processor tend to get the same index
+// and those on different processors different ones.
+var (
+	shardPool = sync.Pool{New: func() interface{} {
+		i := int(atomic.AddUint32(&nextShard, 1) % counterShards)
+		return &i
+	}}
+	nextShard uint32
+)
+
+func counterShard() int {
+	p := shardPool.Get().(*int)
+	i := *p
+	shardPool.Put(p)
+	return i
+}
+
+// A CountingSyncFilter is a SyncFilter that counts calls to Add and Has,
+// and the number of times Has returns true, in Counters.
+type CountingSyncFilter struct {
+	adds, lookups, hits Counter
+
+	*SyncFilter
+}
+
+// NewCountingSyncFilter returns a CountingSyncFilter that wraps f.
+func NewCountingSyncFilter(f *SyncFilter) *CountingSyncFilter {
+	return &CountingSyncFilter{SyncFilter: f}
+}
+
+// Add inserts a key with hash value h into f.
+func (f *CountingSyncFilter) Add(h uint64) {
+	f.adds.Add(1)
+	f.SyncFilter.Add(h)
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+func (f *CountingSyncFilter) Has(h uint64) bool {
+	f.lookups.Add(1)
+	has := f.SyncFilter.Has(h)
+	if has {
+		f.hits.Add(1)
+	}
+	return has
+}
+
+// Counts returns the numbers of calls to Add and Has, and of calls to Has
+// that returned true, so far.
+func (f *CountingSyncFilter) Counts() (adds, lookups, hits uint64) {
+	return f.adds.Load(), f.lookups.Load(), f.hits.Load()
+}
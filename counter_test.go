@@ -0,0 +1,93 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	var c Counter
+	assert.Zero(t, c.Load())
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				c.Add(2)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, uint64(16000), c.Load())
+}
+
+func TestCountingSyncFilter(t *testing.T) {
+	t.Parallel()
+
+	f := NewCountingSyncFilter(NewSync(1<<14, 4))
+	hashes := randomU64(2000, 0xc7)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(hashes []uint64) {
+			defer wg.Done()
+			for _, h := range hashes {
+				f.Add(h)
+				assert.True(t, f.Has(h))
+			}
+		}(hashes[w*250 : (w+1)*250])
+	}
+	wg.Wait()
+
+	hits := 0
+	for _, h := range hashes[1000:] {
+		if f.Has(h) {
+			hits++
+		}
+	}
+	adds, lookups, nhits := f.Counts()
+	assert.Equal(t, uint64(1000), adds)
+	assert.Equal(t, uint64(2000), lookups)
+	assert.Equal(t, uint64(1000+hits), nhits)
+}
+
+func BenchmarkCounter(b *testing.B) {
+	b.Run("impl=sharded", func(b *testing.B) {
+		var c Counter
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				c.Add(1)
+			}
+		})
+	})
+	b.Run("impl=atomic", func(b *testing.B) {
+		var n uint64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				atomic.AddUint64(&n, 1)
+			}
+		})
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+	"sync"
+)
+
+// A Compression identifies a compression format for the blocks of a dump,
+// as selected by DumpOptions.Compression and recorded in the dump header.
+type Compression uint8
+
+// Compression formats. Gzip is built in; others can be registered with
+// RegisterCompression.
+const (
+	NoCompression Compression = iota
+	Gzip
+)
+
+type compression struct {
+	newWriter func(io.Writer) (io.WriteCloser, error)
+	newReader func(io.Reader) (io.Reader, error)
+}
+
+var (
+	compressionsMu sync.RWMutex
+	compressions   = map[Compression]compression{
+		Gzip: {
+			newWriter: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			newReader: func(r io.Reader) (io.Reader, error) {
+				zr, err := gzip.NewReader(r)
+				if err == nil {
+					// Stop at the end of the dump.
+					zr.Multistream(false)
+				}
+				return zr, err
+			},
+		},
+	}
+)
+
+// RegisterCompression registers a compression format for the blocks of
+// dumps, under the identifier c, which is stored in the dump header.
+// It replaces any earlier registration of c. Dumps are compressed with
+// the io.WriteCloser returned by newWriter and decompressed by NewLoader
+// with the io.Reader returned by newReader.
+//
+// Identifiers below 128 are reserved for this package.
+// RegisterCompression panics if c is NoCompression.
+func RegisterCompression(c Compression,
+	newWriter func(io.Writer) (io.WriteCloser, error),
+	newReader func(io.Reader) (io.Reader, error),
+) {
+	if c == NoCompression {
+		panic("blobloom: cannot register NoCompression")
+	}
+
+	compressionsMu.Lock()
+	defer compressionsMu.Unlock()
+	compressions[c] = compression{newWriter, newReader}
+}
+
+func findCompression(c Compression) (compression, error) {
+	compressionsMu.RLock()
+	defer compressionsMu.RUnlock()
+
+	comp, ok := compressions[c]
+	if !ok {
+		return comp, errorf(ErrFormat, "blobloom: unknown compression %d", c)
+	}
+	return comp, nil
+}
+
+type decompressor struct {
+	magic     string
+	newReader func(io.Reader) (io.Reader, error)
+}
+
+var (
+	decompressorsMu sync.RWMutex
+	decompressors   = []decompressor{
+		{"\x1f\x8b", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+	}
+)
+
+// RegisterDecompressor registers a compression format for NewLoader.
+// When the input to NewLoader starts with magic, NewLoader passes it to
+// newReader and reads the dump from the io.Reader that newReader returns.
+//
+// The gzip format is registered by default. Other formats can be registered,
+// typically from an init function, without adding dependencies to this
+// package. For example, to accept zstd-compressed dumps using
+// github.com/klauspost/compress/zstd:
+//
+//	blobloom.RegisterDecompressor("\x28\xb5\x2f\xfd",
+//		func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) })
+//
+// RegisterDecompressor panics if magic is empty, longer than eight bytes
+// or a prefix of "blobloom".
+func RegisterDecompressor(magic string, newReader func(io.Reader) (io.Reader, error)) {
+	if magic == "" || len(magic) > 8 || strings.HasPrefix("blobloom", magic) {
+		panic("blobloom: invalid magic for decompressor")
+	}
+
+	decompressorsMu.Lock()
+	defer decompressorsMu.Unlock()
+	decompressors = append(decompressors, decompressor{magic, newReader})
+}
+
+// findDecompressor returns the decompressor registered for the first
+// eight bytes of an input, or nil.
+func findDecompressor(p []byte) func(io.Reader) (io.Reader, error) {
+	decompressorsMu.RLock()
+	defer decompressorsMu.RUnlock()
+
+	for _, d := range decompressors {
+		if strings.HasPrefix(string(p), d.magic) {
+			return d.newReader
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"io"
+	"os"
+)
+
+// Direct I/O transfers whole multiples of directAlign bytes at offsets
+// that are multiples of directAlign, from and to memory aligned to it.
+const (
+	directAlign   = 4096
+	directBufSize = 256 * directAlign
+)
+
+// DumpFile writes f to the file at path, in the format written by Dump,
+// creating or truncating the file as necessary. The file is synced before
+// DumpFile returns.
+//
+// If direct is true, DumpFile bypasses the operating system's page cache
+// where possible, so that dumping a large filter does not evict other data.
+// This is currently supported on Linux, and only on file systems that
+// support O_DIRECT. Elsewhere, the direct flag is ignored.
+func DumpFile(path string, f *Filter, opts DumpOptions, direct bool) (err error) {
+	if err := checkDump(f.b, f.k, opts.Comment); err != nil {
+		return err
+	}
+
+	file, direct, err := openDirect(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666, direct)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := file.Close(); err == nil {
+			err = e
+		}
+	}()
+
+	w := &directWriter{file: file}
+	if direct {
+		w.buf = alignedBuf(directBufSize)[:0]
+	} else {
+		w.buf = make([]byte, 0, directBufSize)
+	}
+	size, err := DumpWithOptions(w, f, opts)
+	if err != nil {
+		return err
+	}
+	if err = w.flush(direct); err != nil {
+		return err
+	}
+	if direct {
+		// Remove the padding written by flush. MarshaledSize would be
+		// wrong here, since it doesn't account for compression.
+		if err = file.Truncate(size); err != nil {
+			return err
+		}
+	}
+	return file.Sync()
+}
+
+// A directWriter writes to a file in chunks of the size of its buffer.
+type directWriter struct {
+	file *os.File
+	buf  []byte
+}
+
+func (w *directWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		k := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+k]
+		p = p[k:]
+		n += k
+
+		if len(w.buf) == cap(w.buf) {
+			if err = w.flush(false); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// flush writes out the buffer. If pad is true, it first zero-pads
+// the buffer to a multiple of directAlign.
+func (w *directWriter) flush(pad bool) error {
+	if pad {
+		for len(w.buf)%directAlign != 0 {
+			w.buf = append(w.buf, 0)
+		}
+	}
+	_, err := w.file.Write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// LoadFile reads a Filter from the file at path, as written by DumpFile.
+//
+// If direct is true, LoadFile bypasses the page cache where possible.
+// See DumpFile.
+func LoadFile(path string, direct bool) (*Filter, error) {
+	file, direct, err := openDirect(path, os.O_RDONLY, 0, direct)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := &directReader{file: file}
+	if direct {
+		r.buf = alignedBuf(directBufSize)
+	} else {
+		r.buf = make([]byte, directBufSize)
+	}
+	r.buf = r.buf[:0]
+
+	l, err := NewLoader(r)
+	if err != nil {
+		return nil, err
+	}
+	return l.Load(nil)
+}
+
+// A directReader reads from a file in chunks of the size of its buffer.
+type directReader struct {
+	file *os.File
+	buf  []byte
+	pos  int
+}
+
+func (r *directReader) Read(p []byte) (n int, err error) {
+	if r.pos == len(r.buf) {
+		// Direct reads must fill the whole, aligned buffer.
+		// Only the final read, at the end of the file, may be short.
+		n, err = io.ReadFull(r.file, r.buf[:cap(r.buf)])
+		if err == io.ErrUnexpectedEOF {
+			err = nil
+		}
+		r.buf, r.pos = r.buf[:n], 0
+		if n == 0 {
+			if err == nil {
+				err = io.EOF
+			}
+			return 0, err
+		}
+	}
+	n = copy(p, r.buf[r.pos:])
+	r.pos += n
+	return n, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nounsafe
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// openDirect opens a file, with O_DIRECT if direct is true and the file
+// system supports it. It reports whether O_DIRECT is in effect.
+func openDirect(path string, flag int, perm os.FileMode, direct bool) (*os.File, bool, error) {
+	if direct {
+		file, err := os.OpenFile(path, flag|syscall.O_DIRECT, perm)
+		if err == nil {
+			return file, true, nil
+		}
+		// Some file systems, such as tmpfs, don't do direct I/O.
+		if e, ok := err.(*os.PathError); !ok || e.Err != syscall.EINVAL {
+			return nil, false, err
+		}
+	}
+	file, err := os.OpenFile(path, flag, perm)
+	return file, false, err
+}
+
+// alignedBuf returns a byte slice of length n aligned to directAlign.
+func alignedBuf(n int) []byte {
+	buf := make([]byte, n+directAlign)
+	off := int(uintptr(unsafe.Pointer(&buf[0])) % directAlign)
+	if off != 0 {
+		off = directAlign - off
+	}
+	return buf[off : off+n : off+n]
+}
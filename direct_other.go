@@ -0,0 +1,28 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux || nounsafe
+// +build !linux nounsafe
+
+package blobloom
+
+import "os"
+
+// openDirect opens a file. Direct I/O is not supported on this platform.
+func openDirect(path string, flag int, perm os.FileMode, direct bool) (*os.File, bool, error) {
+	file, err := os.OpenFile(path, flag, perm)
+	return file, false, err
+}
+
+func alignedBuf(n int) []byte { return make([]byte, n) }
@@ -0,0 +1,65 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpLoadFile(t *testing.T) {
+	t.Parallel()
+
+	// Prefer a disk-backed directory, since tmpfs doesn't do O_DIRECT.
+	dir, err := ioutil.TempDir(".", "blobloom")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, nblocks := range []int{1, 63, 64, 2000, 4097} {
+		f := New(uint64(nblocks)*BlockBits, 5)
+		for _, h := range randomU64(10*nblocks, int64(nblocks)) {
+			f.Add(h)
+		}
+
+		for _, opts := range []DumpOptions{
+			{Comment: "direct"},
+			{Comment: "compressed", Compression: Gzip, EmbedChecksum: true},
+		} {
+			var buf bytes.Buffer
+			_, err := DumpWithOptions(&buf, f, opts)
+			require.NoError(t, err)
+
+			for _, direct := range []bool{false, true} {
+				path := filepath.Join(dir, "filter.bloom")
+				err := DumpFile(path, f, opts, direct)
+				require.NoError(t, err)
+
+				content, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, buf.Bytes(), content)
+
+				g, err := LoadFile(path, direct)
+				require.NoError(t, err)
+				assert.True(t, f.Equals(g))
+			}
+		}
+	}
+}
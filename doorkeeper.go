@@ -0,0 +1,78 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "sync/atomic"
+
+// A Doorkeeper is an admission filter for caches that use TinyLFU-style
+// frequency estimation. Such caches only count a key's accesses in their
+// frequency sketch once the Doorkeeper has seen the key before, so that
+// keys accessed only once don't pollute the sketch.
+//
+// A Doorkeeper forgets keys gradually: after size new keys have been added,
+// it drops the keys from before the previous such period. Reset forgets
+// all keys at once, for caches that reset the Doorkeeper along with their
+// sketch.
+//
+// A Doorkeeper is safe for concurrent use.
+type Doorkeeper struct {
+	// Number of new keys added.
+	// Accessed atomically; keep first for 64-bit alignment.
+	n uint64
+
+	r    *RotatingFilter
+	size uint64
+}
+
+// NewDoorkeeper returns a Doorkeeper that remembers at least the last size
+// new keys, with the given false positive rate. The size is usually the
+// sample size of the cache's frequency sketch, e.g., ten times the number
+// of entries in the cache.
+//
+// NewDoorkeeper panics if size is zero or fpRate is invalid.
+func NewDoorkeeper(size uint64, fpRate float64) *Doorkeeper {
+	if size == 0 {
+		panic("blobloom: Doorkeeper size must be positive")
+	}
+	// Each generation needs only half the target rate,
+	// since keys are looked up in two of them.
+	config := Config{Capacity: size, FPRate: fpRate / 2}
+	return &Doorkeeper{r: NewRotating(config, 2), size: size}
+}
+
+// AddIfAbsent adds a key with hash value h if the Doorkeeper does not have
+// it yet, and reports whether it did so. It returns false for keys that
+// were added before, and for false positives.
+func (d *Doorkeeper) AddIfAbsent(h uint64) bool {
+	if !d.r.addIfAbsent(h) {
+		return false
+	}
+	if atomic.AddUint64(&d.n, 1)%d.size == 0 {
+		d.r.Rotate()
+	}
+	return true
+}
+
+// Has reports whether a key with hash value h has been added
+// and not forgotten. It may return a false positive.
+func (d *Doorkeeper) Has(h uint64) bool { return d.r.Has(h) }
+
+// Reset makes the Doorkeeper forget all keys.
+func (d *Doorkeeper) Reset() {
+	for i := 0; i < d.r.Generations(); i++ {
+		d.r.Rotate()
+	}
+	atomic.StoreUint64(&d.n, 0)
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoorkeeper(t *testing.T) {
+	t.Parallel()
+
+	const size = 1000
+	d := NewDoorkeeper(size, 1e-6)
+	hashes := randomU64(3*size, 0xd00)
+
+	for _, h := range hashes[:size] {
+		assert.True(t, d.AddIfAbsent(h))
+	}
+	for _, h := range hashes[:size] {
+		assert.False(t, d.AddIfAbsent(h))
+		assert.True(t, d.Has(h))
+	}
+
+	// The first size keys are remembered through the next period,
+	// then forgotten.
+	for _, h := range hashes[size : 2*size] {
+		assert.True(t, d.AddIfAbsent(h))
+	}
+	for _, h := range hashes[:size] {
+		assert.False(t, d.Has(h))
+	}
+	for _, h := range hashes[size : 2*size] {
+		assert.True(t, d.Has(h))
+	}
+
+	d.Reset()
+	for _, h := range hashes[size : 2*size] {
+		assert.False(t, d.Has(h))
+	}
+	assert.True(t, d.AddIfAbsent(hashes[0]))
+
+	assert.Panics(t, func() { NewDoorkeeper(0, .01) })
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by this package wrap one of the following errors, or
+// ErrInvalidSignature, where appropriate, so they can be checked with
+// errors.Is. Errors from an underlying io.Reader or io.Writer, such as
+// io.ErrUnexpectedEOF for a truncated dump, are returned unchanged.
+var (
+	// ErrFormat is wrapped by errors for input that is not a valid dump,
+	// or that uses features this version of the package does not support.
+	ErrFormat = errors.New("blobloom: invalid format")
+
+	// ErrChecksum is wrapped by errors for data that fails an integrity
+	// check, such as ErrInvalidSignature.
+	ErrChecksum = errors.New("blobloom: checksum mismatch")
+
+	// ErrTooLarge is wrapped by errors for filters or fields that exceed
+	// the package's size limits.
+	ErrTooLarge = errors.New("blobloom: too large")
+
+	// ErrShapeMismatch is wrapped by errors for filters, dumps or partitions
+	// whose size or parameters don't match those they are combined with.
+	ErrShapeMismatch = errors.New("blobloom: shape mismatch")
+)
+
+// ErrClosed is returned by operations on resource-backed filters,
+// such as MappedFilter, after they have been closed.
+var ErrClosed = errors.New("blobloom: filter already closed")
+
+// ErrLocked is returned when opening a file-backed filter for writing
+// while another writer holds it open.
+var ErrLocked = errors.New("blobloom: filter is locked by another writer")
+
+// A kindError is an error with its own message that wraps
+// one of the sentinel errors.
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string { return e.msg }
+func (e *kindError) Unwrap() error { return e.kind }
+
+// errorf formats an error message and returns an error with that message
+// that wraps kind.
+func errorf(kind error, format string, args ...interface{}) error {
+	return &kindError{msg: fmt.Sprintf(format, args...), kind: kind}
+}
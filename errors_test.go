@@ -0,0 +1,55 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorKinds(t *testing.T) {
+	t.Parallel()
+
+	f := New(4*BlockBits, 3)
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "")
+	require.NoError(t, err)
+	dump := buf.Bytes()
+
+	_, err = NewLoader(strings.NewReader("not a dump, but 64 bytes long......................................"))
+	assert.True(t, errors.Is(err, ErrFormat))
+	assert.Equal(t, "blobloom: not a Bloom filter dump", err.Error())
+
+	bad := append([]byte(nil), dump...)
+	bad[9] = 0x80
+	_, err = NewLoader(bytes.NewReader(bad))
+	assert.True(t, errors.Is(err, ErrFormat))
+
+	_, err = Dump(&buf, f, strings.Repeat("x", 45))
+	assert.True(t, errors.Is(err, ErrTooLarge))
+
+	l, err := NewLoader(bytes.NewReader(dump))
+	require.NoError(t, err)
+	_, err = l.Load(New(8*BlockBits, 3))
+	assert.True(t, errors.Is(err, ErrShapeMismatch))
+	assert.False(t, errors.Is(err, ErrFormat))
+
+	assert.True(t, errors.Is(ErrInvalidSignature, ErrChecksum))
+}
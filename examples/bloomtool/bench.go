@@ -0,0 +1,142 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greatroar/blobloom"
+)
+
+func bench(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	var (
+		capacity = flags.Uint64("capacity", 1e6, "expected `number` of keys")
+		fpr      = flags.Float64("fpr", 1e-3, "desired false positive `rate`")
+		impl     = flags.String("impl", "", "benchmark only this `implementation` of bulk operations")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: bloomtool bench [flags]")
+	}
+	if *capacity < 1 {
+		return fmt.Errorf("-capacity must be at least 1, got %d", *capacity)
+	}
+
+	impls := blobloom.Implementations()
+	if *impl != "" {
+		if err := blobloom.SetImplementation(*impl); err != nil {
+			return fmt.Errorf("%v (have %s)", err, strings.Join(impls, ", "))
+		}
+		impls = []string{*impl}
+	}
+
+	config := blobloom.Config{Capacity: *capacity, FPRate: *fpr}
+	nbits, nhashes := blobloom.Optimize(config)
+	fmt.Printf("capacity %d, FPR %g: %d bits (%.1f MiB), %d hashes\n\n",
+		*capacity, *fpr, nbits, float64(nbits)/(8<<20), nhashes)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	defer w.Flush()
+
+	// Per-key operations don't depend on the implementation.
+	hashes := make([]uint64, *capacity)
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := range hashes {
+		hashes[i] = rnd.Uint64()
+	}
+	f := blobloom.NewOptimized(config)
+	s := blobloom.NewSyncOptimized(config)
+
+	fmt.Fprintln(w, "operation\tns/op\t")
+	perKey(w, "Filter.Add", hashes, f.Add)
+	perKey(w, "Filter.Has (hit)", hashes, func(h uint64) { f.Has(h) })
+	perKey(w, "Filter.Has (miss)", hashes, func(h uint64) { f.Has(^h) })
+	perKey(w, "SyncFilter.Add", hashes, s.Add)
+	perKey(w, "SyncFilter.Has", hashes, func(h uint64) { s.Has(h) })
+	fmt.Fprintln(w, "\t\t")
+
+	fmt.Fprintln(w, "implementation\tUnion ms\tIntersect ms\tCardinality ms\t")
+	g := blobloom.NewOptimized(config)
+	var fastest string
+	var best time.Duration
+	for _, name := range impls {
+		if err := blobloom.SetImplementation(name); err != nil {
+			return err
+		}
+		u := timeOp(func() { g.Union(f) })
+		i := timeOp(func() { g.Intersect(f) })
+		c := timeOp(func() { f.Cardinality() })
+		fmt.Fprintf(w, "%s\t%.3f\t%.3f\t%.3f\t\n", name, ms(u), ms(i), ms(c))
+
+		if total := u + i + c; fastest == "" || total < best {
+			fastest, best = name, total
+		}
+	}
+	if err := blobloom.SetImplementation(fastest); err != nil {
+		return err
+	}
+	w.Flush()
+
+	// Macro benchmark: build a filter to capacity, then measure its FPR.
+	start := time.Now()
+	f = blobloom.NewOptimized(config)
+	for _, h := range hashes {
+		f.Add(h)
+	}
+	build := time.Since(start)
+	fp := 0
+	const nqueries = 1e6
+	for i := 0; i < nqueries; i++ {
+		if f.Has(rnd.Uint64()) {
+			fp++
+		}
+	}
+	measured := float64(fp) / nqueries
+
+	fmt.Printf("\nbuilding a full filter took %v; measured FPR %.3g\n", build, measured)
+	fmt.Printf("fastest implementation of bulk operations: %s\n", fastest)
+	return nil
+}
+
+func perKey(w *tabwriter.Writer, name string, hashes []uint64, op func(uint64)) {
+	r := testing.Benchmark(func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			op(hashes[i%len(hashes)])
+		}
+	})
+	fmt.Fprintf(w, "%s\t%.1f\t\n", name, float64(r.T.Nanoseconds())/float64(r.N))
+}
+
+// timeOp returns the time per call of op.
+func timeOp(op func()) time.Duration {
+	r := testing.Benchmark(func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			op()
+		}
+	})
+	return r.T / time.Duration(r.N)
+}
+
+func ms(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
@@ -0,0 +1,71 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Bloomtool is a command-line utility for working with dumped Bloom filters.
+//
+// Usage:
+//
+//	bloomtool bench [-capacity n] [-fpr p] [-impl name]
+//	bloomtool serve [-listen addr] [-writable] [-persist interval] file.bloom
+//
+// Bench benchmarks the package on the host for a filter of the given
+// capacity and false positive rate and reports which implementation of
+// bulk operations is fastest.
+//
+// Serve answers queries about the filter in file.bloom over HTTP.
+// Keys are given as 64-bit hash values, in decimal or with a 0x prefix,
+// since bloomtool cannot know which hash function produced the filter.
+// The endpoints are
+//
+//	GET  /has?h=...&h=...  JSON array of booleans, one per hash
+//	POST /add?h=...&h=...  adds hashes (only with -writable)
+//	GET  /stats            JSON object with size and cardinality
+//
+// With -writable, the filter is written back to file.bloom on SIGINT or
+// SIGTERM, and also at the -persist interval if that is positive.
+// The file keeps its mode and the options it was dumped with.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: bloomtool command [arguments]
+
+commands:
+	bench	benchmark filter operations on this machine
+	serve	serve queries about a dumped filter over HTTP`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "bench":
+		err = bench(args)
+	case "serve":
+		err = serve(args)
+	default:
+		fmt.Fprintf(os.Stderr, "bloomtool: unknown command %q\n%s\n", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bloomtool:", err)
+		os.Exit(1)
+	}
+}
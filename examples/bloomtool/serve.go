@@ -0,0 +1,229 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/greatroar/blobloom"
+)
+
+// A filter is the part of the Filter and SyncFilter APIs used by serve.
+type filter interface {
+	Cardinality() float64
+	Has(uint64) bool
+	NumBits() uint64
+}
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	var (
+		listen   = flags.String("listen", ":8080", "`address` to listen on")
+		writable = flags.Bool("writable", false, "allow adding hashes, and write the filter back to file on exit")
+		persist  = flags.Duration("persist", 0, "also write filter back to file at this `interval` (requires -writable)")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: bloomtool serve [flags] file.bloom")
+	}
+	if *persist > 0 && !*writable {
+		return fmt.Errorf("-persist requires -writable")
+	}
+	path := flags.Arg(0)
+
+	s, err := loadServer(path, *writable)
+	if err != nil {
+		return err
+	}
+
+	// A writable filter is always written back on shutdown,
+	// so that adds are not lost.
+	if *writable {
+		if *persist > 0 {
+			go s.persistEvery(*persist)
+		}
+
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			if err := s.save(); err != nil {
+				log.Print(err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}()
+	}
+
+	log.Printf("serving %s on %s", path, *listen)
+	return http.ListenAndServe(*listen, s)
+}
+
+type server struct {
+	path string
+	mode os.FileMode          // Mode of the file at path.
+	opts blobloom.DumpOptions // Options of the dump at path.
+	f    filter
+	sync *blobloom.SyncFilter // Non-nil if writable.
+
+	mu sync.Mutex // Serializes save.
+}
+
+func loadServer(path string, writable bool) (*server, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	l, err := blobloom.NewLoader(file)
+	if err != nil {
+		return nil, err
+	}
+	s := &server{path: path, mode: info.Mode().Perm(), opts: l.DumpOptions()}
+
+	if writable {
+		s.sync, err = l.LoadSync(nil)
+		s.f = s.sync
+	} else {
+		s.f, err = l.Load(nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/has":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		hashes, ok := parseHashes(w, r)
+		if !ok {
+			return
+		}
+		found := make([]bool, len(hashes))
+		for i, h := range hashes {
+			found[i] = s.f.Has(h)
+		}
+		writeJSON(w, found)
+
+	case "/add":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if s.sync == nil {
+			http.Error(w, "filter is read-only", http.StatusForbidden)
+			return
+		}
+		hashes, ok := parseHashes(w, r)
+		if !ok {
+			return
+		}
+		for _, h := range hashes {
+			s.sync.Add(h)
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case "/stats":
+		writeJSON(w, struct {
+			Bits        uint64  `json:"bits"`
+			Cardinality float64 `json:"cardinality"`
+			Comment     string  `json:"comment"`
+			Writable    bool    `json:"writable"`
+		}{s.f.NumBits(), s.f.Cardinality(), s.opts.Comment, s.sync != nil})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func parseHashes(w http.ResponseWriter, r *http.Request) ([]uint64, bool) {
+	params := r.URL.Query()["h"]
+	hashes := make([]uint64, len(params))
+	for i, p := range params {
+		h, err := strconv.ParseUint(p, 0, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid hash %q", p), http.StatusBadRequest)
+			return nil, false
+		}
+		hashes[i] = h
+	}
+	return hashes, true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *server) persistEvery(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := s.save(); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+// save atomically replaces the file at s.path by a dump of s.sync,
+// written with the same options and file mode as the original.
+func (s *server) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), ".bloomtool-*")
+	if err != nil {
+		return err
+	}
+	_, err = blobloom.DumpSyncWithOptions(tmp, s.sync, s.opts)
+	if err == nil {
+		err = tmp.Chmod(s.mode)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
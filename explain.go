@@ -0,0 +1,77 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// An Explanation describes a lookup in a Filter. It is meant as a debugging
+// aid for unexpected positives or negatives. See Filter.Explain.
+type Explanation struct {
+	Hash  uint64 // Hash value used for the lookup, after premixing.
+	Block uint64 // Index of the selected block.
+
+	// Positions of the bits probed within the block, in probe order.
+	// A key is present if all of these are set.
+	Bits []uint32
+
+	// Index into Bits of the first probe that found an unset bit,
+	// or -1 if all bits are set.
+	FailedProbe int
+}
+
+// Found reports whether the lookup found the key, which is what Has returns.
+func (e Explanation) Found() bool { return e.FailedProbe < 0 }
+
+func (e Explanation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "hash %#016x, block %d, bits", e.Hash, e.Block)
+	for i, bit := range e.Bits {
+		fmt.Fprintf(&sb, " %d", bit)
+		if i == e.FailedProbe {
+			sb.WriteString(" (unset)")
+		}
+	}
+	fmt.Fprintf(&sb, ": found = %t", e.Found())
+	return sb.String()
+}
+
+// Explain performs the same lookup as Has(h), but returns an Explanation
+// of it instead of a boolean. Unlike Has, it probes all bits, so Bits is
+// complete even if the key is not found.
+func (f *Filter) Explain(h uint64) Explanation {
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	e := Explanation{
+		Hash:        h,
+		Block:       reducerange(blk, uint64(len(f.b))),
+		Bits:        make([]uint32, 0, f.k-1),
+		FailedProbe: -1,
+	}
+	b := &f.b[e.Block]
+
+	for i := 1; i < f.k; i++ {
+		h1, h2 = doublehash(h1, h2, i)
+		e.Bits = append(e.Bits, h1%BlockBits)
+		if e.FailedProbe < 0 && !b.getbit(h1) {
+			e.FailedProbe = i - 1
+		}
+	}
+	return e
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	f := New(1<<14, 5)
+	f.premix = true
+	hashes := randomU64(2000, 0xe4)
+	for _, h := range hashes[:1000] {
+		f.Add(h)
+	}
+
+	for _, h := range hashes {
+		e := f.Explain(h)
+		assert.Equal(t, f.Has(h), e.Found())
+		assert.Len(t, e.Bits, f.k-1)
+
+		b := &f.b[e.Block]
+		for i, bit := range e.Bits {
+			if i < e.FailedProbe || e.FailedProbe < 0 {
+				assert.True(t, b.getbit(bit))
+			} else if i == e.FailedProbe {
+				assert.False(t, b.getbit(bit))
+			}
+		}
+	}
+
+	f = New(BlockBits, 3)
+	e := f.Explain(0)
+	assert.Equal(t, Explanation{Bits: []uint32{0, 1}, FailedProbe: 0}, e)
+	assert.Equal(t, "hash 0x0000000000000000, block 0, bits 0 (unset) 1: found = false", e.String())
+}
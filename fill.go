@@ -0,0 +1,132 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// FillOptions holds optional settings for FillFromChannel.
+type FillOptions struct {
+	// Number of goroutines that add hashes to the filter.
+	// The default is runtime.GOMAXPROCS(0).
+	Workers int
+
+	// Maximum number of hashes that a worker adds in one batch.
+	// The default is 256. Workers don't wait for batches to fill up.
+	BatchSize int
+}
+
+// FillStats reports the work done by FillFromChannel.
+type FillStats struct {
+	Added   uint64 // Number of hashes added.
+	Present uint64 // Number of hashes that were already present.
+}
+
+// FillFromChannel adds hashes received from ch to f until ch is closed
+// or ctx is done. In the latter case, it returns ctx.Err().
+// Hashes that have been received are always added.
+//
+// Workers only receive from ch when they are ready to add more hashes,
+// so a slow filter exerts back-pressure on the senders.
+func FillFromChannel(ctx context.Context, f *SyncFilter, ch <-chan uint64, opts FillOptions) (FillStats, error) {
+	nworkers := opts.Workers
+	if nworkers <= 0 {
+		nworkers = runtime.GOMAXPROCS(0)
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 256
+	}
+
+	var (
+		stats    = make([]FillStats, nworkers)
+		canceled = make([]bool, nworkers)
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < nworkers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			canceled[i] = fillWorker(ctx, f, ch, batchSize, &stats[i])
+		}(i)
+	}
+	wg.Wait()
+
+	var total FillStats
+	var err error
+	for i, s := range stats {
+		total.Added += s.Added
+		total.Present += s.Present
+		if canceled[i] {
+			err = ctx.Err()
+		}
+	}
+	return total, err
+}
+
+// fillWorker receives batches from ch and adds them to f.
+// It reports whether it stopped because ctx was done.
+func fillWorker(ctx context.Context, f *SyncFilter, ch <-chan uint64, batchSize int, stats *FillStats) bool {
+	var (
+		batch   = make([]uint64, 0, batchSize)
+		present []bool
+	)
+	flush := func() {
+		present = f.TestAndAddBatch(batch, present[:0])
+		stats.Added += uint64(len(batch))
+		for _, p := range present {
+			if p {
+				stats.Present++
+			}
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		// Wait for one hash, then take whatever else is ready.
+		select {
+		case <-ctx.Done():
+			return true
+		case h, ok := <-ch:
+			if !ok {
+				return false
+			}
+			batch = append(batch, h)
+		}
+
+		closed := false
+	collect:
+		for len(batch) < cap(batch) {
+			select {
+			case h, ok := <-ch:
+				if !ok {
+					closed = true
+					break collect
+				}
+				batch = append(batch, h)
+			default:
+				break collect
+			}
+		}
+
+		flush()
+		if closed {
+			return false
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFillFromChannel(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(10000, 0xf111)
+	ch := make(chan uint64)
+	go func() {
+		for _, h := range hashes {
+			ch <- h
+		}
+		for _, h := range hashes[:100] {
+			ch <- h
+		}
+		close(ch)
+	}()
+
+	f := NewSync(1<<20, 6)
+	stats, err := FillFromChannel(context.Background(), f, ch, FillOptions{Workers: 3, BatchSize: 16})
+	require.NoError(t, err)
+	assert.EqualValues(t, len(hashes)+100, stats.Added)
+	assert.GreaterOrEqual(t, stats.Present, uint64(100))
+
+	ref := NewSync(1<<20, 6)
+	for _, h := range hashes {
+		ref.Add(h)
+		assert.True(t, f.Has(h))
+	}
+	assert.Equal(t, ref.b, f.b)
+}
+
+func TestFillFromChannelCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan uint64)
+	go func() {
+		ch <- 1
+		ch <- 2
+		cancel()
+	}()
+
+	f := NewSync(1<<10, 3)
+	stats, err := FillFromChannel(ctx, f, ch, FillOptions{})
+	assert.Equal(t, context.Canceled, err)
+	assert.EqualValues(t, 2, stats.Added)
+	assert.True(t, f.Has(1))
+	assert.True(t, f.Has(2))
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+// Add128 inserts a key with 128-bit hash value (hi, lo) into f.
+//
+// The lower half selects the block and the upper half the bits within it,
+// so that the two are independent. With Add, both are derived from a single
+// 64-bit hash.
+//
+// Keys added with Add128 can only be found with Has128, not with Has.
+func (f *Filter) Add128(hi, lo uint64) {
+	if f.premix {
+		hi, lo = mix64(hi), mix64(lo)
+	}
+	h1, h2 := uint32(hi>>32), uint32(hi)
+	b := getblock(f.b, uint32(lo))
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		b.setbit(bit)
+		return true
+	})
+}
+
+// Has128 reports whether a key with 128-bit hash value (hi, lo)
+// has been added by Add128. It may return a false positive.
+func (f *Filter) Has128(hi, lo uint64) bool {
+	if f.premix {
+		hi, lo = mix64(hi), mix64(lo)
+	}
+	h1, h2 := uint32(hi>>32), uint32(hi)
+	b := getblock(f.b, uint32(lo))
+	return probe(h1, h2, f.k, b.getbit)
+}
+
+// Add128 is like Filter.Add128, but for SyncFilters.
+func (f *SyncFilter) Add128(hi, lo uint64) {
+	if f.premix {
+		hi, lo = mix64(hi), mix64(lo)
+	}
+	h1, h2 := uint32(hi>>32), uint32(hi)
+	b := getblock(f.b, uint32(lo))
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		setbitAtomic(b, bit)
+		return true
+	})
+}
+
+// Has128 is like Filter.Has128, but for SyncFilters.
+func (f *SyncFilter) Has128(hi, lo uint64) bool {
+	if f.premix {
+		hi, lo = mix64(hi), mix64(lo)
+	}
+	h1, h2 := uint32(hi>>32), uint32(hi)
+	b := getblock(f.b, uint32(lo))
+	return probe(h1, h2, f.k, func(bit uint32) bool {
+		return getbitAtomic(b, bit)
+	})
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash128(t *testing.T) {
+	t.Parallel()
+
+	const n = 1e4
+	hashes := randomU64(4*n, 0x128)
+
+	cfg := Config{Capacity: n, FPRate: 1e-4}
+	f := NewOptimized(cfg)
+	s := NewSyncOptimized(cfg)
+
+	for i := 0; i < n; i++ {
+		hi, lo := hashes[2*i], hashes[2*i+1]
+		f.Add128(hi, lo)
+		s.Add128(hi, lo)
+	}
+	assert.Equal(t, f.b, s.b)
+
+	fp := 0
+	for i := 0; i < 2*n; i++ {
+		hi, lo := hashes[2*i], hashes[2*i+1]
+		found := f.Has128(hi, lo)
+		assert.Equal(t, found, s.Has128(hi, lo))
+		if i < n {
+			assert.True(t, found)
+		} else if found {
+			fp++
+		}
+	}
+
+	fpr := float64(fp) / n
+	t.Logf("FPR = %f", fpr)
+	assert.Less(t, fpr, 3*cfg.FPRate)
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "fmt"
+
+// A HashChecker inspects a sample of the hash values passed to it and
+// reports when they look like raw IDs, counters or timestamps rather than
+// the output of a good 64-bit hash function.
+//
+// Such inputs are a common, silent misuse of Bloom filters: everything
+// appears to work, but the false positive rate is much higher than
+// configured. Premixing (see Config.Premix) fixes the problem.
+//
+// A HashChecker is not safe for concurrent use.
+type HashChecker struct {
+	every  uint64
+	report func(reason string)
+
+	ncalls  uint64
+	samples [hashCheckWindow]uint64
+	n       int // Number of samples collected.
+}
+
+// Number of samples in a verdict.
+const hashCheckWindow = 256
+
+// NewHashChecker returns a HashChecker that samples one in every hash values
+// it is passed. Each time it has collected 256 samples, it calls report
+// with a description of the problem if the samples look suspicious.
+//
+// The sampling rate is silently increased to one if a lower value is given.
+func NewHashChecker(every int, report func(reason string)) *HashChecker {
+	if every < 1 {
+		every = 1
+	}
+	return &HashChecker{every: uint64(every), report: report}
+}
+
+// Check passes a hash value to c. Call it with the same values
+// that are passed to a Filter's Add or Has methods.
+func (c *HashChecker) Check(h uint64) {
+	c.ncalls++
+	if c.ncalls%c.every != 0 {
+		return
+	}
+
+	c.samples[c.n] = h
+	c.n++
+	if c.n < len(c.samples) {
+		return
+	}
+	c.n = 0
+
+	if reason := suspiciousHashes(&c.samples); reason != "" {
+		c.report(reason)
+	}
+}
+
+// suspiciousHashes returns a description of the problem with a sample of
+// hash values, or the empty string if they look like proper hashes.
+//
+// The tests are chosen so that a false alarm for good hashes is all but
+// impossible (probability < 2^-64).
+func suspiciousHashes(p *[hashCheckWindow]uint64) string {
+	var and, or uint64 = ^uint64(0), 0
+	small := 0 // Count of small deltas between consecutive samples.
+	var top [256]bool
+	distinct := 0
+
+	for i, h := range p {
+		and &= h
+		or |= h
+
+		if i > 0 {
+			d := h - p[i-1]
+			if d < 1<<32 || -d < 1<<32 {
+				small++
+			}
+		}
+
+		if t := h >> 56; !top[t] {
+			top[t] = true
+			distinct++
+		}
+	}
+
+	switch {
+	case or>>32 == 0:
+		return "hash values have no bits set in their upper half"
+	case and|^or != 0:
+		return fmt.Sprintf("hash values have constant bits %#016x", and|^or)
+	case small > len(p)/2:
+		return "hash values are sequential or close together"
+	case distinct < 64:
+		// With 256 good hashes, we'd expect ca. 162 distinct top bytes.
+		return fmt.Sprintf("hash values have only %d distinct top bytes", distinct)
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashChecker(t *testing.T) {
+	t.Parallel()
+
+	var reasons []string
+	c := NewHashChecker(3, func(reason string) {
+		reasons = append(reasons, reason)
+	})
+
+	r := rand.New(rand.NewSource(0xc4ec))
+	for i := 0; i < 100*hashCheckWindow; i++ {
+		c.Check(r.Uint64())
+	}
+	assert.Empty(t, reasons)
+
+	for i := 0; i < 3*hashCheckWindow; i++ {
+		c.Check(uint64(i))
+	}
+	assert.Len(t, reasons, 1)
+	t.Log(reasons)
+
+	for _, gen := range []func(i int) uint64{
+		func(i int) uint64 { return uint64(r.Uint32()) },                        // 32-bit hash.
+		func(i int) uint64 { return uint64(time.Now().UnixNano()) + uint64(i) }, // Timestamps.
+		func(i int) uint64 { return r.Uint64() | 1<<63 },                        // Constant bit.
+		func(i int) uint64 { return r.Uint64() >> 2 << 2 },                      // Constant bits.
+		func(i int) uint64 { return r.Uint64() &^ (0x3f << 56) },                // Few top bytes.
+	} {
+		reasons = reasons[:0]
+		c := NewHashChecker(1, func(reason string) {
+			reasons = append(reasons, reason)
+		})
+		for i := 0; i < hashCheckWindow; i++ {
+			c.Check(gen(i))
+		}
+		assert.Len(t, reasons, 1)
+		t.Log(reasons)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A Snapshot records the state of a filter at some point in time.
+type Snapshot struct {
+	Time        time.Time
+	Cardinality float64
+	Stats       BlockStats
+}
+
+// A History keeps the most recent Snapshots of a filter in a ring buffer
+// of fixed size, to track how fast the filter fills up.
+//
+// A History is safe for concurrent use.
+type History struct {
+	mu    sync.Mutex
+	ring  []Snapshot
+	next  int // Index of the next Snapshot in ring.
+	count int // Number of Snapshots in ring.
+	clock Clock
+}
+
+// NewHistory returns an empty History that keeps up to size Snapshots.
+// It panics if size < 2.
+func NewHistory(size int) *History {
+	if size < 2 {
+		panic("blobloom: History size must be at least two")
+	}
+	return &History{ring: make([]Snapshot, size), clock: SystemClock}
+}
+
+// WithClock sets the Clock used by Record and RecordEvery and returns h.
+// It must be called before either of those.
+func (h *History) WithClock(c Clock) *History {
+	h.clock = c
+	return h
+}
+
+// A Snapshotter is a filter that a History can take Snapshots of,
+// such as a Filter or SyncFilter.
+type Snapshotter interface {
+	Cardinality() float64
+	Stats() BlockStats
+}
+
+// Record adds a Snapshot of f, taken now, dropping the oldest Snapshot
+// if h is full.
+func (h *History) Record(f Snapshotter) {
+	h.Add(Snapshot{Time: h.clock.Now(), Cardinality: f.Cardinality(), Stats: f.Stats()})
+}
+
+// Add adds s, which must be newer than the Snapshots already in h,
+// dropping the oldest Snapshot if h is full.
+func (h *History) Add(s Snapshot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.ring[h.next] = s
+	h.next = (h.next + 1) % len(h.ring)
+	if h.count < len(h.ring) {
+		h.count++
+	}
+}
+
+// RecordEvery calls Record(f) every interval until ctx is done.
+func (h *History) RecordEvery(ctx context.Context, interval time.Duration, f Snapshotter) {
+	t := h.clock.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C():
+			h.Record(f)
+		}
+	}
+}
+
+// Snapshots returns the Snapshots in h, oldest first.
+func (h *History) Snapshots() []Snapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := make([]Snapshot, 0, h.count)
+	for i := h.next - h.count; i < h.next; i++ {
+		j := i
+		if j < 0 {
+			j += len(h.ring)
+		}
+		s = append(s, h.ring[j])
+	}
+	return s
+}
+
+// oldestNewest returns the oldest and newest Snapshots in h.
+// The caller must hold h.mu.
+func (h *History) oldestNewest() (oldest, newest Snapshot, ok bool) {
+	if h.count < 2 {
+		return oldest, newest, false
+	}
+	first := h.next - h.count
+	if first < 0 {
+		first += len(h.ring)
+	}
+	last := h.next - 1
+	if last < 0 {
+		last += len(h.ring)
+	}
+	return h.ring[first], h.ring[last], true
+}
+
+// GrowthRate returns the average rate at which the estimated cardinality
+// grew, in keys per second, from the oldest to the newest Snapshot.
+// It returns zero if h has fewer than two Snapshots.
+func (h *History) GrowthRate() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	oldest, newest, ok := h.oldestNewest()
+	if !ok {
+		return 0
+	}
+	dt := newest.Time.Sub(oldest.Time).Seconds()
+	if dt <= 0 {
+		return 0
+	}
+	return (newest.Cardinality - oldest.Cardinality) / dt
+}
+
+// TimeToCapacity estimates the time from the newest Snapshot until the
+// cardinality reaches capacity, extrapolating the GrowthRate.
+// If the filter is not growing, it returns false.
+//
+// Since the false positive rate rises steeply once a filter is filled beyond
+// the capacity it was designed for, this is the time left until the filter
+// needs to be rotated or rebuilt.
+func (h *History) TimeToCapacity(capacity float64) (time.Duration, bool) {
+	rate := h.GrowthRate()
+
+	h.mu.Lock()
+	_, newest, _ := h.oldestNewest()
+	h.mu.Unlock()
+
+	switch {
+	case rate <= 0:
+		return 0, false
+	case newest.Cardinality >= capacity:
+		return 0, true
+	}
+	secs := (capacity - newest.Cardinality) / rate
+	return time.Duration(secs * float64(time.Second)), true
+}
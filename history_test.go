@@ -0,0 +1,85 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	h := NewHistory(3)
+	assert.Empty(t, h.Snapshots())
+	assert.Equal(t, 0.0, h.GrowthRate())
+	_, ok := h.TimeToCapacity(100)
+	assert.False(t, ok)
+
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		h.Add(Snapshot{
+			Time:        t0.Add(time.Duration(i) * time.Minute),
+			Cardinality: 600 * float64(i),
+		})
+	}
+
+	s := h.Snapshots()
+	assert.Len(t, s, 3)
+	assert.Equal(t, t0.Add(2*time.Minute), s[0].Time)
+	assert.Equal(t, t0.Add(4*time.Minute), s[2].Time)
+
+	assert.Equal(t, 10.0, h.GrowthRate())
+	d, ok := h.TimeToCapacity(3000)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, d)
+	d, ok = h.TimeToCapacity(1000)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	assert.Panics(t, func() { NewHistory(1) })
+}
+
+func TestHistoryRecord(t *testing.T) {
+	t.Parallel()
+
+	f := NewSyncOptimized(Config{Capacity: 1000, FPRate: 1e-3})
+	h := NewHistory(10)
+	h.Record(f)
+	for _, x := range randomU64(500, 0x415) {
+		f.Add(x)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.RecordEvery(ctx, time.Millisecond, f)
+		close(done)
+	}()
+	for len(h.Snapshots()) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	s := h.Snapshots()
+	assert.Equal(t, 0.0, s[0].Cardinality)
+	assert.InDelta(t, 500, s[1].Cardinality, 25)
+	assert.Equal(t, f.Stats(), s[1].Stats)
+	assert.Greater(t, h.GrowthRate(), 0.0)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpfilter serves lookups in a Bloom filter dump stored behind
+// an HTTP server that supports range requests, such as an object store.
+// Only the blocks needed for lookups are fetched, so several services can
+// share one huge filter without keeping local copies.
+//
+// For S3 and similar stores, use a presigned URL for the dump.
+package httpfilter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/greatroar/blobloom"
+)
+
+// A ReaderAt reads a remote file with HTTP range requests.
+// It is safe for concurrent use.
+type ReaderAt struct {
+	Client *http.Client // If nil, http.DefaultClient is used.
+	URL    string
+
+	// Context for requests. If nil, context.Background() is used.
+	Context context.Context
+}
+
+var _ io.ReaderAt = (*ReaderAt)(nil)
+
+// ReadAt implements io.ReaderAt by performing a GET request
+// for the byte range [off, off+len(p)).
+func (r *ReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequest(http.MethodGet, r.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		return 0, io.EOF
+	case http.StatusOK:
+		// The server ignored the range. Reading the whole file
+		// would defeat the purpose.
+		return 0, fmt.Errorf("httpfilter: %s does not support range requests", r.URL)
+	default:
+		return 0, fmt.Errorf("httpfilter: GET %s: %s", r.URL, resp.Status)
+	}
+
+	n, err = io.ReadFull(resp.Body, p)
+	if err == io.ErrUnexpectedEOF {
+		// Range extends past the end of the file.
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Open returns a LazyFilter for the uncompressed filter dump at url,
+// caching up to cacheBlocks blocks. The client may be nil.
+//
+// Use the LazyFilter's HasBatch method to look up many keys at once;
+// it fetches the blocks they need in a few concurrent requests.
+func Open(client *http.Client, url string, cacheBlocks int) (*blobloom.LazyFilter, error) {
+	return blobloom.NewLazy(&ReaderAt{Client: client, URL: url}, cacheBlocks)
+}
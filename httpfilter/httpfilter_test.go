@@ -0,0 +1,110 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpfilter_test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/greatroar/blobloom"
+	"github.com/greatroar/blobloom/httpfilter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	f := blobloom.NewOptimized(blobloom.Config{Capacity: 10000, FPRate: 1e-3})
+	r := rand.New(rand.NewSource(0x4772))
+	hashes := make([]uint64, 20000)
+	for i := range hashes {
+		hashes[i] = r.Uint64()
+	}
+	for _, h := range hashes[:10000] {
+		f.Add(h)
+	}
+	var buf bytes.Buffer
+	_, err := blobloom.Dump(&buf, f, "remote")
+	require.NoError(t, err)
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(buf.Bytes()))
+	}))
+	defer srv.Close()
+
+	lf, err := httpfilter.Open(srv.Client(), srv.URL, 1024)
+	require.NoError(t, err)
+
+	for _, h := range hashes[:100] {
+		found, err := lf.Has(h)
+		require.NoError(t, err)
+		assert.Equal(t, f.Has(h), found)
+	}
+
+	found, err := lf.HasBatch(hashes, nil)
+	require.NoError(t, err)
+	for i, h := range hashes {
+		assert.Equal(t, f.Has(h), found[i])
+	}
+	before := atomic.LoadInt32(&requests)
+
+	// Everything is cached now.
+	found, err = lf.HasBatch(hashes[:1000], found[:0])
+	require.NoError(t, err)
+	assert.Len(t, found, 1000)
+	assert.Equal(t, before, atomic.LoadInt32(&requests))
+}
+
+func TestReaderAt(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("0123456789")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/norange":
+			w.Write(content)
+		default:
+			http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(content))
+		}
+	}))
+	defer srv.Close()
+
+	r := &httpfilter.ReaderAt{URL: srv.URL}
+	p := make([]byte, 4)
+
+	n, err := r.ReadAt(p, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "3456", string(p[:n]))
+
+	n, err = r.ReadAt(p, 8)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "89", string(p[:n]))
+
+	_, err = r.ReadAt(p, 20)
+	assert.Equal(t, io.EOF, err)
+
+	r.URL = srv.URL + "/norange"
+	_, err = r.ReadAt(p, 0)
+	assert.Error(t, err)
+}
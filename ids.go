@@ -0,0 +1,96 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// An IDLister lists object IDs, by calling fn for each of them, until fn
+// returns an error. It should stop and return an error when ctx is canceled.
+//
+// The signature is shaped after the listing functions of storage backends
+// in backup tools, so that these can be plugged in with a small adapter.
+// fn must not retain id after it returns.
+type IDLister func(ctx context.Context, fn func(id []byte) error) error
+
+// BuildOptions are options for BuildFromIDs.
+type BuildOptions struct {
+	// Hash maps an object ID to a hash value. If nil, the first eight
+	// bytes of the ID are used, in little-endian order. This is only
+	// appropriate when IDs are cryptographic hashes, such as SHA-256.
+	Hash func(id []byte) uint64
+
+	// If Progress is not nil, it is called with the number of IDs added
+	// after every ProgressInterval IDs and once after the last ID.
+	Progress func(n uint64)
+
+	// ProgressInterval defaults to 1<<16 if zero.
+	ProgressInterval uint64
+}
+
+// BuildFromIDs constructs a Filter with NewOptimized(config) and adds to it
+// the hashes of all object IDs listed by list.
+//
+// It returns ctx.Err() if ctx is canceled while listing and any error
+// returned by list. In those cases, the Filter is not returned.
+func BuildFromIDs(ctx context.Context, config Config, list IDLister, opts BuildOptions) (*Filter, error) {
+	interval := opts.ProgressInterval
+	if interval == 0 {
+		interval = 1 << 16
+	}
+
+	f := NewOptimized(config)
+	var n uint64
+
+	err := list(ctx, func(id []byte) error {
+		// Checking every ID would be expensive for large listings.
+		if n%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		var h uint64
+		switch {
+		case opts.Hash != nil:
+			h = opts.Hash(id)
+		case len(id) < 8:
+			return fmt.Errorf("blobloom: object ID %x too short", id)
+		default:
+			h = binary.LittleEndian.Uint64(id)
+		}
+		f.Add(h)
+
+		n++
+		if opts.Progress != nil && n%interval == 0 {
+			opts.Progress(n)
+		}
+		return nil
+	})
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Progress != nil && n%interval != 0 {
+		opts.Progress(n)
+	}
+	return f, nil
+}
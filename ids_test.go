@@ -0,0 +1,79 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listSHA256 lists the SHA-256 hashes of the integers 0 through n-1.
+func listSHA256(n int) IDLister {
+	return func(ctx context.Context, fn func(id []byte) error) error {
+		var buf [8]byte
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint64(buf[:], uint64(i))
+			id := sha256.Sum256(buf[:])
+			if err := fn(id[:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestBuildFromIDs(t *testing.T) {
+	t.Parallel()
+
+	const n = 10000
+	config := Config{Capacity: n, FPRate: 1e-3}
+
+	var progress []uint64
+	f, err := BuildFromIDs(context.Background(), config, listSHA256(n), BuildOptions{
+		Progress:         func(n uint64) { progress = append(progress, n) },
+		ProgressInterval: 3000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3000, 6000, 9000, n}, progress)
+
+	listSHA256(n)(context.Background(), func(id []byte) error {
+		assert.True(t, f.Has(binary.LittleEndian.Uint64(id)))
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f, err = BuildFromIDs(ctx, config, listSHA256(n), BuildOptions{})
+	assert.Nil(t, f)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	short := func(ctx context.Context, fn func(id []byte) error) error {
+		return fn([]byte{1, 2, 3})
+	}
+	_, err = BuildFromIDs(context.Background(), config, short, BuildOptions{})
+	assert.Error(t, err)
+
+	f, err = BuildFromIDs(context.Background(), config, short, BuildOptions{
+		Hash: func(id []byte) uint64 { return uint64(len(id)) },
+	})
+	require.NoError(t, err)
+	assert.True(t, f.Has(3))
+}
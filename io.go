@@ -19,6 +19,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash"
+	"hash/crc32"
 	"io"
 	"strings"
 	"sync/atomic"
@@ -33,7 +35,58 @@ const maxCommentLen = 44
 // format description. It can be used to record the hash function to be used
 // with a Filter.
 func Dump(w io.Writer, f *Filter, comment string) (int64, error) {
-	return dump(w, f.b, f.k, comment)
+	return dump(w, f.b, f.k, f.premix, f.layout, DumpOptions{Comment: comment})
+}
+
+// DumpOptions holds optional settings for DumpWithOptions
+// and DumpSyncWithOptions.
+type DumpOptions struct {
+	// Trigger the "contains filtered or unexported fields" message for
+	// forward compatibility and force the caller to use named fields.
+	_ struct{}
+
+	// Comment is stored in the dump header. See Dump.
+	Comment string
+
+	// If Checksum is not nil, all bytes written are also written to it,
+	// so that a checksum of the dump is computed in the same pass.
+	// The checksum itself is not written.
+	Checksum hash.Hash
+
+	// If EmbedChecksum is true, a CRC-32C of the dump is appended to it,
+	// which a Loader verifies, so that corrupted dumps fail to load.
+	// Versions of this package that predate EmbedChecksum cannot read
+	// such dumps, nor can OpenMapped.
+	EmbedChecksum bool
+
+	// If ChunkChecksums is true, a CRC-32C of every 256KiB of blocks is
+	// appended to the dump, which a Loader verifies. Unlike EmbedChecksum,
+	// this tells which blocks are corrupt, and allows loading the rest of
+	// the filter (see Loader.OnCorrupt). The compatibility restrictions
+	// of EmbedChecksum apply.
+	ChunkChecksums bool
+
+	// Compression selects a compression format for everything after the
+	// header, which stays uncompressed so that NewLoader can report the
+	// shape and comment of the dump before decompressing it. Mostly empty
+	// filters compress well. The compatibility restrictions of
+	// EmbedChecksum apply.
+	Compression Compression
+}
+
+// DumpCompressed is like Dump, but compresses the blocks with Gzip.
+func DumpCompressed(w io.Writer, f *Filter, comment string) (int64, error) {
+	return dump(w, f.b, f.k, f.premix, f.layout, DumpOptions{Comment: comment, Compression: Gzip})
+}
+
+// DumpWithOptions is like Dump, but takes a DumpOptions.
+func DumpWithOptions(w io.Writer, f *Filter, opts DumpOptions) (int64, error) {
+	return dump(w, f.b, f.k, f.premix, f.layout, opts)
+}
+
+// DumpSyncWithOptions is like DumpSync, but takes a DumpOptions.
+func DumpSyncWithOptions(w io.Writer, f *SyncFilter, opts DumpOptions) (int64, error) {
+	return dump(w, f.b, f.k, f.premix, f.layout, opts)
 }
 
 // DumpSync is like Dump, but for SyncFilters.
@@ -45,85 +98,381 @@ func Dump(w io.Writer, f *Filter, comment string) (int64, error) {
 // The format produced is the same as Dump's. The fact that
 // the argument is a SyncFilter is not encoded in the dump.
 func DumpSync(w io.Writer, f *SyncFilter, comment string) (n int64, err error) {
-	return dump(w, f.b, f.k, comment)
+	return dump(w, f.b, f.k, f.premix, f.layout, DumpOptions{Comment: comment})
+}
+
+// MarshaledSize returns the number of bytes that DumpWithOptions(w, f, opts)
+// writes on success, without serializing f. For a compressed dump,
+// it returns the size before compression.
+func (f *Filter) MarshaledSize(opts DumpOptions) int64 {
+	return marshaledSize(len(f.b)) + opts.extraSize(len(f.b))
+}
+
+// MarshaledSize returns the number of bytes that
+// DumpSyncWithOptions(w, f, opts) writes on success, without serializing f.
+// For a compressed dump, it returns the size before compression.
+func (f *SyncFilter) MarshaledSize(opts DumpOptions) int64 {
+	return marshaledSize(len(f.b)) + opts.extraSize(len(f.b))
+}
+
+// marshaledSize is the size of a dump without checksums. It does not
+// depend on the comment, which is stored in a fixed-size field,
+// nor on opts.Checksum, which is not written.
+func marshaledSize(nblocks int) int64 {
+	return int64(nblocks+1) * BlockBits / 8
+}
+
+// A Kind identifies the type of sketch stored in a dump.
+type Kind uint8
+
+// Kinds of sketches. A SyncFilter is dumped as a Filter.
+const (
+	KindFilter     Kind = iota // Filter or SyncFilter.
+	KindSpectral               // SpectralFilter.
+	KindCountMin               // CountMin.
+	KindTimeWindow             // TimeWindowFilter.
+
+	maxKind = KindTimeWindow
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindFilter:
+		return "Filter"
+	case KindSpectral:
+		return "SpectralFilter"
+	case KindCountMin:
+		return "CountMin"
+	case KindTimeWindow:
+		return "TimeWindowFilter"
+	}
+	return fmt.Sprintf("Kind(%d)", uint8(k))
+}
+
+// Flags in byte 9 of the header.
+const (
+	flagPremix = 1 << iota
+	flagChecksum
+	flagChunkSums
+	flagCompressed
+
+	knownFlags = flagPremix | flagChecksum | flagChunkSums | flagCompressed
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// extraSize returns the size of the checksums in a dump of nblocks blocks.
+func (opts *DumpOptions) extraSize(nblocks int) (size int64) {
+	if opts.ChunkChecksums {
+		size += chunkSumsSize(uint64(nblocks))
+	}
+	if opts.EmbedChecksum {
+		size += BlockBits / 8
+	}
+	return size
+}
+
+func (opts *DumpOptions) flags() (flags byte) {
+	if opts.EmbedChecksum {
+		flags |= flagChecksum
+	}
+	if opts.ChunkChecksums {
+		flags |= flagChunkSums
+	}
+	if opts.Compression != NoCompression {
+		flags |= flagCompressed
+	}
+	return flags
+}
+
+// appendTrailer appends the 64-byte trailer holding crc to buf.
+func appendTrailer(buf []byte, crc uint32) []byte {
+	off := len(buf)
+	buf = append(buf, make([]byte, BlockBits/8)...)
+	binary.LittleEndian.PutUint32(buf[off:], crc)
+	return buf
+}
+
+// Maximum size of the buffer used by dump.
+const dumpBufSize = 1 << 16
+
+func dump(w io.Writer, b []block, nhashes int, premix bool, layout Layout, opts DumpOptions) (n int64, err error) {
+	if err := checkDump(b, nhashes, opts.Comment); err != nil {
+		return 0, err
+	}
+	var comp compression
+	if opts.Compression != NoCompression {
+		if comp, err = findCompression(opts.Compression); err != nil {
+			return 0, err
+		}
+	}
+
+	if opts.Checksum != nil {
+		w = io.MultiWriter(w, opts.Checksum)
+	}
+	cw := &writeCounter{w: w}
+	w = cw
+
+	// We encode as many blocks as fit in buf, then write them out in one go.
+	size := uint64(len(b)+1) * BlockBits / 8
+	if size > dumpBufSize {
+		size = dumpBufSize
+	}
+	buf := appendHeader(make([]byte, 0, size), len(b), nhashes, premix, layout, opts.Comment)
+	buf[9] |= opts.flags()
+	buf[11] = byte(opts.Compression)
+
+	var crc hash.Hash32
+	if opts.EmbedChecksum {
+		crc = crc32.New(castagnoli)
+	}
+	var zw io.WriteCloser
+	if comp.newWriter != nil {
+		// The header is not compressed, so that it can be inspected.
+		if _, err := w.Write(buf); err != nil {
+			return cw.n, err
+		}
+		if crc != nil {
+			crc.Write(buf)
+		}
+		buf = buf[:0]
+		if zw, err = comp.newWriter(w); err != nil {
+			return cw.n, err
+		}
+		w = zw
+	}
+	if crc != nil {
+		w = io.MultiWriter(w, crc)
+	}
+
+	var chunks *chunkSummer
+	if opts.ChunkChecksums {
+		chunks = new(chunkSummer)
+	}
+
+	for i := range b {
+		if len(buf) == cap(buf) {
+			if _, err := w.Write(buf); err != nil {
+				return cw.n, err
+			}
+			buf = buf[:0]
+		}
+
+		buf = appendBlock(buf, &b[i])
+		if chunks != nil {
+			chunks.add(buf[len(buf)-BlockBits/8:])
+		}
+	}
+
+	_, err = w.Write(buf)
+	if err == nil && chunks != nil {
+		_, err = w.Write(appendChunkSums(buf[:0], chunks.finish(), uint64(len(b))))
+	}
+	if err == nil && crc != nil {
+		_, err = w.Write(appendTrailer(buf[:0], crc.Sum32()))
+	}
+	if err == nil && zw != nil {
+		err = zw.Close()
+	}
+	return cw.n, err
 }
 
-func dump(w io.Writer, b []block, nhashes int, comment string) (n int64, err error) {
+// A writeCounter counts the bytes written through it.
+type writeCounter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func checkDump(b []block, nhashes int, comment string) error {
+	if len(b) == 0 || nhashes == 0 {
+		return errors.New("blobloom: won't dump uninitialized Filter")
+	}
+	return checkDumpComment(comment)
+}
+
+func checkDumpComment(comment string) error {
 	switch {
-	case len(b) == 0 || nhashes == 0:
-		err = errors.New("blobloom: won't dump uninitialized Filter")
 	case len(comment) > maxCommentLen:
-		err = fmt.Errorf("blobloom: comment of length %d too long", len(comment))
+		return errorf(ErrTooLarge, "blobloom: comment of length %d too long", len(comment))
 	case strings.IndexByte(comment, 0) != -1:
-		err = fmt.Errorf("blobloom: comment %q contains zero byte", len(comment))
-	}
-	if err != nil {
-		return 0, err
+		return errorf(ErrFormat, "blobloom: comment %q contains zero byte", comment)
 	}
+	return nil
+}
+
+// appendHeader appends the 64-byte header to buf.
+func appendHeader(buf []byte, nblocks, nhashes int, premix bool, layout Layout, comment string) []byte {
+	off := len(buf)
+	buf = append(buf, make([]byte, 64)...)
+	hdr := buf[off:]
 
-	var buf [64]byte
-	copy(buf[:8], "blobloom")
+	copy(hdr[:8], "blobloom")
+	hdr[8] = byte(layout)
+	if premix {
+		hdr[9] |= flagPremix
+	}
 	// As documented in the comment for Loader, we store one less than the
 	// number of blocks. This way, we can use the otherwise invalid value 0
 	// and store 2³² blocks instead of at most 2³²-1.
-	binary.LittleEndian.PutUint32(buf[12:], uint32(len(b)-1))
-	binary.LittleEndian.PutUint32(buf[16:], uint32(nhashes))
-	copy(buf[20:], comment)
+	binary.LittleEndian.PutUint32(hdr[12:], uint32(nblocks-1))
+	binary.LittleEndian.PutUint32(hdr[16:], uint32(nhashes))
+	copy(hdr[20:], comment)
 
-	k, err := w.Write(buf[:])
-	n = int64(k)
-	if err != nil {
-		return n, err
-	}
+	return buf
+}
 
-	for i := range b {
-		for j := range b[i] {
-			x := atomic.LoadUint32(&b[i][j])
-			binary.LittleEndian.PutUint32(buf[4*j:], x)
+// AppendDump appends the serialization of f, in the format written by Dump,
+// to dst and returns the extended slice. If dst has enough spare capacity,
+// AppendDump does not allocate. The required capacity is given by
+// f.MarshaledSize(opts), except when opts.Compression is set.
+//
+// If opts.Checksum is not nil, the appended bytes are written to it.
+func AppendDump(dst []byte, f *Filter, opts DumpOptions) ([]byte, error) {
+	if err := checkDump(f.b, f.k, opts.Comment); err != nil {
+		return dst, err
+	}
+	if opts.Compression != NoCompression {
+		buf := bytes.NewBuffer(dst)
+		if _, err := DumpWithOptions(buf, f, opts); err != nil {
+			return dst, err
 		}
-		k, err = w.Write(buf[:])
-		n += int64(k)
-		if err != nil {
-			break
+		return buf.Bytes(), nil
+	}
+
+	off := len(dst)
+	if need := int(f.MarshaledSize(opts)); cap(dst)-off < need {
+		grown := make([]byte, off, off+need)
+		copy(grown, dst)
+		dst = grown
+	}
+
+	dst = appendHeader(dst, len(f.b), f.k, f.premix, f.layout, opts.Comment)
+	dst[off+9] |= opts.flags()
+	for i := range f.b {
+		dst = appendBlock(dst, &f.b[i])
+	}
+	if opts.ChunkChecksums {
+		var chunks chunkSummer
+		for p := dst[off+BlockBits/8:]; len(p) > 0; p = p[BlockBits/8:] {
+			chunks.add(p[:BlockBits/8])
 		}
+		dst = appendChunkSums(dst, chunks.finish(), uint64(len(f.b)))
+	}
+	if opts.EmbedChecksum {
+		dst = appendTrailer(dst, crc32.Checksum(dst[off:], castagnoli))
 	}
 
-	return n, err
+	if opts.Checksum != nil {
+		opts.Checksum.Write(dst[off:])
+	}
+	return dst, nil
+}
+
+// appendBlock appends the little-endian encoding of b to buf,
+// using atomic loads.
+func appendBlock(buf []byte, b *block) []byte {
+	off := len(buf)
+	buf = buf[:off+BlockBits/8]
+	for j := range b {
+		x := atomic.LoadUint32(&b[j])
+		binary.LittleEndian.PutUint32(buf[off+4*j:], x)
+	}
+	return buf
 }
 
 // A Loader reads a Filter or SyncFilter from an io.Reader.
+// It also reads the other sketches that this package can dump,
+// for which Kind reports the type.
 //
 // A Loader accepts the binary format produced by Dump. The format starts
 // with a 64-byte header:
 //   - the string "blobloom", in ASCII;
-//   - a four-byte version number, which must be zero;
+//   - a one-byte version number, which is the Layout of the filter;
+//   - a one-byte flags field, in which bit 0 means that hash values
+//     are premixed (see Config.Premix), bit 1 means that the dump has
+//     a checksum, bit 2 means that it has chunk checksums, bit 3 means
+//     that it is compressed and the other bits must be zero;
+//   - a one-byte Kind, which is zero for a Filter;
+//   - the Compression of the dump, which is zero if it is not compressed;
 //   - the number of Bloom filter blocks, minus one, as a 32-bit integer;
 //   - the number of hashes, as a 32-bit integer;
 //   - a comment of at most 44 non-zero bytes, padded to 44 bytes with zeros.
 //
 // After the header come the 512-bit blocks, divided into sixteen 32-bit limbs.
-// All integers are little-endian.
+// If the dump has chunk checksums, the blocks are followed by the CRC-32C
+// (Castagnoli) of each run of 4096 blocks, the last of which may be shorter,
+// padded with zeros to a multiple of 64 bytes. If the dump has a checksum,
+// that is followed by a 64-byte trailer that holds the CRC-32C of everything
+// before it, padded with zeros. In a compressed dump, all of these follow
+// the header in compressed form. All integers are little-endian.
+//
+// A SpectralFilter is stored with the number of blocks of counters in place
+// of the number of blocks, followed by the counter blocks, one byte per
+// counter. A CountMin is stored with its width, minus one, and depth in place
+// of the numbers of blocks and hashes, followed by its counters, row by row,
+// as 32-bit integers. For the format of a TimeWindowFilter, see DumpTimeWindow.
+//
+// A Loader reads no further than the end of a dump, so several dumps can be
+// written to a single stream and read back by calling NewLoader repeatedly.
+// For compressed dumps, this requires the stream to be an io.ByteReader.
 type Loader struct {
 	buf [64]byte
 	r   io.Reader
 	err error
 
 	Comment string // Comment field. Filled in by NewLoader.
+	kind    Kind
 	nblocks uint64
 	nhashes int
+	premix  bool
+	layout  Layout
+	comp    Compression
+	crc     hash.Hash32  // Checksum of the bytes read so far, if any.
+	chunks  *chunkSummer // Chunk checksums of the blocks read, if any.
+
+	onCorrupt CorruptAction
+	corrupt   []BlockRange
+
+	progress func(loaded, total uint64)
 }
 
 // NewLoader parses the format header from r and returns a Loader
 // that can be used to load a Filter from it.
+//
+// If r starts with the magic number of a compression format registered
+// with RegisterDecompressor, such as gzip, NewLoader decompresses it
+// transparently. The same goes for dumps written with
+// DumpOptions.Compression, of which only the header is read by NewLoader.
 func NewLoader(r io.Reader) (*Loader, error) {
 	l := &Loader{r: r}
 
-	err := l.fillbuf()
+	// Read the magic number first, to detect compression.
+	err := l.fill(l.buf[:8])
+	if err != nil {
+		return nil, err
+	}
+	if newReader := findDecompressor(l.buf[:8]); newReader != nil {
+		magic := bytes.NewReader(append([]byte(nil), l.buf[:8]...))
+		l.r, err = newReader(io.MultiReader(magic, r))
+		if err == nil {
+			err = l.fillbuf()
+		}
+	} else {
+		err = l.fill(l.buf[8:])
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	version := binary.LittleEndian.Uint32(l.buf[8:])
+	version, flags := l.buf[8], l.buf[9]
+	l.kind = Kind(l.buf[10])
+	l.comp = Compression(l.buf[11])
 	// See comment in dump for the +1.
 	l.nblocks = 1 + uint64(binary.LittleEndian.Uint32(l.buf[12:]))
 	l.nhashes = int(binary.LittleEndian.Uint32(l.buf[16:]))
@@ -131,16 +480,38 @@ func NewLoader(r io.Reader) (*Loader, error) {
 
 	switch {
 	case string(l.buf[:8]) != "blobloom":
-		err = errors.New("blobloom: not a Bloom filter dump")
-	case version != 0:
-		err = errors.New("blobloom: unsupported dump version")
+		err = errorf(ErrFormat, "blobloom: not a Bloom filter dump")
+	case Layout(version) > LayoutV1 || (l.comp != 0) != (flags&flagCompressed != 0):
+		err = errorf(ErrFormat, "blobloom: unsupported dump version")
+	case l.kind > maxKind:
+		err = errorf(ErrFormat, "blobloom: unsupported kind %d in dump", l.kind)
+	case flags&^knownFlags != 0:
+		err = errorf(ErrFormat, "blobloom: unsupported flags %#x in dump", flags)
 	case l.nhashes == 0:
-		err = errors.New("blobloom: zero hashes in Bloom filter dump")
+		err = errorf(ErrFormat, "blobloom: zero hashes in Bloom filter dump")
 	}
+	l.premix = flags&flagPremix != 0
+	l.layout = Layout(version)
 	if err == nil {
 		comment, err = checkComment(comment)
 		l.Comment = string(comment)
 	}
+	if err == nil && flags&(flagChecksum|flagChunkSums) != 0 && l.kind != KindFilter {
+		err = errorf(ErrFormat, "blobloom: checksum in %v dump", l.kind)
+	}
+	if err == nil && flags&flagChunkSums != 0 {
+		l.chunks = new(chunkSummer)
+	}
+	if err == nil && l.comp != NoCompression {
+		var comp compression
+		comp, err = findCompression(l.comp)
+		l.r = &lazyReader{r: l.r, newReader: comp.newReader}
+	}
+	if err == nil && flags&flagChecksum != 0 {
+		l.crc = crc32.New(castagnoli)
+		l.crc.Write(l.buf[:])
+		l.r = io.TeeReader(l.r, l.crc)
+	}
 
 	if err != nil {
 		l = nil
@@ -154,13 +525,8 @@ func NewLoader(r io.Reader) (*Loader, error) {
 // If f is not nil and an error occurs while reading from the Loader,
 // f may end up in an inconsistent state.
 func (l *Loader) Load(f *Filter) (*Filter, error) {
-	if f == nil {
-		nbits := BlockBits * l.nblocks
-		if nbits > MaxBits {
-			return nil, fmt.Errorf("blobloom: %d blocks is too large", l.nblocks)
-		}
-		f = New(nbits, int(l.nhashes))
-	} else if err := l.checkBitsAndHashes(len(f.b), f.k); err != nil {
+	f, err := l.filterFor(f)
+	if err != nil {
 		return nil, err
 	}
 
@@ -169,11 +535,65 @@ func (l *Loader) Load(f *Filter) (*Filter, error) {
 			return nil, err
 		}
 
+		if l.chunks != nil {
+			l.chunks.add(l.buf[:])
+		}
+
 		for j := range f.b[i] {
 			f.b[i][j] |= binary.LittleEndian.Uint32(l.buf[4*j:])
 		}
+		l.reportProgress(i, i+1)
 	}
 
+	if err := l.verifyChunks(f.b); err != nil {
+		return nil, err
+	}
+	if err := l.verifyChecksum(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// LoadBytes loads a Filter from the dump at the start of p.
+//
+// If p holds an uncompressed dump, the machine is little-endian and the
+// blocks in p are aligned to four bytes, the Filter uses the blocks in p
+// in place, without copying. Keys added to the Filter then modify p, so p
+// must be writable if keys are to be added. Otherwise, or when the
+// nounsafe build tag is set, LoadBytes decodes p as Load does.
+// Alignment to BlockAlignment is recommended.
+//
+// The caller must not modify p while the Filter is in use.
+func LoadBytes(p []byte) (*Filter, error) {
+	l, err := NewLoader(bytes.NewReader(p))
+	if err != nil {
+		return nil, err
+	}
+	if err := l.checkKind(KindFilter); err != nil {
+		return nil, err
+	}
+	if f := l.inPlace(p); f != nil {
+		return f, nil
+	}
+	return l.Load(nil)
+}
+
+// filterFor returns f if it matches the Loader's filter,
+// or a new Filter of the appropriate size if f is nil.
+func (l *Loader) filterFor(f *Filter) (*Filter, error) {
+	if err := l.checkKind(KindFilter); err != nil {
+		return nil, err
+	}
+	if f == nil {
+		nbits := BlockBits * l.nblocks
+		if nbits > MaxBits {
+			return nil, errorf(ErrTooLarge, "blobloom: %d blocks is too large", l.nblocks)
+		}
+		f = New(nbits, int(l.nhashes))
+		f.premix, f.layout = l.premix, l.layout
+	} else if err := l.checkBitsAndHashes(len(f.b), f.k, f.premix, f.layout); err != nil {
+		return nil, err
+	}
 	return f, nil
 }
 
@@ -184,13 +604,17 @@ func (l *Loader) Load(f *Filter) (*Filter, error) {
 // If f is not nil and an error occurs while reading from the Loader,
 // f may end up in an inconsistent state.
 func (l *Loader) LoadSync(f *SyncFilter) (*SyncFilter, error) {
+	if err := l.checkKind(KindFilter); err != nil {
+		return nil, err
+	}
 	if f == nil {
 		nbits := BlockBits * l.nblocks
 		if nbits > MaxBits {
-			return nil, fmt.Errorf("blobloom: %d blocks is too large", l.nblocks)
+			return nil, errorf(ErrTooLarge, "blobloom: %d blocks is too large", l.nblocks)
 		}
 		f = NewSync(nbits, int(l.nhashes))
-	} else if err := l.checkBitsAndHashes(len(f.b), f.k); err != nil {
+		f.premix, f.layout = l.premix, l.layout
+	} else if err := l.checkBitsAndHashes(len(f.b), f.k, f.premix, f.layout); err != nil {
 		return nil, err
 	}
 
@@ -199,34 +623,127 @@ func (l *Loader) LoadSync(f *SyncFilter) (*SyncFilter, error) {
 			return nil, err
 		}
 
+		if l.chunks != nil {
+			l.chunks.add(l.buf[:])
+		}
+
 		for j := range f.b[i] {
-			p := &f.b[i][j]
-			x := binary.LittleEndian.Uint32(l.buf[4*j:])
-
-			for {
-				old := atomic.LoadUint32(p)
-				if atomic.CompareAndSwapUint32(p, old, old|x) {
-					break
-				}
-			}
+			orAtomic(&f.b[i][j], binary.LittleEndian.Uint32(l.buf[4*j:]))
 		}
+		l.reportProgress(i, i+1)
 	}
 
+	if err := l.verifyChunks(f.b); err != nil {
+		return nil, err
+	}
+	if err := l.verifyChecksum(); err != nil {
+		return nil, err
+	}
 	return f, nil
 }
 
-func (l *Loader) checkBitsAndHashes(nblocks, nhashes int) error {
+// WithProgress sets a function that Load and LoadSync call with the number
+// of blocks loaded so far and the total number of blocks, after every 65536
+// blocks (4MiB) and after the last block. It returns l.
+func (l *Loader) WithProgress(fn func(loadedBlocks, totalBlocks uint64)) *Loader {
+	l.progress = fn
+	return l
+}
+
+// Number of blocks between progress reports.
+const progressInterval = 1 << 16
+
+// reportProgress reports progress when the number of blocks loaded
+// goes from prev to loaded.
+func (l *Loader) reportProgress(prev, loaded int) {
+	if l.progress == nil {
+		return
+	}
+	if prev/progressInterval != loaded/progressInterval || uint64(loaded) == l.nblocks {
+		l.progress(uint64(loaded), l.nblocks)
+	}
+}
+
+// A lazyReader calls newReader on its first Read, so that NewLoader
+// does not read past the header of a compressed dump.
+type lazyReader struct {
+	r         io.Reader
+	newReader func(io.Reader) (io.Reader, error)
+	err       error
+}
+
+func (r *lazyReader) Read(p []byte) (int, error) {
+	if r.newReader != nil {
+		r.r, r.err = r.newReader(r.r)
+		r.newReader = nil
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.r.Read(p)
+}
+
+// Kind returns the kind of sketch in the Loader's dump.
+func (l *Loader) Kind() Kind { return l.kind }
+
+// Compression returns the Compression of the Loader's dump, which is
+// NoCompression for dumps that were compressed as a whole, e.g., by gzip.
+func (l *Loader) Compression() Compression { return l.comp }
+
+// DumpOptions returns the options that the Loader's dump was written with,
+// so that a filter can be dumped again in the same way.
+// The Checksum field is always nil.
+func (l *Loader) DumpOptions() DumpOptions {
+	return DumpOptions{
+		Comment:        l.Comment,
+		EmbedChecksum:  l.crc != nil,
+		ChunkChecksums: l.chunks != nil,
+		Compression:    l.comp,
+	}
+}
+
+func (l *Loader) checkKind(kind Kind) error {
+	if l.kind != kind {
+		return errorf(ErrShapeMismatch, "blobloom: dump contains %v, not %v", l.kind, kind)
+	}
+	return nil
+}
+
+func (l *Loader) checkBitsAndHashes(nblocks, nhashes int, premix bool, layout Layout) error {
 	switch {
 	case nblocks != int(l.nblocks):
-		return fmt.Errorf("blobloom: Filter has %d blocks, but dump has %d", nblocks, l.nblocks)
+		return errorf(ErrShapeMismatch, "blobloom: Filter has %d blocks, but dump has %d", nblocks, l.nblocks)
 	case nhashes != l.nhashes:
-		return fmt.Errorf("blobloom: Filter has %d hashes, but dump has %d", nhashes, l.nhashes)
+		return errorf(ErrShapeMismatch, "blobloom: Filter has %d hashes, but dump has %d", nhashes, l.nhashes)
+	case premix != l.premix:
+		return errorf(ErrShapeMismatch, "blobloom: Filter has premix=%t, but dump has %t", premix, l.premix)
+	case layout != l.layout:
+		return errorf(ErrShapeMismatch, "blobloom: Filter has layout %d, but dump has %d", layout, l.layout)
 	}
 	return nil
 }
 
-func (l *Loader) fillbuf() error {
-	_, err := io.ReadFull(l.r, l.buf[:])
+// verifyChecksum reads the trailer, if the dump has one,
+// and checks the checksum in it.
+func (l *Loader) verifyChecksum() error {
+	if l.crc == nil {
+		return nil
+	}
+	want := l.crc.Sum32()
+	if err := l.fillbuf(); err != nil {
+		return err
+	}
+	// Corrupt blocks that were not rejected also fail the checksum.
+	if !bytes.Equal(appendTrailer(nil, want), l.buf[:]) && l.corrupt == nil {
+		return errorf(ErrChecksum, "blobloom: dump checksum mismatch")
+	}
+	return nil
+}
+
+func (l *Loader) fillbuf() error { return l.fill(l.buf[:]) }
+
+func (l *Loader) fill(p []byte) error {
+	_, err := io.ReadFull(l.r, p)
 	if err == io.EOF {
 		err = io.ErrUnexpectedEOF
 	}
@@ -238,7 +755,7 @@ func checkComment(p []byte) ([]byte, error) {
 	if eos != -1 {
 		tail := p[eos+1:]
 		if !bytes.Equal(tail, make([]byte, len(tail))) {
-			return nil, fmt.Errorf("blobloom: comment block %q contains zero byte", p)
+			return nil, errorf(ErrFormat, "blobloom: comment block %q contains zero byte", p)
 		}
 		p = p[:eos]
 	}
@@ -16,8 +16,13 @@ package blobloom
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"crypto/sha256"
 	"io"
+	"io/ioutil"
 	"math/rand"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -50,3 +55,509 @@ func TestDumpLoad(t *testing.T) {
 	assert.Nil(t, g2)
 	assert.Equal(t, io.ErrUnexpectedEOF, err)
 }
+
+func TestDumpLoadPremix(t *testing.T) {
+	f := NewOptimized(Config{Capacity: 100, FPRate: .01, Premix: true})
+	for i := uint64(0); i < 100; i++ {
+		f.Add(i)
+	}
+
+	buf := new(bytes.Buffer)
+	_, err := Dump(buf, f, "")
+	require.NoError(t, err)
+	assert.EqualValues(t, flagPremix, buf.Bytes()[9])
+
+	l, err := NewLoader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	g, err := l.Load(nil)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	l, err = NewLoader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	_, err = l.Load(New(f.NumBits(), f.k))
+	assert.Error(t, err)
+
+	p := buf.Bytes()
+	p[9] |= 0x80
+	_, err = NewLoader(bytes.NewReader(p))
+	assert.Error(t, err)
+}
+
+type countingWriter struct {
+	n      int
+	ncalls int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	w.ncalls++
+	return len(p), nil
+}
+
+func TestDumpChecksum(t *testing.T) {
+	f := New(1<<22, 3) // 8192 blocks.
+	r := rand.New(rand.NewSource(0xd0))
+	for i := 0; i < 1000; i++ {
+		f.Add(r.Uint64())
+	}
+
+	var buf bytes.Buffer
+	h := sha256.New()
+	n, err := DumpWithOptions(&buf, f, DumpOptions{Comment: "sum", Checksum: h})
+	require.NoError(t, err)
+	assert.EqualValues(t, buf.Len(), n)
+	sum := sha256.Sum256(buf.Bytes())
+	assert.Equal(t, sum[:], h.Sum(nil))
+
+	// The dump should be written in large chunks.
+	w := new(countingWriter)
+	n, err = DumpWithOptions(w, f, DumpOptions{})
+	require.NoError(t, err)
+	assert.EqualValues(t, w.n, n)
+	assert.EqualValues(t, 8193*64, n)
+	assert.Equal(t, 9, w.ncalls)
+}
+
+func TestAppendDump(t *testing.T) {
+	f := New(1<<14, 5)
+	f.premix = true
+	r := rand.New(rand.NewSource(0xa9))
+	for i := 0; i < 100; i++ {
+		f.Add(r.Uint64())
+	}
+
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "append")
+	require.NoError(t, err)
+
+	prefix := []byte("prefix")
+	h := sha256.New()
+	p, err := AppendDump(prefix, f, DumpOptions{Comment: "append", Checksum: h})
+	require.NoError(t, err)
+	assert.Equal(t, "prefix", string(p[:6]))
+	assert.Equal(t, buf.Bytes(), p[6:])
+	sum := sha256.Sum256(buf.Bytes())
+	assert.Equal(t, sum[:], h.Sum(nil))
+
+	assert.EqualValues(t, buf.Len(), f.MarshaledSize(DumpOptions{}))
+	dst := make([]byte, 0, f.MarshaledSize(DumpOptions{Comment: "append"}))
+	allocs := testing.AllocsPerRun(10, func() {
+		dst, err = AppendDump(dst[:0], f, DumpOptions{Comment: "append"})
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, allocs)
+	assert.Equal(t, buf.Bytes(), dst)
+
+	_, err = AppendDump(nil, f, DumpOptions{Comment: "\x00"})
+	assert.Error(t, err)
+}
+
+func TestDumpLoadLayout(t *testing.T) {
+	cfg := Config{Capacity: 100, FPRate: .01, Layout: LayoutV1}
+	f := NewSyncOptimized(cfg)
+	for i := uint64(0); i < 100; i++ {
+		f.Add(mix64(i))
+	}
+
+	buf := new(bytes.Buffer)
+	_, err := DumpSync(buf, f, "")
+	require.NoError(t, err)
+	assert.EqualValues(t, LayoutV1, buf.Bytes()[8])
+	assert.EqualValues(t, buf.Len(), f.MarshaledSize(DumpOptions{}))
+	assert.EqualValues(t, buf.Len()-64, f.Size())
+
+	l, err := NewLoader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	g, err := l.Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, LayoutV1, g.layout)
+	for i := uint64(0); i < 100; i++ {
+		assert.True(t, g.Has(mix64(i)))
+	}
+
+	l, err = NewLoader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	cfg.Layout = LayoutV0
+	_, err = l.LoadSync(NewSyncOptimized(cfg))
+	assert.Error(t, err)
+
+	p := buf.Bytes()
+	p[8] = 2
+	_, err = NewLoader(bytes.NewReader(p))
+	assert.Error(t, err)
+}
+
+func TestLoadCompressed(t *testing.T) {
+	f := New(1<<12, 3)
+	for i := uint64(0); i < 50; i++ {
+		f.Add(mix64(i))
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := Dump(zw, f, "gzipped")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	// Data after the compressed stream.
+	buf.WriteString("trailing garbage")
+
+	l, err := NewLoader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "gzipped", l.Comment)
+	g, err := l.Load(nil)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	// A fake compression format that prepends a magic number.
+	RegisterDecompressor("fake!", func(r io.Reader) (io.Reader, error) {
+		_, err := io.CopyN(ioutil.Discard, r, 5)
+		return r, err
+	})
+	buf.Reset()
+	buf.WriteString("fake!")
+	_, err = Dump(&buf, f, "fake")
+	require.NoError(t, err)
+
+	l, err = NewLoader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "fake", l.Comment)
+	g, err = l.Load(nil)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	_, err = NewLoader(strings.NewReader("\x1f\x8bnot really gzip"))
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { RegisterDecompressor("blob", nil) })
+	assert.Panics(t, func() { RegisterDecompressor("", nil) })
+}
+
+func TestDumpCompressed(t *testing.T) {
+	t.Parallel()
+
+	// Mostly empty.
+	f := New(1<<20, 5)
+	for _, h := range randomU64(100, 0xc0) {
+		f.Add(h)
+	}
+
+	var buf bytes.Buffer
+	n, err := DumpCompressed(&buf, f, "compressed")
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Less(t, 20*n, f.MarshaledSize(DumpOptions{}))
+	p := append([]byte(nil), buf.Bytes()...)
+
+	// The header can be inspected without decompressing.
+	l, err := NewLoader(bytes.NewReader(p[:BlockBits/8]))
+	require.NoError(t, err)
+	assert.Equal(t, "compressed", l.Comment)
+	assert.Equal(t, Gzip, l.Compression())
+
+	l, err = NewLoader(bytes.NewReader(p))
+	require.NoError(t, err)
+	g, err := l.Load(nil)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	g, err = LoadBytes(p)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	opts := DumpOptions{Compression: Gzip, EmbedChecksum: true, ChunkChecksums: true}
+	appended, err := AppendDump([]byte("prefix"), f, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "prefix", string(appended[:6]))
+	p = appended[6:]
+
+	// Compressed dumps can be concatenated.
+	r := bytes.NewReader(append(append([]byte(nil), p...), p...))
+	for i := 0; i < 2; i++ {
+		l, err := NewLoader(r)
+		require.NoError(t, err)
+		s, err := l.LoadSync(nil)
+		require.NoError(t, err)
+		assert.True(t, f.Equals(s.Freeze()))
+	}
+	assert.Zero(t, r.Len())
+
+	// Corrupt the compressed data.
+	q := append([]byte(nil), p...)
+	q[len(q)/2] ^= 0xff
+	l, err = NewLoader(bytes.NewReader(q))
+	require.NoError(t, err)
+	_, err = l.Load(nil)
+	assert.Error(t, err)
+
+	// The flag and the compression byte go together.
+	q = append([]byte(nil), p...)
+	q[11] = 0
+	_, err = NewLoader(bytes.NewReader(q))
+	assert.ErrorIs(t, err, ErrFormat)
+	q[9] &^= flagCompressed
+	q[11] = byte(Gzip)
+	_, err = NewLoader(bytes.NewReader(q))
+	assert.ErrorIs(t, err, ErrFormat)
+
+	// Unknown compression.
+	_, err = DumpWithOptions(ioutil.Discard, f, DumpOptions{Compression: 201})
+	assert.ErrorIs(t, err, ErrFormat)
+	q[9] |= flagCompressed
+	q[11] = 201
+	_, err = NewLoader(bytes.NewReader(q))
+	assert.ErrorIs(t, err, ErrFormat)
+
+	RegisterCompression(200,
+		func(w io.Writer) (io.WriteCloser, error) { return flate.NewWriter(w, flate.BestSpeed) },
+		func(r io.Reader) (io.Reader, error) { return flate.NewReader(r), nil })
+	buf.Reset()
+	_, err = DumpWithOptions(&buf, f, DumpOptions{Compression: 200})
+	require.NoError(t, err)
+	l, err = NewLoader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, Compression(200), l.Compression())
+	g, err = l.Load(nil)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	assert.Panics(t, func() { RegisterCompression(NoCompression, nil, nil) })
+}
+
+func TestLoaderDumpOptions(t *testing.T) {
+	t.Parallel()
+
+	f := New(1<<12, 3)
+	for _, opts := range []DumpOptions{
+		{},
+		{Comment: "plain"},
+		{Comment: "checked", EmbedChecksum: true, ChunkChecksums: true},
+		{Compression: Gzip, ChunkChecksums: true},
+	} {
+		p, err := AppendDump(nil, f, opts)
+		require.NoError(t, err)
+		l, err := NewLoader(bytes.NewReader(p))
+		require.NoError(t, err)
+		assert.Equal(t, opts, l.DumpOptions())
+	}
+}
+
+func TestLoadProgress(t *testing.T) {
+	t.Parallel()
+
+	const nblocks = progressInterval + 100
+	f := New(nblocks*BlockBits, 3)
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "")
+	require.NoError(t, err)
+	dump := buf.Bytes()
+
+	var loaded []uint64
+	progress := func(n, total uint64) {
+		assert.EqualValues(t, nblocks, total)
+		loaded = append(loaded, n)
+	}
+
+	l, err := NewLoader(bytes.NewReader(dump))
+	require.NoError(t, err)
+	_, err = l.WithProgress(progress).Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{progressInterval, nblocks}, loaded)
+
+	loaded = nil
+	l, err = NewLoader(bytes.NewReader(dump))
+	require.NoError(t, err)
+	_, err = l.WithProgress(progress).LoadSync(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{progressInterval, nblocks}, loaded)
+}
+
+func TestDumpKinds(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1000, FPRate: 1e-3, Premix: true, Layout: LayoutV1}
+	f := NewOptimized(config)
+	s := NewSyncOptimized(config)
+	sp := NewSpectralOptimized(config)
+	cm := NewCountMinOptimized(CountMinConfig{Epsilon: .01, Delta: .01})
+	for _, h := range randomU64(1000, 0x4b1d) {
+		f.Add(h)
+		s.Add(h)
+		sp.Add(h)
+		cm.Add(h)
+	}
+
+	// Several dumps in one stream.
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "filter")
+	require.NoError(t, err)
+	_, err = DumpSpectral(&buf, sp, "spectral")
+	require.NoError(t, err)
+	_, err = DumpCountMin(&buf, cm, "cm")
+	require.NoError(t, err)
+	_, err = DumpSync(&buf, s, "sync")
+	require.NoError(t, err)
+
+	for _, comment := range []string{"filter", "spectral", "cm", "sync"} {
+		l, err := NewLoader(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, comment, l.Comment)
+
+		switch l.Kind() {
+		case KindFilter:
+			g, err := l.Load(nil)
+			require.NoError(t, err)
+			assert.True(t, f.Equals(g))
+		case KindSpectral:
+			_, err := l.LoadSync(nil)
+			assert.Error(t, err)
+			g, err := l.LoadSpectral()
+			require.NoError(t, err)
+			assert.Equal(t, sp, g)
+		case KindCountMin:
+			_, err := l.LoadSpectral()
+			assert.Error(t, err)
+			g, err := l.LoadCountMin()
+			require.NoError(t, err)
+			assert.Equal(t, cm, g)
+		default:
+			t.Fatalf("unexpected kind %v", l.Kind())
+		}
+	}
+	assert.Zero(t, buf.Len())
+
+	// Unknown kinds are rejected.
+	_, err = Dump(&buf, f, "")
+	require.NoError(t, err)
+	buf.Bytes()[10] = 0xff
+	_, err = NewLoader(&buf)
+	assert.Error(t, err)
+	assert.Equal(t, "Kind(255)", Kind(255).String())
+}
+
+func TestLoadBytes(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1000, FPRate: 1e-3, Premix: true, Layout: LayoutV1}
+	f := NewOptimized(config)
+	hashes := randomU64(2000, 0x10adb)
+	for _, h := range hashes[:1000] {
+		f.Add(h)
+	}
+
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "in place")
+	require.NoError(t, err)
+	p := buf.Bytes()
+
+	g, err := LoadBytes(p)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+	assert.Equal(t, f.premix, g.premix)
+	assert.Equal(t, f.layout, g.layout)
+
+	// Trailing data is ignored.
+	g, err = LoadBytes(append(append([]byte(nil), p...), "trailer"...))
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	// Misaligned: copies.
+	q := make([]byte, len(p)+1)[1:]
+	copy(q, p)
+	g, err = LoadBytes(q)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	// Compressed: copies.
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(p)
+	require.NoError(t, w.Close())
+	g, err = LoadBytes(gz.Bytes())
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	_, err = LoadBytes(p[:len(p)-1])
+	assert.Error(t, err)
+	_, err = LoadBytes(p[:20])
+	assert.Error(t, err)
+
+	buf.Reset()
+	_, err = DumpCountMin(&buf, NewCountMin(64, 2), "")
+	require.NoError(t, err)
+	_, err = LoadBytes(buf.Bytes())
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+}
+
+func TestEmbedChecksum(t *testing.T) {
+	t.Parallel()
+
+	f := NewOptimized(Config{Capacity: 1000, FPRate: 1e-3, Premix: true})
+	for _, h := range randomU64(1000, 0xc4c) {
+		f.Add(h)
+	}
+	opts := DumpOptions{Comment: "checked", EmbedChecksum: true}
+
+	var buf bytes.Buffer
+	n, err := DumpWithOptions(&buf, f, opts)
+	require.NoError(t, err)
+	assert.Equal(t, f.MarshaledSize(opts), n)
+	assert.Equal(t, f.MarshaledSize(DumpOptions{})+BlockBits/8, n)
+	p := append([]byte(nil), buf.Bytes()...)
+
+	appended, err := AppendDump(nil, f, opts)
+	require.NoError(t, err)
+	assert.Equal(t, p, appended)
+
+	load := func(p []byte) (*Filter, error) {
+		l, err := NewLoader(bytes.NewReader(p))
+		if err != nil {
+			return nil, err
+		}
+		return l.Load(nil)
+	}
+
+	g, err := load(p)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	g, err = LoadBytes(p)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	l, err := NewLoader(bytes.NewReader(p))
+	require.NoError(t, err)
+	g, err = l.LoadParallel(nil, 4)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+
+	l, err = NewLoader(bytes.NewReader(p))
+	require.NoError(t, err)
+	s, err := l.LoadSync(nil)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(s.Freeze()))
+
+	// Dumps with checksums can be concatenated.
+	r := bytes.NewReader(append(append([]byte(nil), p...), p...))
+	for i := 0; i < 2; i++ {
+		l, err := NewLoader(r)
+		require.NoError(t, err)
+		_, err = l.Load(nil)
+		require.NoError(t, err)
+	}
+	assert.Zero(t, r.Len())
+
+	// Corrupt the comment, a block and the trailer.
+	for _, off := range []int{21, 64 + 7, len(p) - 64, len(p) - 1} {
+		q := append([]byte(nil), p...)
+		q[off] ^= 0x10
+
+		_, err = load(q)
+		assert.ErrorIs(t, err, ErrChecksum, "offset %d", off)
+		_, err = LoadBytes(q)
+		assert.ErrorIs(t, err, ErrChecksum, "offset %d", off)
+	}
+
+	_, err = load(p[:len(p)-1])
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package joinfilter provides Bloom filters for hash joins in columnar
+// database engines. A Filter is built from the key hashes of the build
+// side and probed with batches of key hashes from the probe side,
+// producing selection vectors of the rows that may have a join partner.
+package joinfilter
+
+import (
+	"math/bits"
+
+	"github.com/greatroar/blobloom"
+)
+
+// Options configure Build.
+type Options struct {
+	// Target false positive rate. Defaults to 0.01.
+	FPRate float64
+
+	// Number of goroutines used to build the filter.
+	// Defaults to runtime.GOMAXPROCS(0).
+	Workers int
+
+	// Whether the key hashes are of poor quality and need mixing.
+	// See blobloom.Config.Premix.
+	Premix bool
+}
+
+// A Filter is a Bloom filter over the keys of the build side of a join.
+// It is safe for concurrent probing.
+type Filter struct {
+	f *blobloom.Filter
+
+	keys     uint64
+	min, max uint64 // Range of the build-side hashes.
+}
+
+// Build constructs a Filter containing hashes, in parallel.
+// The hashes slice must not be modified while Build runs.
+func Build(hashes []uint64, opts Options) *Filter {
+	if opts.FPRate <= 0 {
+		opts.FPRate = .01
+	}
+	config := blobloom.Config{
+		Capacity: uint64(len(hashes)),
+		FPRate:   opts.FPRate,
+		Premix:   opts.Premix,
+	}
+	f := &Filter{
+		f:    blobloom.ParallelBuild(hashes, config, opts.Workers),
+		keys: uint64(len(hashes)),
+	}
+	if len(hashes) > 0 {
+		f.min, f.max = hashes[0], hashes[0]
+	}
+	for _, h := range hashes {
+		if h < f.min {
+			f.min = h
+		}
+		if h > f.max {
+			f.max = h
+		}
+	}
+	return f
+}
+
+// Filter returns the underlying Bloom filter, e.g., to send it to other
+// nodes with blobloom.Dump. It must not be modified.
+func (f *Filter) Filter() *blobloom.Filter { return f.f }
+
+// Number of hashes probed at a time, as a multiple of 64.
+const chunkSize = 1024
+
+// Probe appends to sel the indices i for which hashes[i] may be in f,
+// in increasing order, and returns the extended sel.
+func (f *Filter) Probe(hashes []uint64, sel []uint32) []uint32 {
+	var found [chunkSize / 64]uint64
+
+	for off := 0; off < len(hashes); off += chunkSize {
+		chunk := hashes[off:]
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		sel = appendSelected(sel, f.f.HasBatchBits(chunk, found[:0]), func(i int) uint32 {
+			return uint32(off + i)
+		})
+	}
+	return sel
+}
+
+// ProbeSelected is like Probe, but only considers the rows in the
+// selection vector in, e.g., those that passed an earlier filter.
+// It appends to out the elements of in whose hashes may be in f,
+// in the order of in, and returns the extended out.
+// It is not safe to use the same backing array for in and out.
+func (f *Filter) ProbeSelected(hashes []uint64, in, out []uint32) []uint32 {
+	var (
+		buf   [chunkSize]uint64
+		found [chunkSize / 64]uint64
+	)
+
+	for len(in) > 0 {
+		chunk := in
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		in = in[len(chunk):]
+
+		for i, j := range chunk {
+			buf[i] = hashes[j]
+		}
+		out = appendSelected(out, f.f.HasBatchBits(buf[:len(chunk)], found[:0]), func(i int) uint32 {
+			return chunk[i]
+		})
+	}
+	return out
+}
+
+// appendSelected appends index(i) to sel for each set bit i in found.
+func appendSelected(sel []uint32, found []uint64, index func(int) uint32) []uint32 {
+	for w, x := range found {
+		for x != 0 {
+			i := 64*w + bits.TrailingZeros64(x)
+			sel = append(sel, index(i))
+			x &= x - 1
+		}
+	}
+	return sel
+}
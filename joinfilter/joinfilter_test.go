@@ -0,0 +1,89 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package joinfilter_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/greatroar/blobloom/joinfilter"
+	"github.com/stretchr/testify/assert"
+)
+
+func randomHashes(n int, seed int64) []uint64 {
+	r := rand.New(rand.NewSource(seed))
+	h := make([]uint64, n)
+	for i := range h {
+		h[i] = r.Uint64()
+	}
+	return h
+}
+
+func TestProbe(t *testing.T) {
+	t.Parallel()
+
+	build := randomHashes(5000, 0x701)
+	f := joinfilter.Build(build, joinfilter.Options{FPRate: 1e-3, Workers: 3})
+
+	// Probe side: every third row matches.
+	probe := randomHashes(3000, 0x702)
+	for i := 0; i < len(probe); i += 3 {
+		probe[i] = build[i]
+	}
+
+	sel := f.Probe(probe, []uint32{42})
+	assert.Equal(t, uint32(42), sel[0])
+	sel = sel[1:]
+
+	var want []uint32
+	for i, h := range probe {
+		if f.Filter().Has(h) {
+			want = append(want, uint32(i))
+		}
+	}
+	assert.Equal(t, want, sel)
+	assert.GreaterOrEqual(t, len(sel), 1000)
+	assert.Less(t, len(sel), 1020)
+
+	// Probe only the odd rows.
+	var odd []uint32
+	for i := 1; i < len(probe); i += 2 {
+		odd = append(odd, uint32(i))
+	}
+	want = want[:0]
+	for _, i := range odd {
+		if f.Filter().Has(probe[i]) {
+			want = append(want, i)
+		}
+	}
+	assert.Equal(t, want, f.ProbeSelected(probe, odd, nil))
+
+	assert.Empty(t, f.Probe(nil, nil))
+	assert.Empty(t, f.ProbeSelected(probe, nil, nil))
+}
+
+func BenchmarkProbe(b *testing.B) {
+	build := randomHashes(1<<20, 0x703)
+	f := joinfilter.Build(build, joinfilter.Options{})
+	probe := randomHashes(1<<12, 0x704)
+	sel := make([]uint32, 0, len(probe))
+
+	b.SetBytes(int64(8 * len(probe)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sel = f.Probe(probe, sel[:0])
+	}
+}
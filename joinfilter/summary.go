@@ -0,0 +1,84 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package joinfilter
+
+import (
+	"math"
+
+	"github.com/greatroar/blobloom"
+)
+
+// A Summary is a small description of a Filter that a query planner can
+// ship around in place of the filter, to decide whether probing the filter
+// is worthwhile compared to just scanning and joining.
+type Summary struct {
+	Keys uint64 // Number of build-side hashes, counting duplicates.
+
+	// Smallest and largest build-side hash. These are only useful when
+	// the hashes are the join keys themselves, as with Options.Premix,
+	// e.g., to compare against the min/max statistics of a scan.
+	Min, Max uint64
+
+	// Expected false positive rate, computed from the filter's bits.
+	FPRate float64
+
+	// Size of the filter in bytes, when dumped by blobloom.Dump.
+	Bytes int64
+}
+
+// Summary returns a Summary of f.
+func (f *Filter) Summary() Summary {
+	return Summary{
+		Keys:   f.keys,
+		Min:    f.min,
+		Max:    f.max,
+		FPRate: fpRate(f.f),
+		Bytes:  f.f.MarshaledSize(blobloom.DumpOptions{}),
+	}
+}
+
+// fpRate returns the false positive rate of f, given the occupancy of its
+// blocks: within a block, each hash value probes f.K()-1 bits.
+func fpRate(f *blobloom.Filter) float64 {
+	stats := f.Stats()
+	var p float64
+	for ones, n := range stats.Histogram {
+		if n > 0 {
+			p += float64(n) * math.Pow(float64(ones)/blobloom.BlockBits, float64(f.K()-1))
+		}
+	}
+	return p / float64(stats.Blocks)
+}
+
+// Overlaps reports whether the range [min, max] of probe-side hashes
+// overlaps that of the build side. If it does not, no probe row can
+// have a join partner.
+func (s Summary) Overlaps(min, max uint64) bool {
+	return s.Keys > 0 && min <= s.Max && max >= s.Min
+}
+
+// PassRate returns the expected fraction of probe rows that pass the filter,
+// given the fraction matchRate that have a join partner.
+func (s Summary) PassRate(matchRate float64) float64 {
+	return matchRate + (1-matchRate)*s.FPRate
+}
+
+// Worthwhile reports whether probing the filter is expected to pay off,
+// given the fraction of probe rows that have a join partner, the cost of
+// probing the filter per row and the cost per row of the work that is
+// saved by dropping a row, in the same unit.
+func (s Summary) Worthwhile(matchRate, probeCost, rowCost float64) bool {
+	return probeCost < (1-s.PassRate(matchRate))*rowCost
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package joinfilter_test
+
+import (
+	"testing"
+
+	"github.com/greatroar/blobloom"
+	"github.com/greatroar/blobloom/joinfilter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	// Hashes that are the keys themselves.
+	build := make([]uint64, 10000)
+	for i := range build {
+		build[i] = uint64(1000 + 3*i)
+	}
+	f := joinfilter.Build(build, joinfilter.Options{FPRate: .01, Workers: 2, Premix: true})
+	s := f.Summary()
+
+	assert.Equal(t, uint64(len(build)), s.Keys)
+	assert.Equal(t, uint64(1000), s.Min)
+	assert.Equal(t, uint64(1000+3*9999), s.Max)
+	assert.Equal(t, f.Filter().MarshaledSize(blobloom.DumpOptions{}), s.Bytes)
+
+	// The estimate is close to the measured false positive rate.
+	var fp int
+	probe := randomHashes(100000, 0x5e1)
+	for _, h := range probe {
+		if f.Filter().Has(h) {
+			fp++
+		}
+	}
+	measured := float64(fp) / float64(len(probe))
+	assert.InDelta(t, measured, s.FPRate, .2*measured)
+	assert.InDelta(t, .01, s.FPRate, .005)
+
+	assert.True(t, s.Overlaps(0, 1000))
+	assert.True(t, s.Overlaps(2000, 3000))
+	assert.True(t, s.Overlaps(0, 1<<40))
+	assert.False(t, s.Overlaps(0, 999))
+	assert.False(t, s.Overlaps(1000+3*9999+1, 1<<40))
+
+	assert.Equal(t, 1.0, s.PassRate(1))
+	assert.Equal(t, s.FPRate, s.PassRate(0))
+
+	// Probing costs 5ns per row. Dropping a row saves 100ns,
+	// which is worth it when few rows match.
+	assert.True(t, s.Worthwhile(.1, 5, 100))
+	assert.False(t, s.Worthwhile(.99, 5, 100))
+	assert.False(t, s.Worthwhile(.1, 100, 100))
+
+	empty := joinfilter.Build(nil, joinfilter.Options{}).Summary()
+	assert.False(t, empty.Overlaps(0, 1<<63))
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// A kernels is a set of implementations of the bulk operations on blocks.
+type kernels struct {
+	name string
+
+	// intersect and union operate on slices of equal length.
+	intersect, union func(a, b []block)
+
+	onescount, onescountAtomic func(*block) int
+}
+
+var (
+	// All available kernels. Only modified during initialization.
+	allKernels = []*kernels{&genericKernels}
+	curKernels uint32 // Index into allKernels.
+)
+
+// registerKernels adds k to the list of available kernels
+// and makes it the current implementation.
+// Kernels registered later are assumed to be faster.
+func registerKernels(k *kernels) {
+	allKernels = append(allKernels, k)
+	curKernels = uint32(len(allKernels) - 1)
+}
+
+func kernel() *kernels { return allKernels[atomic.LoadUint32(&curKernels)] }
+
+// Implementations returns the names of the implementations of bulk
+// operations (Union, Intersect, Cardinality) that are compiled into the
+// package, from slowest to fastest. The first is always "generic".
+// The last is the default.
+//
+// Which implementations are available depends on the architecture and
+// build tags. Currently, "unsafe64" is available on amd64 and arm64
+// unless the nounsafe or blobloomdebug build tag is set. The blobloomdebug
+// tag adds "debug", which checks the invariants that the others assume.
+func Implementations() []string {
+	names := make([]string, len(allKernels))
+	for i, k := range allKernels {
+		names[i] = k.name
+	}
+	return names
+}
+
+// Implementation returns the name of the current implementation.
+func Implementation() string { return kernel().name }
+
+// SetImplementation selects the implementation of bulk operations
+// by name. It returns an error if name is not one of Implementations().
+//
+// All implementations produce the same results. SetImplementation is
+// meant for debugging and benchmarking and is safe to call concurrently
+// with other operations.
+func SetImplementation(name string) error {
+	for i, k := range allKernels {
+		if k.name == name {
+			atomic.StoreUint32(&curKernels, uint32(i))
+			return nil
+		}
+	}
+	return fmt.Errorf("blobloom: unknown implementation %q", name)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKernels(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(0x4e7))
+	random := func(n int) []block {
+		b := make([]block, n)
+		for i := range b {
+			for j := range b[i] {
+				b[i][j] = r.Uint32()
+			}
+		}
+		return b
+	}
+
+	for _, k := range allKernels {
+		for _, n := range []int{0, 1, 2, 3, 8, 13} {
+			a, b := random(n), random(n)
+
+			for _, op := range []struct {
+				ref, got func(a, b []block)
+			}{
+				{genericKernels.intersect, k.intersect},
+				{genericKernels.union, k.union},
+			} {
+				expect := append([]block(nil), a...)
+				got := append([]block(nil), a...)
+				op.ref(expect, b)
+				op.got(got, b)
+				assert.Equal(t, expect, got, "%s, %d blocks", k.name, n)
+			}
+
+			for i := range a {
+				expect := onescountGeneric(&a[i])
+				assert.Equal(t, expect, k.onescount(&a[i]), k.name)
+				assert.Equal(t, expect, k.onescountAtomic(&a[i]), k.name)
+			}
+		}
+	}
+}
+
+func TestSetImplementation(t *testing.T) {
+	names := Implementations()
+	assert.Equal(t, "generic", names[0])
+	def := Implementation()
+	assert.Equal(t, names[len(names)-1], def)
+
+	for _, name := range names {
+		assert.NoError(t, SetImplementation(name))
+		assert.Equal(t, name, Implementation())
+
+		f := New(4*BlockBits, 3)
+		g := New(4*BlockBits, 3)
+		f.Add(1)
+		g.Add(2)
+		f.Union(g)
+		assert.True(t, f.Has(1))
+		assert.True(t, f.Has(2))
+	}
+
+	assert.Error(t, SetImplementation("avx1024"))
+	assert.NoError(t, SetImplementation(def))
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+// MaxKmerLength is the maximum k-mer length supported by a KmerHasher.
+const MaxKmerLength = 32
+
+// A KmerHasher computes hash values for the k-mers (substrings of length K)
+// of DNA sequences.
+//
+// Each base is encoded in two bits (A=0, C=1, G=2, T=3, case-insensitive),
+// so that a k-mer of up to 32 bases fits in a uint64. The encoding is
+// updated incrementally while sliding over a sequence, then passed through
+// a mixing function to produce a proper hash value. K-mers that contain
+// any other byte, such as N, are skipped.
+type KmerHasher struct {
+	// Length of the k-mers, between 1 and MaxKmerLength.
+	K int
+
+	// If Canonical is true, a k-mer and its reverse complement
+	// get the same hash value.
+	Canonical bool
+}
+
+// EncodeKmer returns the two-bit encoding of the bases in kmer.
+// It returns false if kmer is longer than MaxKmerLength
+// or contains bytes other than ACGT.
+func EncodeKmer(kmer []byte) (code uint64, ok bool) {
+	if len(kmer) > MaxKmerLength {
+		return 0, false
+	}
+	for _, c := range kmer {
+		x := baseCode[c]
+		if x > 3 {
+			return 0, false
+		}
+		code = code<<2 | uint64(x)
+	}
+	return code, true
+}
+
+// baseCode maps the bytes ACGTacgt to their two-bit codes.
+// All other bytes map to 4.
+var baseCode = func() (t [256]byte) {
+	for i := range t {
+		t[i] = 4
+	}
+	for i, c := range "ACGT" {
+		t[c] = byte(i)
+		t[c-'A'+'a'] = byte(i)
+	}
+	return t
+}()
+
+// kmerChunk is the number of k-mer positions that are hashed at a time,
+// so that Add and Has can pass the hash values to a Filter in batches.
+const kmerChunk = 256
+
+// Each calls fn with the position and hash value of each valid k-mer in seq,
+// in order.
+//
+// Each panics if kh.K is not between 1 and MaxKmerLength.
+func (kh KmerHasher) Each(seq []byte, fn func(pos int, h uint64)) {
+	kh.checkK()
+
+	var (
+		hbuf [kmerChunk]uint64
+		pbuf [kmerChunk]int
+	)
+	for i := 0; i+kh.K <= len(seq); i += kmerChunk {
+		hashes, pos := kh.appendChunk(hbuf[:0], pbuf[:0], seq, i)
+		for j, h := range hashes {
+			fn(pos[j], h)
+		}
+	}
+}
+
+// Add adds the hash values of all valid k-mers in seq to f
+// and returns the number of k-mers added.
+func (kh KmerHasher) Add(f *Filter, seq []byte) (n int) {
+	kh.checkK()
+
+	var hbuf [kmerChunk]uint64
+	for i := 0; i+kh.K <= len(seq); i += kmerChunk {
+		hashes, _ := kh.appendChunk(hbuf[:0], nil, seq, i)
+		f.AddBatch(hashes)
+		n += len(hashes)
+	}
+	return n
+}
+
+// Has reports, for each k-mer position in seq, whether f has the k-mer
+// starting at that position. The results are appended to found, which is
+// returned. Positions of invalid k-mers are reported as absent.
+//
+// The number of results is len(seq)-kh.K+1, or zero if seq is shorter than
+// a k-mer.
+func (kh KmerHasher) Has(f *Filter, seq []byte, found []bool) []bool {
+	kh.checkK()
+
+	start := len(found)
+	if n := len(seq) - kh.K + 1; n > 0 {
+		found = append(found, make([]bool, n)...)
+	}
+
+	var (
+		hbuf [kmerChunk]uint64
+		pbuf [kmerChunk]int
+		fbuf [kmerChunk]bool
+	)
+	for i := 0; i+kh.K <= len(seq); i += kmerChunk {
+		hashes, pos := kh.appendChunk(hbuf[:0], pbuf[:0], seq, i)
+		for j, ok := range f.HasBatch(hashes, fbuf[:0]) {
+			found[start+pos[j]] = ok
+		}
+	}
+	return found
+}
+
+func (kh KmerHasher) checkK() {
+	if kh.K < 1 || kh.K > MaxKmerLength {
+		panic("k-mer length must be between 1 and 32")
+	}
+}
+
+// appendChunk appends the hash values of the valid k-mers in seq that start
+// at positions start through start+kmerChunk-1 to hashes, and their
+// positions to pos if it is not nil.
+func (kh KmerHasher) appendChunk(hashes []uint64, pos []int, seq []byte, start int) ([]uint64, []int) {
+	k := kh.K
+	end := start + kmerChunk + k - 1
+	if end > len(seq) {
+		end = len(seq)
+	}
+
+	var (
+		mask  = ^uint64(0) >> (64 - 2*uint(k))
+		shift = 2 * uint(k-1)
+
+		fwd, rev uint64
+		valid    int // Number of valid bases at the end of the window.
+	)
+
+	for i := start; i < end; i++ {
+		x := uint64(baseCode[seq[i]])
+		if x > 3 {
+			valid = 0
+			continue
+		}
+		fwd = (fwd<<2 | x) & mask
+		rev = rev>>2 | (3-x)<<shift
+
+		if valid++; valid < k {
+			continue
+		}
+		code := fwd
+		if kh.Canonical && rev < code {
+			code = rev
+		}
+		hashes = append(hashes, mix64(code))
+		if pos != nil {
+			pos = append(pos, i-k+1)
+		}
+	}
+	return hashes, pos
+}
+
+// Hash returns the hash value of a single k-mer, which must be exactly
+// kh.K bases long. It returns false if kmer is not a valid k-mer.
+func (kh KmerHasher) Hash(kmer []byte) (uint64, bool) {
+	if len(kmer) != kh.K {
+		return 0, false
+	}
+	code, ok := EncodeKmer(kmer)
+	if !ok {
+		return 0, false
+	}
+	if kh.Canonical {
+		if rc := revcomp(code, kh.K); rc < code {
+			code = rc
+		}
+	}
+	return mix64(code), true
+}
+
+// revcomp returns the encoding of the reverse complement of the k-mer
+// encoded as code.
+func revcomp(code uint64, k int) (rc uint64) {
+	for i := 0; i < k; i++ {
+		rc = rc<<2 | (3 - code&3)
+		code >>= 2
+	}
+	return rc
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeKmer(t *testing.T) {
+	t.Parallel()
+
+	code, ok := EncodeKmer([]byte("ACgt"))
+	assert.True(t, ok)
+	assert.EqualValues(t, 0x1b, code)
+
+	_, ok = EncodeKmer([]byte("ACNT"))
+	assert.False(t, ok)
+
+	code, ok = EncodeKmer([]byte("GATTACA"))
+	assert.True(t, ok)
+	rc, _ := EncodeKmer([]byte("TGTAATC"))
+	assert.Equal(t, rc, revcomp(code, 7))
+}
+
+func TestKmerHasher(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(0xd7a))
+	seq := make([]byte, 1000)
+	for i := range seq {
+		seq[i] = "ACGT"[r.Intn(4)]
+	}
+	seq[500] = 'N'
+
+	for _, kh := range []KmerHasher{
+		{K: 1}, {K: 21}, {K: 21, Canonical: true}, {K: 32, Canonical: true},
+	} {
+		// The rolling hashes must match the hashes of individual k-mers.
+		var npos int
+		kh.Each(seq, func(pos int, h uint64) {
+			expect, ok := kh.Hash(seq[pos : pos+kh.K])
+			assert.True(t, ok)
+			assert.Equal(t, expect, h)
+			npos++
+		})
+		assert.Equal(t, len(seq)-kh.K+1-kh.K, npos, "K=%d", kh.K)
+
+		f := New(1<<16, 5)
+		assert.Equal(t, 500-kh.K+1, kh.Add(f, seq[:500]))
+
+		found := kh.Has(f, seq[:500], nil)
+		assert.Len(t, found, 500-kh.K+1)
+		for _, ok := range found {
+			assert.True(t, ok)
+		}
+
+		// K-mers that contain the N are absent, the others present.
+		kh.Add(f, seq)
+		found = kh.Has(f, seq, []bool{true})
+		assert.Len(t, found, 1+len(seq)-kh.K+1)
+		for pos, ok := range found[1:] {
+			assert.Equal(t, pos+kh.K <= 500 || pos > 500, ok, "K=%d, pos=%d", kh.K, pos)
+		}
+	}
+
+	kh := KmerHasher{K: 5, Canonical: true}
+	h1, _ := kh.Hash([]byte("GATTA"))
+	h2, _ := kh.Hash([]byte("TAATC"))
+	assert.Equal(t, h1, h2)
+
+	assert.Panics(t, func() { KmerHasher{K: 33}.Each(seq, nil) })
+}
+
+func BenchmarkKmerHasher(b *testing.B) {
+	r := rand.New(rand.NewSource(0xbe4c))
+	seq := make([]byte, 1<<16)
+	for i := range seq {
+		seq[i] = "ACGT"[r.Intn(4)]
+	}
+	kh := KmerHasher{K: 31, Canonical: true}
+	f := NewOptimized(Config{Capacity: 1 << 20, FPRate: 1e-3})
+	kh.Add(f, seq)
+	b.SetBytes(int64(len(seq)))
+
+	b.Run("op=Add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			kh.Add(f, seq)
+		}
+	})
+	b.Run("op=Has", func(b *testing.B) {
+		var found []bool
+		for i := 0; i < b.N; i++ {
+			found = kh.Has(f, seq, found[:0])
+		}
+	})
+}
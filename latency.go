@@ -0,0 +1,199 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// A LatencyHistogram records durations in logarithmic buckets with eight
+// sub-buckets per power of two, so quantiles are accurate to within 12.5%
+// over the whole range of time.Duration. Its zero value is ready for use.
+//
+// A LatencyHistogram is safe for concurrent use. Recording does not
+// allocate or lock. Like a Counter, a LatencyHistogram spreads its counts
+// over several shards, so that concurrent Records don't contend.
+// This takes about 60KiB of memory.
+type LatencyHistogram struct {
+	shards [counterShards][latencyBuckets]uint64
+}
+
+const (
+	latencySubBits = 3
+	latencySub     = 1 << latencySubBits
+	latencyBuckets = (63-latencySubBits-1)*latencySub + 2*latencySub
+)
+
+func latencyBucket(ns uint64) int {
+	if ns < 2*latencySub {
+		return int(ns)
+	}
+	e := bits.Len64(ns) - 1
+	m := ns >> uint(e-latencySubBits)
+	return (e-latencySubBits)*latencySub + int(m)
+}
+
+// latencyLower returns the smallest value in bucket i.
+func latencyLower(i int) uint64 {
+	if i < 2*latencySub {
+		return uint64(i)
+	}
+	e := i/latencySub + latencySubBits - 1
+	m := uint64(i%latencySub + latencySub)
+	return m << uint(e-latencySubBits)
+}
+
+// Record adds a duration to h. Negative durations are recorded as zero.
+func (h *LatencyHistogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	atomic.AddUint64(&h.shards[counterShard()][latencyBucket(uint64(d))], 1)
+}
+
+// load returns the counts per bucket, summed over the shards.
+func (h *LatencyHistogram) load() (counts [latencyBuckets]uint64) {
+	for s := range h.shards {
+		for i := range counts {
+			counts[i] += atomic.LoadUint64(&h.shards[s][i])
+		}
+	}
+	return counts
+}
+
+// Count returns the number of durations recorded.
+func (h *LatencyHistogram) Count() (n uint64) {
+	h.Buckets(func(_ time.Duration, count uint64) { n += count })
+	return n
+}
+
+// Quantile returns an upper bound on the q-quantile of the recorded
+// durations, for 0 <= q <= 1. It returns zero if h is empty.
+func (h *LatencyHistogram) Quantile(q float64) time.Duration {
+	counts := h.load()
+	total := uint64(0)
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(total))
+	if rank >= total {
+		rank = total - 1
+	}
+	seen := uint64(0)
+	for i, c := range counts {
+		seen += c
+		if seen > rank {
+			return latencyUpper(i)
+		}
+	}
+	panic("unreachable")
+}
+
+func latencyUpper(i int) time.Duration {
+	if i == latencyBuckets-1 {
+		return time.Duration(1<<63 - 1)
+	}
+	return time.Duration(latencyLower(i+1) - 1)
+}
+
+// Buckets calls fn for each non-empty bucket, in increasing order, with
+// the bucket's upper bound and count. It can be used to export h to
+// a metrics system.
+func (h *LatencyHistogram) Buckets(fn func(upper time.Duration, count uint64)) {
+	for i, c := range h.load() {
+		if c != 0 {
+			fn(latencyUpper(i), c)
+		}
+	}
+}
+
+// A TimedNode wraps a Node and records the latency of each of its
+// operations. Since a Cluster calls its nodes in the request path,
+// wrapping remote nodes in TimedNodes exposes their tail latency.
+type TimedNode struct {
+	node    Node
+	latency [len(nodeOps)]LatencyHistogram
+}
+
+var nodeOps = [...]string{"Add", "Has", "Export", "Import", "Drop", "Fingerprint"}
+
+const (
+	opAdd = iota
+	opHas
+	opExport
+	opImport
+	opDrop
+	opFingerprint
+)
+
+// NewTimedNode returns a TimedNode that forwards calls to n.
+func NewTimedNode(n Node) *TimedNode { return &TimedNode{node: n} }
+
+// Latency returns the histogram for the operation with the given name,
+// which is the name of a Node method. It returns nil for other names.
+func (t *TimedNode) Latency(op string) *LatencyHistogram {
+	for i, name := range nodeOps {
+		if name == op {
+			return &t.latency[i]
+		}
+	}
+	return nil
+}
+
+func (t *TimedNode) record(op int, start time.Time) {
+	t.latency[op].Record(time.Since(start))
+}
+
+// Add implements Node.
+func (t *TimedNode) Add(part int, hashes []uint64) error {
+	defer t.record(opAdd, time.Now())
+	return t.node.Add(part, hashes)
+}
+
+// Has implements Node.
+func (t *TimedNode) Has(part int, hashes []uint64, found []bool) error {
+	defer t.record(opHas, time.Now())
+	return t.node.Has(part, hashes, found)
+}
+
+// Export implements Node.
+func (t *TimedNode) Export(part int) ([]byte, error) {
+	defer t.record(opExport, time.Now())
+	return t.node.Export(part)
+}
+
+// Import implements Node.
+func (t *TimedNode) Import(part int, data []byte) error {
+	defer t.record(opImport, time.Now())
+	return t.node.Import(part, data)
+}
+
+// Drop implements Node.
+func (t *TimedNode) Drop(part int) error {
+	defer t.record(opDrop, time.Now())
+	return t.node.Drop(part)
+}
+
+// Fingerprint implements Node.
+func (t *TimedNode) Fingerprint(part int) (uint64, error) {
+	defer t.record(opFingerprint, time.Now())
+	return t.node.Fingerprint(part)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyBuckets(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, latencyBucket(0))
+	assert.Equal(t, latencyBuckets-1, latencyBucket(1<<63-1))
+
+	for i := 0; i < latencyBuckets; i++ {
+		lo := latencyLower(i)
+		assert.Equal(t, i, latencyBucket(lo))
+		if i > 0 {
+			assert.Equal(t, i-1, latencyBucket(lo-1))
+		}
+	}
+
+	r := rand.New(rand.NewSource(0x1a7))
+	for i := 0; i < 1000; i++ {
+		ns := uint64(r.Int63()) >> uint(r.Intn(63))
+		b := latencyBucket(ns)
+		assert.LessOrEqual(t, latencyLower(b), ns)
+		assert.LessOrEqual(t, ns, uint64(latencyUpper(b)))
+	}
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	var h LatencyHistogram
+	assert.Zero(t, h.Quantile(.5))
+
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+	h.Record(-1)
+	assert.EqualValues(t, 1001, h.Count())
+
+	for _, q := range []float64{.5, .9, .99} {
+		exact := time.Duration(q*1000) * time.Microsecond
+		got := h.Quantile(q)
+		assert.GreaterOrEqual(t, int64(got), int64(exact))
+		assert.LessOrEqual(t, float64(got), 1.13*float64(exact))
+	}
+	assert.GreaterOrEqual(t, int64(h.Quantile(1)), int64(time.Millisecond))
+}
+
+func TestTimedNode(t *testing.T) {
+	t.Parallel()
+
+	c := NewCluster(Config{Capacity: 1000, FPRate: 1e-2}, ClusterOptions{})
+	n := NewTimedNode(c.NewLocalNode())
+	require.NoError(t, c.Join("n", n))
+
+	hashes := randomU64(100, 0x71)
+	require.NoError(t, c.Add(hashes...))
+	for _, h := range hashes {
+		found, err := c.Has(h)
+		require.NoError(t, err)
+		assert.True(t, found)
+	}
+
+	assert.EqualValues(t, len(hashes), n.Latency("Has").Count())
+	assert.NotZero(t, n.Latency("Add").Count())
+	assert.Zero(t, n.Latency("Export").Count())
+	assert.Nil(t, n.Latency("Union"))
+}
+
+func BenchmarkLatencyHistogram(b *testing.B) {
+	h := new(LatencyHistogram)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			h.Record(250 * time.Microsecond)
+		}
+	})
+}
@@ -0,0 +1,304 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"io"
+	"sort"
+	"sync"
+)
+
+// A LazyFilter is a read-only Filter whose blocks are read from a dump
+// on demand, through an io.ReaderAt, and kept in a small LRU cache.
+// It suits very large filters that are queried too rarely to be worth
+// loading completely.
+//
+// A LazyFilter is safe for concurrent use if its io.ReaderAt is,
+// as an *os.File is.
+type LazyFilter struct {
+	r       io.ReaderAt
+	nblocks uint64
+	k       int
+	premix  bool
+	layout  Layout
+
+	mu    sync.Mutex
+	size  int
+	lru   list.List // Of *lazyBlock, most recently used at the front.
+	cache map[uint64]*list.Element
+}
+
+type lazyBlock struct {
+	i uint64
+	b block
+}
+
+// NewLazy returns a LazyFilter for the filter dump in r, which must be
+// uncompressed. The LazyFilter caches up to cacheBlocks blocks, or one
+// block if cacheBlocks < 1.
+//
+// Checksums in the dump are not verified, since that would require
+// reading all of it.
+func NewLazy(r io.ReaderAt, cacheBlocks int) (*LazyFilter, error) {
+	var hdr [BlockBits / 8]byte
+	n, err := r.ReadAt(hdr[:], 0)
+	switch {
+	case n >= 8 && string(hdr[:8]) != "blobloom":
+		return nil, errorf(ErrFormat, "blobloom: LazyFilter needs an uncompressed dump")
+	case n < len(hdr):
+		return nil, unexpectedEOF(err)
+	}
+	l, err := NewLoader(bytes.NewReader(hdr[:]))
+	if err == nil {
+		err = l.checkKind(KindFilter)
+	}
+	if err == nil && l.comp != NoCompression {
+		err = errorf(ErrFormat, "blobloom: LazyFilter needs an uncompressed dump")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if l.nblocks > MaxBits/BlockBits {
+		return nil, errorf(ErrTooLarge, "blobloom: %d blocks is too large", l.nblocks)
+	}
+
+	// Fail early on truncated input.
+	var last [1]byte
+	if err := readAt(r, last[:], int64(l.nblocks+1)*BlockBits/8-1); err != nil {
+		return nil, err
+	}
+
+	if cacheBlocks < 1 {
+		cacheBlocks = 1
+	}
+	return &LazyFilter{
+		r:       r,
+		nblocks: l.nblocks,
+		k:       l.nhashes,
+		premix:  l.premix,
+		layout:  l.layout,
+		size:    cacheBlocks,
+		cache:   make(map[uint64]*list.Element, cacheBlocks),
+	}, nil
+}
+
+// readAt fills p from r at offset off. Unlike r.ReadAt, it does not
+// return io.EOF for a complete read, and it returns io.ErrUnexpectedEOF
+// for an incomplete one.
+func readAt(r io.ReaderAt, p []byte, off int64) error {
+	n, err := r.ReadAt(p, off)
+	if n == len(p) {
+		return nil
+	}
+	return unexpectedEOF(err)
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF || err == nil {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+//
+// Has reads the block for h from the underlying io.ReaderAt
+// if it is not cached, and returns any error from doing so.
+func (f *LazyFilter) Has(h uint64) (bool, error) {
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	b, err := f.block(reducerange(blk, f.nblocks))
+	if err != nil {
+		return false, err
+	}
+	return probe(h1, h2, f.k, b.getbit), nil
+}
+
+// block returns a copy of block i, from the cache or from f.r.
+func (f *LazyFilter) block(i uint64) (block, error) {
+	f.mu.Lock()
+	b, ok := f.cached(i)
+	f.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+
+	// Don't hold the lock during I/O. Concurrent misses on the same block
+	// may both read it; only the first to finish caches it.
+	var dst [1]block
+	if err := f.readBlocks(i, dst[:]); err != nil {
+		return b, err
+	}
+	f.mu.Lock()
+	f.insert(i, &dst[0])
+	f.mu.Unlock()
+	return dst[0], nil
+}
+
+// cached returns block i if it is in the cache. The caller must hold f.mu.
+func (f *LazyFilter) cached(i uint64) (b block, ok bool) {
+	e, ok := f.cache[i]
+	if ok {
+		f.lru.MoveToFront(e)
+		b = e.Value.(*lazyBlock).b
+	}
+	return b, ok
+}
+
+// insert adds block i to the cache. The caller must hold f.mu.
+func (f *LazyFilter) insert(i uint64, b *block) {
+	if e, ok := f.cache[i]; ok {
+		f.lru.MoveToFront(e)
+		return
+	}
+	if len(f.cache) >= f.size {
+		last := f.lru.Back()
+		f.lru.Remove(last)
+		delete(f.cache, last.Value.(*lazyBlock).i)
+	}
+	f.cache[i] = f.lru.PushFront(&lazyBlock{i: i, b: *b})
+}
+
+// readBlocks reads len(dst) consecutive blocks, starting at block first,
+// from f.r in a single call.
+func (f *LazyFilter) readBlocks(first uint64, dst []block) error {
+	buf := make([]byte, len(dst)*BlockBits/8)
+	if err := readAt(f.r, buf, int64(first+1)*BlockBits/8); err != nil {
+		return err
+	}
+	for i := range dst {
+		for j := range dst[i] {
+			dst[i][j] = binary.LittleEndian.Uint32(buf[BlockBits/8*i+4*j:])
+		}
+	}
+	return nil
+}
+
+// Maximum number of blocks read by a single ReadAt call in HasBatch.
+const lazyMaxRun = 256
+
+// Maximum number of concurrent ReadAt calls in HasBatch.
+const lazyParallelReads = 8
+
+// HasBatch reports, for each of hashes, whether f has that hash value.
+// The results are appended to found, which is returned.
+//
+// HasBatch reads the uncached blocks for all of hashes before probing.
+// Reads of adjacent blocks are merged and up to eight reads are issued
+// concurrently, which pays off when the io.ReaderAt has a high latency,
+// as for a filter in remote storage.
+func (f *LazyFilter) HasBatch(hashes []uint64, found []bool) ([]bool, error) {
+	var (
+		blocks  = make(map[uint64]*block, len(hashes))
+		mixed   = make([]uint64, len(hashes))
+		missing []uint64
+	)
+
+	f.mu.Lock()
+	for i, h := range hashes {
+		if f.premix {
+			h = mix64(h)
+		}
+		mixed[i] = h
+		blk, _, _ := f.layout.split(h)
+		j := reducerange(blk, f.nblocks)
+		if _, ok := blocks[j]; ok {
+			continue
+		}
+		b, ok := f.cached(j)
+		if !ok {
+			missing = append(missing, j)
+		}
+		blocks[j] = &b
+	}
+	f.mu.Unlock()
+
+	if err := f.readMissing(missing, blocks); err != nil {
+		return found, err
+	}
+
+	for _, h := range mixed {
+		blk, h1, h2 := f.layout.split(h)
+		b := blocks[reducerange(blk, f.nblocks)]
+		found = append(found, probe(h1, h2, f.k, b.getbit))
+	}
+	return found, nil
+}
+
+// readMissing reads the blocks with indices in missing into blocks,
+// and adds them to the cache.
+func (f *LazyFilter) readMissing(missing []uint64, blocks map[uint64]*block) error {
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+
+	type run struct {
+		first uint64
+		b     []block
+		err   error
+	}
+	var runs []*run
+	for k := 0; k < len(missing); {
+		n := 1
+		for k+n < len(missing) && n < lazyMaxRun && missing[k+n] == missing[k]+uint64(n) {
+			n++
+		}
+		runs = append(runs, &run{first: missing[k], b: make([]block, n)})
+		k += n
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, lazyParallelReads)
+	for _, r := range runs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *run) {
+			defer wg.Done()
+			r.err = f.readBlocks(r.first, r.b)
+			<-sem
+		}(r)
+	}
+	wg.Wait()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range runs {
+		if r.err != nil {
+			return r.err
+		}
+		for i := range r.b {
+			*blocks[r.first+uint64(i)] = r.b[i]
+			f.insert(r.first+uint64(i), &r.b[i])
+		}
+	}
+	return nil
+}
+
+// CachedBlocks returns the number of blocks in the cache.
+func (f *LazyFilter) CachedBlocks() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.cache)
+}
+
+// K returns the number of hash functions of f.
+func (f *LazyFilter) K() int { return f.k }
+
+// NumBits returns the number of bits of f.
+func (f *LazyFilter) NumBits() uint64 { return BlockBits * f.nblocks }
@@ -0,0 +1,175 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"container/list"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A countingReaderAt counts the bytes read from it.
+type countingReaderAt struct {
+	r *bytes.Reader
+
+	mu    sync.Mutex
+	n     int // Bytes read.
+	calls int
+}
+
+func (r *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.r.ReadAt(p, off)
+	r.mu.Lock()
+	r.n += n
+	r.calls++
+	r.mu.Unlock()
+	return n, err
+}
+
+func TestLazyFilter(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 10000, FPRate: 1e-3, Premix: true, Layout: LayoutV1}
+	f := NewOptimized(config)
+	hashes := randomU64(20000, 0x1a2f)
+	for _, h := range hashes[:10000] {
+		f.Add(h)
+	}
+
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "lazy")
+	require.NoError(t, err)
+	r := &countingReaderAt{r: bytes.NewReader(buf.Bytes())}
+
+	lf, err := NewLazy(r, 8)
+	require.NoError(t, err)
+	assert.Equal(t, f.K(), lf.K())
+	assert.Equal(t, f.NumBits(), lf.NumBits())
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(hashes []uint64) {
+			defer wg.Done()
+			for _, h := range hashes {
+				found, err := lf.Has(h)
+				assert.NoError(t, err)
+				assert.Equal(t, f.Has(h), found)
+			}
+		}(hashes[w*5000 : (w+1)*5000])
+	}
+	wg.Wait()
+	assert.Equal(t, 8, lf.CachedBlocks())
+
+	// Cached blocks are not read again.
+	h := hashes[0]
+	lf.Has(h)
+	n := r.n
+	lf.Has(h)
+	assert.Equal(t, n, r.n)
+
+	// Only touched blocks are read.
+	lf, err = NewLazy(r, 0)
+	require.NoError(t, err)
+	r.n = 0
+	lf.Has(h)
+	assert.Equal(t, BlockBits/8, r.n)
+}
+
+func TestLazyFilterHasBatch(t *testing.T) {
+	t.Parallel()
+
+	f := New(1<<16, 5)
+	hashes := randomU64(3000, 0xba7c)
+	for _, h := range hashes[:1000] {
+		f.Add(h)
+	}
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "")
+	require.NoError(t, err)
+	r := &countingReaderAt{r: bytes.NewReader(buf.Bytes())}
+
+	lf, err := NewLazy(r, 16)
+	require.NoError(t, err)
+	lf.Has(hashes[0]) // Put something in the cache.
+	calls, n := r.calls, r.n
+
+	found, err := lf.HasBatch(hashes, []bool{true})
+	require.NoError(t, err)
+	require.Len(t, found, 1+len(hashes))
+	for i, h := range hashes {
+		assert.Equal(t, f.Has(h), found[i+1])
+	}
+	// 3000 random hashes touch all of the 128 blocks. The uncached ones
+	// are read in at most two runs, around the cached one.
+	assert.LessOrEqual(t, r.calls-calls, 2)
+	assert.Equal(t, (f.NumBlocks()-1)*BlockBits/8, r.n-n)
+	assert.Equal(t, 16, lf.CachedBlocks())
+
+	lf.r = bytes.NewReader(buf.Bytes()[:BlockBits/8*10])
+	lf.size, lf.cache = 1, map[uint64]*list.Element{}
+	lf.lru.Init()
+	_, err = lf.HasBatch(hashes, nil)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestLazyFilterErrors(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	_, err := Dump(&buf, New(1<<14, 3), "")
+	require.NoError(t, err)
+	p := append([]byte(nil), buf.Bytes()...)
+
+	_, err = NewLazy(bytes.NewReader(p[:len(p)-1]), 1)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	_, err = NewLazy(bytes.NewReader(p[:10]), 1)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(p)
+	require.NoError(t, w.Close())
+	_, err = NewLazy(bytes.NewReader(gz.Bytes()), 1)
+	assert.ErrorIs(t, err, ErrFormat)
+	buf.Reset()
+	_, err = DumpCompressed(&buf, New(1<<14, 3), "")
+	require.NoError(t, err)
+	_, err = NewLazy(bytes.NewReader(buf.Bytes()), 1)
+	assert.ErrorIs(t, err, ErrFormat)
+
+	buf.Reset()
+	_, err = DumpCountMin(&buf, NewCountMin(64, 2), "")
+	require.NoError(t, err)
+	_, err = NewLazy(bytes.NewReader(buf.Bytes()), 1)
+	assert.ErrorIs(t, err, ErrShapeMismatch)
+
+	// Truncation after NewLazy.
+	lf, err := NewLazy(bytes.NewReader(p), 1)
+	require.NoError(t, err)
+	lf.r = bytes.NewReader(p[:2*BlockBits/8])
+	for _, h := range randomU64(100, 0x7e4) {
+		if _, err = lf.Has(h); err != nil {
+			break
+		}
+	}
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nounsafe
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"bytes"
+	"hash/crc32"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// inPlace returns a Filter that uses the blocks in the uncompressed dump p,
+// whose header l has parsed, or nil if that is not possible.
+func (l *Loader) inPlace(p []byte) *Filter {
+	const hdrSize = BlockBits / 8
+
+	var sections, trailer uint64
+	if l.chunks != nil {
+		sections = uint64(chunkSumsSize(l.nblocks)) / hdrSize
+	}
+	if l.crc != nil {
+		trailer = 1
+	}
+	switch {
+	case string(p[:8]) != "blobloom", l.comp != NoCompression,
+		!littleEndian(),
+		l.nblocks > MaxBits/BlockBits,
+		uint64(len(p)-hdrSize)/hdrSize < l.nblocks+sections+trailer, // Truncated.
+		uintptr(unsafe.Pointer(&p[hdrSize]))%4 != 0:
+		return nil
+	}
+	// On a checksum mismatch, return nil to let Load report the error.
+	if sections != 0 {
+		end := hdrSize * (l.nblocks + 1)
+		if !chunkSumsOK(p[hdrSize:end], p[end:]) {
+			return nil
+		}
+	}
+	if trailer != 0 {
+		size := hdrSize * (l.nblocks + 1 + sections)
+		crc := crc32.Checksum(p[:size], castagnoli)
+		if !bytes.Equal(appendTrailer(nil, crc), p[size:size+hdrSize]) {
+			return nil
+		}
+	}
+
+	var b []block
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	hdr.Data = uintptr(unsafe.Pointer(&p[hdrSize]))
+	hdr.Len, hdr.Cap = int(l.nblocks), int(l.nblocks)
+	runtime.KeepAlive(p)
+	return &Filter{b: b, k: l.nhashes, premix: l.premix, layout: l.layout}
+}
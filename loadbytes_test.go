@@ -0,0 +1,47 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nounsafe
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBytesInPlace(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	_, err := Dump(&buf, New(1<<12, 4), "")
+	require.NoError(t, err)
+	p := buf.Bytes()
+
+	f, err := LoadBytes(p)
+	require.NoError(t, err)
+	assert.True(t, f.Empty())
+
+	if !littleEndian() {
+		t.Skip("LoadBytes copies on big-endian machines")
+	}
+	f.Add(0x1234)
+	g, err := LoadBytes(p)
+	require.NoError(t, err)
+	assert.True(t, g.Has(0x1234), "Add did not modify p")
+}
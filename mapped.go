@@ -0,0 +1,239 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nounsafe
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"unsafe"
+)
+
+// A MappedFilter is a Filter whose blocks live in a memory-mapped file
+// in the format written by Dump. It can be larger than available memory,
+// shared between processes and opened without reading the whole file.
+//
+// A MappedFilter holds operating system resources that are released
+// deterministically by Close, not by the garbage collector. After Close,
+// Flush, Sync and Close return ErrClosed and the Filter methods panic.
+//
+// Only one process at a time can map a file for writing. Writers hold an
+// exclusive advisory lock on the file, and other writers get ErrLocked.
+// Read-only mappings take no lock.
+//
+// MappedFilters are only available on little-endian Unix-like and Windows
+// machines, and not when the nounsafe build tag is set.
+type MappedFilter struct {
+	*Filter
+
+	file     *os.File
+	data     []byte
+	writable bool
+}
+
+var _ io.Closer = (*MappedFilter)(nil)
+
+var errBigEndian = errors.New("blobloom: memory-mapped filters require a little-endian machine")
+
+// CreateMapped creates a file at path, which must not exist, containing
+// an empty filter for the given config, and maps it for writing.
+func CreateMapped(path string, config Config) (*MappedFilter, error) {
+	config.Layout.check()
+	nbits, nhashes := fixBitsAndHashes(Optimize(config))
+	nblocks := int(nbits / BlockBits)
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return nil, err
+	}
+	hdr := appendHeader(nil, nblocks, nhashes, config.Premix, config.Layout, "")
+	_, err = file.Write(hdr)
+	if err == nil {
+		err = file.Truncate(marshaledSize(nblocks))
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		return nil, err
+	}
+
+	m, err := mapFile(file, true)
+	if err != nil {
+		os.Remove(path)
+	}
+	return m, err
+}
+
+// OpenMapped maps the filter in the file at path, which must contain an
+// uncompressed dump.
+//
+// If writable is true, keys added to the filter are written to the file.
+// Otherwise, the mapping is private: keys can still be added, but they
+// are visible only to the MappedFilter and are not written to the file.
+func OpenMapped(path string, writable bool) (*MappedFilter, error) {
+	flag := os.O_RDONLY
+	if writable {
+		flag = os.O_RDWR
+	}
+	file, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return nil, err
+	}
+	return mapFile(file, writable)
+}
+
+// OpenOrCreateMapped maps the filter in the file at path for writing,
+// creating the file with an empty filter for config if it does not exist.
+// This lets a long-running service keep its filter on disk across restarts.
+//
+// If the file exists, config is not used; in particular, the filter
+// in it is not checked to match config.
+func OpenOrCreateMapped(path string, config Config) (*MappedFilter, error) {
+	m, err := OpenMapped(path, true)
+	if os.IsNotExist(err) {
+		m, err = CreateMapped(path, config)
+		if os.IsExist(err) {
+			// Lost a race against another creator.
+			m, err = OpenMapped(path, true)
+		}
+	}
+	return m, err
+}
+
+// OpenMmap maps the filter in the file at path, as OpenMapped(path, false)
+// does, and returns it as a plain Filter. The filter's blocks are read from
+// the file on demand, so opening even a very large filter is fast and takes
+// little memory. Keys added to the filter do not reach the file.
+//
+// Call Free on the Filter to unmap it and close the file. The garbage
+// collector does not do this.
+func OpenMmap(path string) (*Filter, error) {
+	m, err := OpenMapped(path, false)
+	if err != nil {
+		return nil, err
+	}
+	m.Filter.free = func() { m.Close() }
+	return m.Filter, nil
+}
+
+// mapFile maps file, taking ownership of it.
+func mapFile(file *os.File, writable bool) (m *MappedFilter, err error) {
+	defer func() {
+		if err != nil {
+			file.Close()
+		}
+	}()
+
+	if !littleEndian() {
+		return nil, errBigEndian
+	}
+	if writable {
+		if err = lock(file); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := NewLoader(io.NewSectionReader(file, 0, 64))
+	if err == nil {
+		err = l.checkKind(KindFilter)
+	}
+	if err == nil && l.comp != NoCompression {
+		err = errorf(ErrFormat, "blobloom: cannot map a compressed dump")
+	}
+	if err == nil && (l.crc != nil || l.chunks != nil) {
+		// Writes through the mapping would invalidate the checksums.
+		err = errorf(ErrFormat, "blobloom: cannot map a dump with checksums")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if l.nblocks > MaxBits/BlockBits {
+		return nil, errorf(ErrTooLarge, "blobloom: %d blocks is too large", l.nblocks)
+	}
+	size := marshaledSize(int(l.nblocks))
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() != size {
+		return nil, errorf(ErrFormat, "blobloom: file %s has size %d, expected %d",
+			file.Name(), info.Size(), size)
+	}
+
+	data, err := mmap(file, int(size), writable)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []block
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	hdr.Data = uintptr(unsafe.Pointer(&data[BlockBits/8]))
+	hdr.Len, hdr.Cap = int(l.nblocks), int(l.nblocks)
+
+	return &MappedFilter{
+		Filter:   &Filter{b: b, k: l.nhashes, premix: l.premix, layout: l.layout},
+		file:     file,
+		data:     data,
+		writable: writable,
+	}, nil
+}
+
+func littleEndian() bool {
+	x := uint32(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}
+
+// Flush starts writing changes to the file, without waiting for them
+// to reach stable storage. Changes are visible to other processes that
+// map or read the file even without Flush, and they survive a crash of
+// the current process, but not of the operating system.
+// Flush is a no-op for filters that were not opened as writable.
+func (m *MappedFilter) Flush() error { return m.msync(false) }
+
+// Sync flushes changes to the file to stable storage.
+// It is a no-op for filters that were not opened as writable.
+func (m *MappedFilter) Sync() error { return m.msync(true) }
+
+func (m *MappedFilter) msync(wait bool) error {
+	if m.data == nil {
+		return ErrClosed
+	}
+	if !m.writable {
+		return nil
+	}
+	return msync(m.file, m.data, wait)
+}
+
+// Close unmaps the filter and closes the file. It does not Sync.
+// Close returns ErrClosed if m has already been closed.
+func (m *MappedFilter) Close() error {
+	if m.data == nil {
+		return ErrClosed
+	}
+	// Make sure the Filter methods panic instead of accessing
+	// unmapped memory.
+	m.Filter.b = nil
+
+	err := munmap(m.data)
+	m.data = nil
+	if e := m.file.Close(); err == nil {
+		err = e
+	}
+	return err
+}
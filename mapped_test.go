@@ -0,0 +1,183 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build (darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows) && !nounsafe
+// +build darwin dragonfly freebsd linux netbsd openbsd windows
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappedFilter(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobloom")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "filter.bloom")
+
+	config := Config{Capacity: 1000, FPRate: 1e-3, Premix: true, Layout: LayoutV1}
+	m, err := CreateMapped(path, config)
+	require.NoError(t, err)
+	_, err = CreateMapped(path, config)
+	assert.Error(t, err, "overwrote existing file")
+
+	hashes := randomU64(2000, 0x3a9)
+	f := NewOptimized(config)
+	for _, h := range hashes[:1000] {
+		m.Add(h)
+		f.Add(h)
+	}
+	assert.True(t, f.Equals(m.Filter))
+	require.NoError(t, m.Flush())
+	require.NoError(t, m.Sync())
+
+	// Single writer, any number of readers.
+	_, err = OpenMapped(path, true)
+	assert.Equal(t, ErrLocked, err)
+	r, err := OpenMapped(path, false)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	require.NoError(t, m.Close())
+
+	// The file is an ordinary dump.
+	var buf bytes.Buffer
+	_, err = Dump(&buf, f, "")
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), content)
+
+	// Read-only mappings are private.
+	m, err = OpenMapped(path, false)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(m.Filter))
+	for _, h := range hashes[1000:] {
+		m.Add(h)
+	}
+	assert.NoError(t, m.Flush())
+	assert.NoError(t, m.Sync())
+	require.NoError(t, m.Close())
+	content, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), content)
+
+	// Use after close.
+	assert.Equal(t, ErrClosed, m.Close())
+	assert.Equal(t, ErrClosed, m.Flush())
+	assert.Equal(t, ErrClosed, m.Sync())
+	assert.Panics(t, func() { m.Has(hashes[0]) })
+
+	// Checksummed dumps cannot be mapped.
+	cpath := filepath.Join(dir, "checksum.bloom")
+	checked, err := AppendDump(nil, f, DumpOptions{EmbedChecksum: true})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(cpath, checked, 0666))
+	_, err = OpenMapped(cpath, false)
+	assert.ErrorIs(t, err, ErrFormat)
+	checked, err = AppendDump(nil, f, DumpOptions{ChunkChecksums: true})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(cpath, checked, 0666))
+	_, err = OpenMapped(cpath, false)
+	assert.ErrorIs(t, err, ErrFormat)
+
+	// Nor can compressed ones.
+	checked, err = AppendDump(nil, f, DumpOptions{Compression: Gzip})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(cpath, checked, 0666))
+	_, err = OpenMapped(cpath, false)
+	assert.ErrorIs(t, err, ErrFormat)
+
+	// Truncated file.
+	require.NoError(t, os.Truncate(path, int64(len(content)-1)))
+	_, err = OpenMapped(path, true)
+	assert.Error(t, err)
+}
+
+func TestOpenOrCreateMapped(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobloom")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "filter.bloom")
+
+	config := Config{Capacity: 1000, FPRate: 1e-3}
+	hashes := randomU64(1000, 0x0c4ea7e)
+
+	m, err := OpenOrCreateMapped(path, config)
+	require.NoError(t, err)
+	assert.True(t, m.Empty())
+	for _, h := range hashes {
+		m.Add(h)
+	}
+	require.NoError(t, m.Flush())
+	require.NoError(t, m.Close())
+
+	// Restart.
+	m, err = OpenOrCreateMapped(path, config)
+	require.NoError(t, err)
+	defer m.Close()
+	for _, h := range hashes {
+		assert.True(t, m.Has(h))
+	}
+
+	_, err = OpenOrCreateMapped(path, config)
+	assert.Equal(t, ErrLocked, err)
+}
+
+func TestOpenMmap(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blobloom")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "filter.bloom")
+
+	f := NewOptimized(Config{Capacity: 1000, FPRate: 1e-4, Premix: true})
+	hashes := randomU64(1000, 0x33a9)
+	for _, h := range hashes {
+		f.Add(h)
+	}
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	_, err = Dump(file, f, "")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	g, err := OpenMmap(path)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(g))
+	for _, h := range hashes {
+		assert.True(t, g.Has(h))
+	}
+
+	g.Free()
+	assert.Panics(t, func() { g.Has(hashes[0]) })
+	g.Free()
+
+	_, err = OpenMmap(filepath.Join(dir, "nonexistent"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "sync"
+
+// Migrate returns a new Filter with the given config, to which it adds
+// those of keys that old has. It is meant for moving to a filter with
+// a different capacity or false positive rate, when the keys are stored
+// elsewhere.
+//
+// Keys that old does not have are skipped, so that the new filter does not
+// get keys that were never added to the old one. If old is nil, all keys
+// are added.
+//
+// The keys argument has the same type as iter.Seq[uint64].
+func Migrate(old *Filter, config Config, keys func(yield func(uint64) bool)) *Filter {
+	f := NewOptimized(config)
+	keys(func(h uint64) bool {
+		if old == nil || old.Has(h) {
+			f.Add(h)
+		}
+		return true
+	})
+	return f
+}
+
+// A Migration moves a service from an old filter to a new one with a
+// different Config while both are in use.
+//
+// During a migration, keys are added to both filters, while lookups are
+// answered by the old one. Existing keys are copied to the new filter with
+// Backfill. Once Verify reports no missing keys, Finish switches lookups
+// to the new filter.
+//
+// A Migration is safe for concurrent use.
+type Migration struct {
+	mu       sync.RWMutex
+	old, new *Filter
+	finished bool
+}
+
+// NewMigration starts a migration from old to an empty Filter with the
+// given config. The old filter must not be used directly during the
+// migration.
+func NewMigration(old *Filter, config Config) *Migration {
+	return &Migration{old: old, new: NewOptimized(config)}
+}
+
+// Add inserts a key with hash value h into both filters,
+// or only into the new filter once the migration is finished.
+func (m *Migration) Add(h uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.finished {
+		m.old.Add(h)
+	}
+	m.new.Add(h)
+}
+
+// Has reports whether a key with hash value h has been added, according
+// to the old filter, or the new filter once the migration is finished.
+func (m *Migration) Has(h uint64) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.finished {
+		return m.new.Has(h)
+	}
+	return m.old.Has(h)
+}
+
+// Backfill adds those of keys that the old filter has to the new filter.
+func (m *Migration) Backfill(keys func(yield func(uint64) bool)) {
+	keys(func(h uint64) bool {
+		m.mu.Lock()
+		if m.old.Has(h) {
+			m.new.Add(h)
+		}
+		m.mu.Unlock()
+		return true
+	})
+}
+
+// MigrationReport is the result of Migration.Verify.
+type MigrationReport struct {
+	Checked uint64 // Number of keys checked.
+	Missing uint64 // Keys that the old filter has, but the new one does not.
+	Extra   uint64 // Keys that the new filter has, but the old one does not.
+}
+
+// Verify compares the answers of the old and new filters for keys.
+//
+// After a complete Backfill, Missing should be zero. Extra counts false
+// positives of the new filter and keys added during the migration that
+// the old filter has not seen.
+func (m *Migration) Verify(keys func(yield func(uint64) bool)) (r MigrationReport) {
+	keys(func(h uint64) bool {
+		m.mu.RLock()
+		inOld, inNew := m.old.Has(h), m.new.Has(h)
+		m.mu.RUnlock()
+
+		r.Checked++
+		switch {
+		case inOld && !inNew:
+			r.Missing++
+		case inNew && !inOld:
+			r.Extra++
+		}
+		return true
+	})
+	return r
+}
+
+// Finish switches lookups to the new filter, stops adding keys
+// to the old one, and returns the new filter.
+//
+// The returned Filter must not be used directly while the Migration
+// is still in use.
+func (m *Migration) Finish() *Filter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.finished = true
+	return m.new
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func seq(hashes []uint64) func(func(uint64) bool) {
+	return func(yield func(uint64) bool) {
+		for _, h := range hashes {
+			if !yield(h) {
+				return
+			}
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(3000, 0x319)
+	keys, others := hashes[:1000], hashes[1000:]
+
+	old := NewOptimized(Config{Capacity: 1000, FPRate: 1e-2})
+	for _, h := range keys {
+		old.Add(h)
+	}
+
+	config := Config{Capacity: 2000, FPRate: 1e-6, Premix: true}
+	f := Migrate(old, config, seq(hashes))
+	for _, h := range keys {
+		assert.True(t, f.Has(h))
+	}
+	// Only false positives of old make it into f.
+	n := 0
+	for _, h := range others {
+		if f.Has(h) {
+			assert.True(t, old.Has(h))
+			n++
+		}
+	}
+	assert.Less(t, n, 100)
+
+	f = Migrate(nil, config, seq(hashes))
+	for _, h := range hashes {
+		assert.True(t, f.Has(h))
+	}
+}
+
+func TestMigration(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(3000, 0x31a)
+	keys, live, others := hashes[:1000], hashes[1000:2000], hashes[2000:]
+
+	old := NewOptimized(Config{Capacity: 2000, FPRate: 1e-2})
+	for _, h := range keys {
+		old.Add(h)
+	}
+
+	m := NewMigration(old, Config{Capacity: 2000, FPRate: 1e-6})
+	for _, h := range live {
+		m.Add(h)
+	}
+	r := m.Verify(seq(keys))
+	assert.EqualValues(t, len(keys), r.Checked)
+	assert.Greater(t, r.Missing, uint64(990))
+
+	m.Backfill(seq(keys))
+	r = m.Verify(seq(hashes[:2000]))
+	assert.Equal(t, MigrationReport{Checked: 2000}, r)
+
+	f := m.Finish()
+	m.Add(others[0])
+	assert.True(t, m.Has(others[0]))
+	assert.True(t, f.Has(others[0]))
+	for _, h := range hashes[:2000] {
+		assert.True(t, m.Has(h))
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows && !nounsafe
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows,!nounsafe
+
+package blobloom
+
+import (
+	"errors"
+	"os"
+)
+
+var errNoMmap = errors.New("blobloom: memory-mapped filters not supported on this platform")
+
+func mmap(file *os.File, size int, writable bool) ([]byte, error) { return nil, errNoMmap }
+func munmap(data []byte) error                                    { return errNoMmap }
+func msync(file *os.File, data []byte, wait bool) error           { return errNoMmap }
+func lock(file *os.File) error                                    { return errNoMmap }
@@ -0,0 +1,67 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build (darwin || dragonfly || freebsd || linux || netbsd || openbsd) && !nounsafe
+// +build darwin dragonfly freebsd linux netbsd openbsd
+// +build !nounsafe
+
+package blobloom
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func mmap(file *os.File, size int, writable bool) ([]byte, error) {
+	// A private mapping is copy-on-write, so that modifications of
+	// a read-only filter are possible, but don't reach the file.
+	flags := syscall.MAP_PRIVATE
+	if writable {
+		flags = syscall.MAP_SHARED
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, size,
+		syscall.PROT_READ|syscall.PROT_WRITE, flags)
+	if err != nil {
+		return nil, os.NewSyscallError("mmap", err)
+	}
+	return data, nil
+}
+
+func munmap(data []byte) error {
+	return os.NewSyscallError("munmap", syscall.Munmap(data))
+}
+
+func msync(file *os.File, data []byte, wait bool) error {
+	flags := syscall.MS_ASYNC
+	if wait {
+		flags = syscall.MS_SYNC
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC,
+		uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)), uintptr(flags))
+	if errno != 0 {
+		return os.NewSyscallError("msync", errno)
+	}
+	return nil
+}
+
+// lock takes an exclusive advisory lock on file,
+// which is released when file is closed.
+func lock(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return ErrLocked
+	}
+	return os.NewSyscallError("flock", err)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows && !nounsafe
+// +build windows,!nounsafe
+
+package blobloom
+
+import (
+	"os"
+	"reflect"
+	"syscall"
+	"unsafe"
+)
+
+var procLockFileEx = syscall.NewLazyDLL("kernel32.dll").NewProc("LockFileEx")
+
+const (
+	errorLockViolation      syscall.Errno = 33
+	lockfileExclusiveLock                 = 0x2
+	lockfileFailImmediately               = 0x1
+)
+
+// lock takes an exclusive lock on file,
+// which is released when file is closed.
+func lock(file *os.File) error {
+	// Lock a byte far beyond the end of the file. Windows locks are
+	// mandatory, so locking the contents would block other readers.
+	ol := syscall.Overlapped{Offset: ^uint32(0), OffsetHigh: ^uint32(0)}
+	r, _, err := procLockFileEx.Call(file.Fd(),
+		lockfileExclusiveLock|lockfileFailImmediately, 0, 1, 0,
+		uintptr(unsafe.Pointer(&ol)))
+	switch {
+	case r != 0:
+		return nil
+	case err == errorLockViolation:
+		return ErrLocked
+	}
+	return os.NewSyscallError("LockFileEx", err)
+}
+
+func mmap(file *os.File, size int, writable bool) ([]byte, error) {
+	// FILE_MAP_COPY is the equivalent of MAP_PRIVATE.
+	prot, access := uint32(syscall.PAGE_WRITECOPY), uint32(syscall.FILE_MAP_COPY)
+	if writable {
+		prot, access = syscall.PAGE_READWRITE, syscall.FILE_MAP_WRITE
+	}
+
+	size64 := uint64(size)
+	h, err := syscall.CreateFileMapping(syscall.Handle(file.Fd()), nil, prot,
+		uint32(size64>>32), uint32(size64), nil)
+	if err != nil {
+		return nil, os.NewSyscallError("CreateFileMapping", err)
+	}
+	// The view keeps the mapping object alive.
+	defer syscall.CloseHandle(h)
+
+	addr, err := syscall.MapViewOfFile(h, access, 0, 0, uintptr(size))
+	if err != nil {
+		return nil, os.NewSyscallError("MapViewOfFile", err)
+	}
+
+	var data []byte
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&data))
+	hdr.Data, hdr.Len, hdr.Cap = addr, size, size
+	return data, nil
+}
+
+func munmap(data []byte) error {
+	addr := uintptr(unsafe.Pointer(&data[0]))
+	return os.NewSyscallError("UnmapViewOfFile", syscall.UnmapViewOfFile(addr))
+}
+
+func msync(file *os.File, data []byte, wait bool) error {
+	// FlushViewOfFile only starts writing dirty pages;
+	// FlushFileBuffers waits for them to reach the disk.
+	addr := uintptr(unsafe.Pointer(&data[0]))
+	if err := syscall.FlushViewOfFile(addr, uintptr(len(data))); err != nil {
+		return os.NewSyscallError("FlushViewOfFile", err)
+	}
+	if !wait {
+		return nil
+	}
+	return os.NewSyscallError("FlushFileBuffers",
+		syscall.FlushFileBuffers(syscall.Handle(file.Fd())))
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "container/list"
+
+// A NegativeCache wraps a Filter with a small, exact LRU cache of hashes
+// that the Filter reported as present, but that a more expensive check
+// found to be absent.
+//
+// When the same false positive is looked up repeatedly (a hot key),
+// the cache suppresses the repeated downstream checks. A NegativeCache
+// never introduces false negatives: adding a key through the
+// NegativeCache removes it from the cache.
+//
+// A NegativeCache is not safe for concurrent use.
+type NegativeCache struct {
+	f *Filter
+
+	size  int
+	lru   list.List // Of uint64, most recently used at the front.
+	elems map[uint64]*list.Element
+}
+
+// NewNegativeCache returns a NegativeCache that remembers up to size
+// confirmed absent hashes for f. The size is silently increased to one
+// if a lower value is given.
+func NewNegativeCache(f *Filter, size int) *NegativeCache {
+	if size < 1 {
+		size = 1
+	}
+	return &NegativeCache{
+		f:     f,
+		size:  size,
+		elems: make(map[uint64]*list.Element, size),
+	}
+}
+
+// Add inserts a key with hash value h into the underlying Filter
+// and forgets any record of it being absent.
+func (c *NegativeCache) Add(h uint64) {
+	c.Forget(h)
+	c.f.Add(h)
+}
+
+// Filter returns the underlying Filter.
+//
+// Keys added to the Filter directly must be passed to Forget,
+// or Has may return false negatives for them.
+func (c *NegativeCache) Filter() *Filter { return c.f }
+
+// Forget removes h from the cache, if present.
+func (c *NegativeCache) Forget(h uint64) {
+	if e, ok := c.elems[h]; ok {
+		c.lru.Remove(e)
+		delete(c.elems, h)
+	}
+}
+
+// Has reports whether a key with hash value h has been added.
+// It returns false without consulting the Filter if h was recently
+// marked absent.
+func (c *NegativeCache) Has(h uint64) bool {
+	if e, ok := c.elems[h]; ok {
+		c.lru.MoveToFront(e)
+		return false
+	}
+	return c.f.Has(h)
+}
+
+// Len returns the number of hashes in the cache.
+func (c *NegativeCache) Len() int { return len(c.elems) }
+
+// MarkAbsent records that the key with hash value h is known to be absent,
+// typically after Has returned true and a downstream check disagreed.
+// If the cache is full, the least recently used hash is evicted.
+func (c *NegativeCache) MarkAbsent(h uint64) {
+	if e, ok := c.elems[h]; ok {
+		c.lru.MoveToFront(e)
+		return
+	}
+	if len(c.elems) >= c.size {
+		last := c.lru.Back()
+		c.lru.Remove(last)
+		delete(c.elems, last.Value.(uint64))
+	}
+	c.elems[h] = c.lru.PushFront(h)
+}
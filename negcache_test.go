@@ -0,0 +1,50 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegativeCache(t *testing.T) {
+	t.Parallel()
+
+	f := New(BlockBits, 2)
+	f.Fill() // Everything is a false positive.
+
+	c := NewNegativeCache(f, 2)
+	assert.True(t, c.Has(1))
+
+	c.MarkAbsent(1)
+	c.MarkAbsent(2)
+	assert.False(t, c.Has(1))
+	assert.False(t, c.Has(2))
+	assert.Equal(t, 2, c.Len())
+
+	// 1 was used more recently than 2, so 2 gets evicted.
+	c.Has(1)
+	c.MarkAbsent(3)
+	assert.False(t, c.Has(1))
+	assert.True(t, c.Has(2))
+	assert.False(t, c.Has(3))
+	assert.Equal(t, 2, c.Len())
+
+	// Adding a key must not leave a stale negative entry.
+	c.Add(3)
+	assert.True(t, c.Has(3))
+	assert.Equal(t, 1, c.Len())
+}
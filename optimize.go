@@ -35,16 +35,47 @@ type Config struct {
 	// Maximum size of the Bloom filter in bits. Zero means the global
 	// MaxBits constant. A value less than BlockBits means BlockBits.
 	MaxBits uint64
+
+	// If Premix is true, the Bloom filter runs every hash value passed to it
+	// through a fast mixing function (the SplitMix64 finalizer) before use.
+	// This protects the false positive rate when the "hash values" are really
+	// sequential IDs, timestamps or the output of a weak hash function.
+	//
+	// Premixing is recorded by Dump and restored by a Loader.
+	// Optimize ignores this setting.
+	Premix bool
+
+	// Layout selects the mapping of hash values to bits.
+	// The default is LayoutV0, but LayoutV1 should be used
+	// for filters larger than 512MiB.
+	//
+	// The layout is recorded by Dump and restored by a Loader.
+	// Optimize ignores this setting.
+	Layout Layout
 }
 
-// NewOptimized is shorthand for New(Optimize(config)).
+// NewOptimized is shorthand for New(Optimize(config)),
+// except that it also applies config.Premix and config.Layout.
+//
+// NewOptimized panics if config.Layout is not a known layout.
 func NewOptimized(config Config) *Filter {
-	return New(Optimize(config))
+	config.Layout.check()
+	f := New(Optimize(config))
+	f.premix = config.Premix
+	f.layout = config.Layout
+	return f
 }
 
-// NewSyncOptimized is shorthand for New(Optimize(config)).
+// NewSyncOptimized is shorthand for NewSync(Optimize(config)),
+// except that it also applies config.Premix and config.Layout.
+//
+// NewSyncOptimized panics if config.Layout is not a known layout.
 func NewSyncOptimized(config Config) *SyncFilter {
-	return NewSync(Optimize(config))
+	config.Layout.check()
+	f := NewSync(Optimize(config))
+	f.premix = config.Premix
+	f.layout = config.Layout
+	return f
 }
 
 // Optimize returns numbers of keys and hash functions that achieve the
@@ -199,3 +230,35 @@ func logPoisson(λ, k float64) float64 {
 	lg, _ := math.Lgamma(k + 1)
 	return k*math.Log(λ) - λ - lg
 }
+
+// OptimizeK returns the lowest number of hash functions, at most that of f,
+// with which f would have an estimated false positive rate of at most fpRate
+// after nkeys distinct keys have been added. If no such number exists,
+// it returns f's current number of hash functions.
+//
+// For a filter that is much sparser than designed, such as the union of
+// many small shards, the result may be well below the current number.
+// Passing it to ReduceHashes makes Has probe fewer bits.
+func (f *Filter) OptimizeK(nkeys uint64, fpRate float64) int {
+	for k := 2; k < f.k; k++ {
+		if FPRate(nkeys, f.NumBits(), k) <= fpRate {
+			return k
+		}
+	}
+	return f.k
+}
+
+// ReduceHashes lowers the number of hash functions of f to k.
+//
+// Since the bits probed for a key with k hash functions are a subset of
+// those probed with more, this introduces no false negatives, but raises
+// the false positive rate, as reported by f.FPRate. Keys added afterwards
+// set only k bits. Dump records the new number.
+//
+// ReduceHashes panics if k < 2 or k is greater than the current number.
+func (f *Filter) ReduceHashes(k int) {
+	if k < 2 || k > f.k {
+		panic("blobloom: invalid number of hashes for ReduceHashes")
+	}
+	f.k = k
+}
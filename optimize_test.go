@@ -162,3 +162,34 @@ func TestOptimizeInvalidFPRate(t *testing.T) {
 	assert.Panics(t, func() { Optimize(Config{FPRate: 0}) })
 	assert.Panics(t, func() { Optimize(Config{FPRate: 1.0000001}) })
 }
+
+func TestOptimizeK(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1e5, FPRate: 1e-4}
+	hashes := randomU64(2e4, 0x0b7)
+
+	// Union of shards, containing much fewer keys than designed.
+	f := NewOptimized(config)
+	for i := 0; i < 4; i++ {
+		shard := NewOptimized(config)
+		for _, h := range hashes[5000*i : 5000*(i+1)] {
+			shard.Add(h)
+		}
+		f.Union(shard)
+	}
+
+	k := f.OptimizeK(uint64(len(hashes)), config.FPRate)
+	assert.Less(t, k, f.k)
+	assert.LessOrEqual(t, FPRate(uint64(len(hashes)), f.NumBits(), k), config.FPRate)
+	assert.Equal(t, f.k, f.OptimizeK(1e6, 1e-9))
+
+	f.ReduceHashes(k)
+	assert.Equal(t, k, f.k)
+	for _, h := range hashes {
+		assert.True(t, f.Has(h))
+	}
+
+	assert.Panics(t, func() { f.ReduceHashes(k + 1) })
+	assert.Panics(t, func() { f.ReduceHashes(1) })
+}
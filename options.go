@@ -0,0 +1,75 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+// An Option configures a Filter constructed by NewWithOptions.
+type Option func(*options)
+
+type options struct {
+	nbits   uint64
+	nhashes int
+	premix  bool
+	layout  Layout
+	new     func(nbits uint64, nhashes int) *Filter
+}
+
+// NewWithOptions constructs a Filter as configured by opts. Later options
+// override earlier ones. Without options, it returns the same as New(0, 0).
+//
+// NewWithOptions panics if the options specify an unknown Layout,
+// or more than MaxBits bits.
+func NewWithOptions(opts ...Option) *Filter {
+	o := options{new: New}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.layout.check()
+
+	f := o.new(o.nbits, o.nhashes)
+	f.premix, f.layout = o.premix, o.layout
+	return f
+}
+
+// WithBits sets the number of bits, which is adjusted as New adjusts it.
+func WithBits(nbits uint64) Option {
+	return func(o *options) { o.nbits = nbits }
+}
+
+// WithHashes sets the number of hash functions,
+// which is adjusted as New adjusts it.
+func WithHashes(nhashes int) Option {
+	return func(o *options) { o.nhashes = nhashes }
+}
+
+// WithPremix sets whether the Filter premixes hash values.
+// See Config.Premix.
+func WithPremix(premix bool) Option {
+	return func(o *options) { o.premix = premix }
+}
+
+// WithLayout sets the Filter's Layout.
+func WithLayout(layout Layout) Option {
+	return func(o *options) { o.layout = layout }
+}
+
+// WithConfig sets the numbers of bits and hash functions to those
+// returned by Optimize(config), and applies config.Premix and config.Layout.
+func WithConfig(config Config) Option {
+	nbits, nhashes := Optimize(config)
+	return func(o *options) {
+		o.nbits, o.nhashes = nbits, nhashes
+		o.premix, o.layout = config.Premix, config.Layout
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, New(0, 0).Equals(NewWithOptions()))
+
+	f := NewWithOptions(WithBits(10*BlockBits), WithHashes(5), WithLayout(LayoutV1))
+	assert.EqualValues(t, 10*BlockBits, f.NumBits())
+	assert.Equal(t, 5, f.k)
+	assert.False(t, f.premix)
+	assert.Equal(t, LayoutV1, f.layout)
+
+	config := Config{Capacity: 1e4, FPRate: 1e-3, Premix: true, Layout: LayoutV1}
+	assert.True(t, NewOptimized(config).Equals(NewWithOptions(WithConfig(config))))
+
+	// Later options override earlier ones.
+	f = NewWithOptions(WithConfig(config), WithHashes(2), WithPremix(false))
+	assert.Equal(t, 2, f.k)
+	assert.False(t, f.premix)
+
+	assert.Panics(t, func() { NewWithOptions(WithLayout(LayoutV1 + 1)) })
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"encoding/binary"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// ParallelBuild constructs a Filter with NewOptimized(config) and adds
+// all of hashes to it, using the given number of goroutines.
+// If workers < 1, it uses runtime.GOMAXPROCS(0) goroutines.
+//
+// Each goroutine owns a contiguous range of blocks and only sets bits
+// in that range, so no synchronization is needed beyond waiting for the
+// goroutines to finish. To get each hash to the goroutine that owns its
+// block, the goroutines first sort parts of hashes into buckets, one per
+// range, in a single pass. This takes a copy of hashes in memory.
+// The resulting Filter is identical to one built sequentially.
+//
+// The hashes slice must not be modified while ParallelBuild runs.
+func ParallelBuild(hashes []uint64, config Config, workers int) *Filter {
+	f := NewOptimized(config)
+
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	nblocks := uint64(len(f.b))
+	if uint64(workers) > nblocks {
+		workers = int(nblocks)
+	}
+	if workers > len(hashes) {
+		workers = len(hashes)
+	}
+	if workers <= 1 {
+		for _, h := range hashes {
+			f.Add(h)
+		}
+		return f
+	}
+
+	// Worker w owns the blocks [w*rangeSize, (w+1)*rangeSize).
+	rangeSize := (nblocks + uint64(workers) - 1) / uint64(workers)
+	partSize := (len(hashes) + workers - 1) / workers
+
+	// buckets[w][v] holds the hashes from part w of hashes whose blocks
+	// are owned by worker v, mixed if f.premix.
+	buckets := make([][][]uint64, workers)
+	runWorkers(workers, func(w int) {
+		part := hashes[:0]
+		if lo := w * partSize; lo < len(hashes) {
+			part = hashes[lo:]
+		}
+		if len(part) > partSize {
+			part = part[:partSize]
+		}
+		b := make([][]uint64, workers)
+		for v := range b {
+			b[v] = make([]uint64, 0, len(part)/workers)
+		}
+		for _, h := range part {
+			if f.premix {
+				h = mix64(h)
+			}
+			blk, _, _ := f.layout.split(h)
+			v := reducerange(blk, nblocks) / rangeSize
+			b[v] = append(b[v], h)
+		}
+		buckets[w] = b
+	})
+
+	runWorkers(workers, func(v int) {
+		for _, b := range buckets {
+			f.addMixed(b[v])
+		}
+	})
+	return f
+}
+
+// runWorkers calls fn(w) for w in [0,n), each in its own goroutine,
+// and waits for all calls to return.
+func runWorkers(n int, fn func(w int)) {
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for w := 0; w < n; w++ {
+		go func(w int) {
+			fn(w)
+			wg.Done()
+		}(w)
+	}
+	wg.Wait()
+}
+
+// addMixed adds hashes to f, which have already been mixed if f.premix.
+func (f *Filter) addMixed(hashes []uint64) {
+	for _, h := range hashes {
+		blk, h1, h2 := f.layout.split(h)
+		b := getblock(f.b, blk)
+		probe(h1, h2, f.k, func(bit uint32) bool {
+			b.setbit(bit)
+			return true
+		})
+	}
+}
+
+// Number of blocks read and decoded at a time by LoadParallel (256KiB).
+const loadChunkBlocks = 4096
+
+// LoadParallel is like Load, but reads and decodes blocks in the given
+// number of goroutines. If workers < 1, it uses runtime.GOMAXPROCS(0)
+// goroutines.
+//
+// LoadParallel only runs in parallel when the Loader's io.Reader is also
+// an io.ReaderAt and an io.Seeker, such as an *os.File or *bytes.Reader,
+// and the dump is neither compressed nor checksummed (see
+// DumpOptions.EmbedChecksum and DumpOptions.ChunkChecksums).
+// Otherwise, it is equivalent to Load.
+// On success, the reader is positioned after the dump.
+func (l *Loader) LoadParallel(f *Filter, workers int) (*Filter, error) {
+	type readSeekerAt interface {
+		io.ReaderAt
+		io.Seeker
+	}
+	r, ok := l.r.(readSeekerAt)
+
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers == 1 || !ok || l.chunks != nil {
+		return l.Load(f)
+	}
+	f, err := l.filterFor(f)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	nchunks := (len(f.b) + loadChunkBlocks - 1) / loadChunkBlocks
+	if workers > nchunks {
+		workers = nchunks
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		loaded   int
+		next     int // Next chunk to load.
+		wg       sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			var buf []byte
+
+			for {
+				mu.Lock()
+				c := next
+				next++
+				stop := firstErr != nil
+				mu.Unlock()
+				if stop || c >= nchunks {
+					return
+				}
+
+				b := f.b[c*loadChunkBlocks:]
+				if len(b) > loadChunkBlocks {
+					b = b[:loadChunkBlocks]
+				}
+				if buf == nil {
+					buf = make([]byte, loadChunkBlocks*BlockBits/8)
+				}
+				p := buf[:len(b)*BlockBits/8]
+				off := start + int64(c)*loadChunkBlocks*BlockBits/8
+
+				// ReadAt may return io.EOF along with a full p.
+				n, err := r.ReadAt(p, off)
+				switch {
+				case err == io.EOF && n == len(p):
+					err = nil
+				case err == io.EOF:
+					err = io.ErrUnexpectedEOF
+				}
+				if err == nil {
+					decodeBlocks(b, p)
+				}
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				if err == nil {
+					l.reportProgress(loaded, loaded+len(b))
+					loaded += len(b)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr == nil {
+		_, firstErr = r.Seek(int64(len(f.b))*BlockBits/8, io.SeekCurrent)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return f, nil
+}
+
+// decodeBlocks ORs the little-endian encoded blocks in p into b.
+func decodeBlocks(b []block, p []byte) {
+	for i := range b {
+		q := p[i*BlockBits/8:]
+		for j := range b[i] {
+			b[i][j] |= binary.LittleEndian.Uint32(q[4*j:])
+		}
+	}
+}
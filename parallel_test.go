@@ -0,0 +1,131 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelBuild(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(1e4, 0x9a7a11e1)
+
+	for _, cfg := range []Config{
+		{Capacity: 1e4, FPRate: 1e-4},
+		{Capacity: 1e4, FPRate: 1e-4, Premix: true},
+		{Capacity: 1e4, FPRate: 1e-4, MaxBits: 3 * BlockBits},
+	} {
+		ref := NewOptimized(cfg)
+		for _, h := range hashes {
+			ref.Add(h)
+		}
+
+		for _, workers := range []int{0, 1, 3, 16} {
+			f := ParallelBuild(hashes, cfg, workers)
+			assert.True(t, ref.Equals(f))
+		}
+
+		// Fewer hashes than workers, and parts that run out of hashes.
+		ref = NewOptimized(cfg)
+		for _, h := range hashes[:5] {
+			ref.Add(h)
+		}
+		for _, workers := range []int{4, 16} {
+			f := ParallelBuild(hashes[:5], cfg, workers)
+			assert.True(t, ref.Equals(f))
+		}
+	}
+}
+
+func TestLoadParallel(t *testing.T) {
+	t.Parallel()
+
+	for _, nblocks := range []uint64{1, 7, loadChunkBlocks, 3*loadChunkBlocks + 5} {
+		f := New(nblocks*BlockBits, 4)
+		for _, h := range randomU64(int(8*nblocks), int64(nblocks)) {
+			f.Add(h)
+		}
+		var buf bytes.Buffer
+		_, err := Dump(&buf, f, "")
+		require.NoError(t, err)
+		dump := buf.Bytes()
+
+		for _, workers := range []int{0, 1, 2, 5} {
+			r := bytes.NewReader(dump)
+			l, err := NewLoader(r)
+			require.NoError(t, err)
+			var progress uint64
+			l.WithProgress(func(n, total uint64) { progress = n })
+			g, err := l.LoadParallel(nil, workers)
+			require.NoError(t, err)
+			assert.True(t, f.Equals(g))
+			assert.Equal(t, nblocks, progress)
+			assert.Equal(t, 0, r.Len())
+
+			// A ReaderAt may return io.EOF with the final chunk.
+			l, err = NewLoader(eofReader{bytes.NewReader(dump)})
+			require.NoError(t, err)
+			g, err = l.LoadParallel(nil, workers)
+			require.NoError(t, err)
+			assert.True(t, f.Equals(g))
+
+			// Truncated input.
+			l, err = NewLoader(bytes.NewReader(dump[:len(dump)-1]))
+			require.NoError(t, err)
+			_, err = l.LoadParallel(nil, workers)
+			assert.Error(t, err)
+		}
+	}
+}
+
+// An eofReader returns io.EOF from ReadAt when reading up to the end
+// of its input, as io.ReaderAt allows.
+type eofReader struct{ *bytes.Reader }
+
+func (r eofReader) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.Reader.ReadAt(p, off)
+	if err == nil && off+int64(n) == r.Size() {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func BenchmarkLoad(b *testing.B) {
+	f := New(1<<30, 4) // 128MiB.
+	var buf bytes.Buffer
+	_, err := Dump(&buf, f, "")
+	require.NoError(b, err)
+	dump := buf.Bytes()
+
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprint("workers=", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(dump)))
+			for i := 0; i < b.N; i++ {
+				l, _ := NewLoader(bytes.NewReader(dump))
+				_, err := l.LoadParallel(f, workers)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+)
+
+// A ReadThrough answers lookups for a Cluster while building a local copy
+// of the Cluster's filter, so that it can keep answering when the Cluster
+// is unavailable.
+//
+// Until the local copy is warm, Has asks the Cluster and only falls back
+// to the local copy, for partitions that have been copied, when the Cluster
+// fails. Once every partition has been copied, Has answers locally.
+// Keys added to the Cluster by other clients become visible locally on
+// the next Sync.
+//
+// A ReadThrough is safe for concurrent use.
+type ReadThrough struct {
+	c      *Cluster
+	local  *SyncFilter
+	loaded []uint32 // Per partition, 1 if copied. Accessed atomically.
+	nwarm  int32    // Number of partitions copied. Accessed atomically.
+}
+
+// NewReadThrough returns a ReadThrough for c with an empty local copy.
+// It allocates memory for the full filter.
+func NewReadThrough(c *Cluster) *ReadThrough {
+	shape := &c.shape
+	local := &SyncFilter{
+		b:      makeBlocks(shape.nblocks),
+		k:      shape.k,
+		premix: shape.premix,
+		layout: shape.layout,
+	}
+	return &ReadThrough{c: c, local: local, loaded: make([]uint32, c.npart)}
+}
+
+// Add inserts keys into the Cluster and into the local copy.
+func (r *ReadThrough) Add(hashes ...uint64) error {
+	for _, h := range hashes {
+		r.local.Add(h)
+	}
+	return r.c.Add(hashes...)
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+//
+// If the Cluster fails and the key's partition has not been copied yet,
+// Has returns the Cluster's answer and error.
+func (r *ReadThrough) Has(h uint64) (bool, error) {
+	if r.Warm() {
+		return r.local.Has(h), nil
+	}
+	found, err := r.c.Has(h)
+	if err != nil && atomic.LoadUint32(&r.loaded[r.c.partition(h)]) != 0 {
+		return r.local.Has(h), nil
+	}
+	return found, err
+}
+
+// Warm reports whether all partitions have been copied.
+func (r *ReadThrough) Warm() bool {
+	return int(atomic.LoadInt32(&r.nwarm)) == len(r.loaded)
+}
+
+// Sync copies all partitions from the Cluster into the local copy,
+// merging them with what has been copied before. It returns the first error
+// encountered, but keeps going for the other partitions.
+func (r *ReadThrough) Sync(ctx context.Context) error {
+	var err error
+	for p := range r.loaded {
+		if e := ctx.Err(); e != nil {
+			return e
+		}
+		if e := r.syncPartition(p); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (r *ReadThrough) syncPartition(p int) error {
+	data, err := r.c.exportPartition(p)
+	if err != nil {
+		return err
+	}
+
+	first := uint64(p) * r.c.partBlocks
+	b := r.local.b[first:]
+	if n := uint64(len(data)) / (BlockBits / 8); n > uint64(len(b)) || len(data)%(BlockBits/8) != 0 {
+		return errorf(ErrShapeMismatch, "blobloom: wrong data size %d for partition %d", len(data), p)
+	}
+	for i := 0; i < len(data)/4; i++ {
+		orAtomic(&b[i/blockWords][i%blockWords], binary.LittleEndian.Uint32(data[4*i:]))
+	}
+
+	if atomic.SwapUint32(&r.loaded[p], 1) == 0 {
+		atomic.AddInt32(&r.nwarm, 1)
+	}
+	return nil
+}
+
+// Run calls Sync at the given interval until ctx is done, passing any
+// error to report, if it is not nil. The first Sync happens immediately.
+// Run is meant to be run in its own goroutine.
+func (r *ReadThrough) Run(ctx context.Context, interval time.Duration, report func(error)) {
+	t := r.c.opts.Clock.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if err := r.Sync(ctx); err != nil && report != nil && ctx.Err() == nil {
+			report(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C():
+		}
+	}
+}
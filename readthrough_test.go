@@ -0,0 +1,109 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A downNode fails all operations when down is set.
+type downNode struct {
+	*LocalNode
+	down bool
+}
+
+var errNodeDown = errors.New("node down")
+
+func (n *downNode) Has(part int, hashes []uint64, found []bool) error {
+	if n.down {
+		return errNodeDown
+	}
+	return n.LocalNode.Has(part, hashes, found)
+}
+
+func (n *downNode) Export(part int) ([]byte, error) {
+	if n.down {
+		return nil, errNodeDown
+	}
+	return n.LocalNode.Export(part)
+}
+
+func TestReadThrough(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1e4, FPRate: 1e-3}
+	c := NewCluster(config, ClusterOptions{Partitions: 8})
+	a := &downNode{LocalNode: c.NewLocalNode()}
+	b := &downNode{LocalNode: c.NewLocalNode()}
+	require.NoError(t, c.Join("a", a))
+	require.NoError(t, c.Join("b", b))
+
+	hashes := randomU64(2000, 0x4ea)
+	require.NoError(t, c.Add(hashes[:1000]...))
+
+	r := NewReadThrough(c)
+	assert.False(t, r.Warm())
+
+	// Cold, but the cluster is up.
+	for _, h := range hashes[:1000] {
+		found, err := r.Has(h)
+		require.NoError(t, err)
+		assert.True(t, found)
+	}
+
+	// Partial sync: a's partitions fail to copy.
+	a.down = true
+	assert.Equal(t, errNodeDown, r.Sync(context.Background()))
+	assert.False(t, r.Warm())
+	b.down = true
+	for _, h := range hashes[:1000] {
+		found, err := r.Has(h)
+		if c.Owners(h)[0] == "b" {
+			assert.NoError(t, err)
+			assert.True(t, found)
+		} else {
+			assert.Equal(t, errNodeDown, err)
+		}
+	}
+
+	a.down, b.down = false, false
+	require.NoError(t, c.Add(hashes[1000:1500]...))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx, time.Hour, func(err error) { assert.NoError(t, err) })
+	for !r.Warm() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Warm: the cluster isn't needed anymore.
+	a.down, b.down = true, true
+	for _, h := range hashes[:1500] {
+		found, err := r.Has(h)
+		require.NoError(t, err)
+		assert.True(t, found)
+	}
+
+	f := NewOptimized(config)
+	for _, h := range hashes[:1500] {
+		f.Add(h)
+	}
+	assert.Equal(t, f.b, r.local.b)
+}
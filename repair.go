@@ -0,0 +1,139 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+// RepairStats describes the work done by Cluster.Repair.
+type RepairStats struct {
+	Partitions     uint64 // Partitions checked.
+	Diverged       uint64 // Partitions whose replicas had different fingerprints.
+	RepairedBlocks uint64 // Blocks updated, summed over all replicas.
+}
+
+// Repair compares the fingerprints of the replicas of each partition.
+// When they differ, it merges the contents of all replicas and writes
+// the union back to those replicas that were missing bits.
+//
+// Replicas can diverge when an Add fails on some of them. Since Repair
+// only ever sets bits, it is safe to run concurrently with Add and Has.
+// It is a no-op for clusters with a single replica per partition.
+//
+// Repair stops at the first error, returning the stats up to that point.
+func (c *Cluster) Repair() (RepairStats, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var stats RepairStats
+	for p, owners := range c.owners {
+		if len(owners) < 2 {
+			continue
+		}
+		stats.Partitions++
+
+		diverged := false
+		var first uint64
+		for i, name := range owners {
+			fp, err := c.nodes[name].Fingerprint(p)
+			if err != nil {
+				return stats, err
+			}
+			if i == 0 {
+				first = fp
+			} else if fp != first {
+				diverged = true
+			}
+		}
+		if !diverged {
+			continue
+		}
+		stats.Diverged++
+
+		n, err := c.repairPartition(p, owners)
+		stats.RepairedBlocks += n
+		if err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// repairPartition merges the replicas of partition p and returns
+// the number of blocks updated.
+func (c *Cluster) repairPartition(p int, owners []string) (uint64, error) {
+	data := make([][]byte, len(owners))
+	var merged []byte
+	for i, name := range owners {
+		d, err := c.nodes[name].Export(p)
+		if err != nil {
+			return 0, err
+		}
+		data[i] = d
+		if merged == nil {
+			merged = append([]byte(nil), d...)
+			continue
+		}
+		if len(d) != len(merged) {
+			return 0, errorf(ErrShapeMismatch, "blobloom: replicas of partition %d differ in size", p)
+		}
+		for j := range merged {
+			merged[j] |= d[j]
+		}
+	}
+
+	var repaired uint64
+	const blockBytes = BlockBits / 8
+	for i, name := range owners {
+		d := data[i]
+		n := uint64(0)
+		for j := 0; j < len(d); j += blockBytes {
+			if !bytes.Equal(d[j:j+blockBytes], merged[j:j+blockBytes]) {
+				n++
+			}
+		}
+		if n == 0 {
+			continue
+		}
+		if err := c.nodes[name].Import(p, merged); err != nil {
+			return repaired, err
+		}
+		repaired += n
+	}
+	return repaired, nil
+}
+
+// RepairEvery calls Repair at the given interval until ctx is done,
+// passing the results of each pass to report, if it is not nil.
+// It is meant to be run in its own goroutine.
+func (c *Cluster) RepairEvery(ctx context.Context, interval time.Duration, report func(RepairStats, error)) {
+	t := c.opts.Clock.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C():
+		}
+		stats, err := c.Repair()
+		if report != nil {
+			report(stats, err)
+		}
+	}
+}
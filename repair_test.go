@@ -0,0 +1,81 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterRepair(t *testing.T) {
+	t.Parallel()
+
+	c := NewCluster(Config{Capacity: 1e4, FPRate: 1e-2}, ClusterOptions{
+		Partitions: 16,
+		Replicas:   2,
+	})
+	a, b := c.NewLocalNode(), c.NewLocalNode()
+	require.NoError(t, c.Join("a", a))
+	require.NoError(t, c.Join("b", b))
+
+	hashes := randomU64(1000, 0xe9a1)
+	require.NoError(t, c.Add(hashes[:900]...))
+
+	stats, err := c.Repair()
+	require.NoError(t, err)
+	assert.Equal(t, RepairStats{Partitions: uint64(c.npart)}, stats)
+
+	// Simulate failed Adds on both replicas.
+	for _, h := range hashes[900:950] {
+		require.NoError(t, a.Add(c.partition(h), []uint64{h}))
+	}
+	for _, h := range hashes[950:] {
+		require.NoError(t, b.Add(c.partition(h), []uint64{h}))
+	}
+
+	stats, err = c.Repair()
+	require.NoError(t, err)
+	assert.NotZero(t, stats.Diverged)
+	assert.GreaterOrEqual(t, stats.RepairedBlocks, stats.Diverged)
+	t.Logf("%+v", stats)
+
+	for _, h := range hashes {
+		for _, n := range []*LocalNode{a, b} {
+			var found [1]bool
+			require.NoError(t, n.Has(c.partition(h), []uint64{h}, found[:]))
+			assert.True(t, found[0])
+		}
+	}
+
+	stats, err = c.Repair()
+	require.NoError(t, err)
+	assert.Zero(t, stats.Diverged)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	passes := make(chan RepairStats)
+	go c.RepairEvery(ctx, time.Millisecond, func(s RepairStats, err error) {
+		assert.NoError(t, err)
+		select {
+		case passes <- s:
+		case <-ctx.Done():
+		}
+	})
+	assert.Zero(t, (<-passes).Diverged)
+	cancel()
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "math/bits"
+
+// A RollingHasher computes hash values of a window sliding over a byte
+// sequence, updating the hash in constant time when the window moves.
+type RollingHasher interface {
+	// Reset starts hashing at window and returns its hash value.
+	Reset(window []byte) uint64
+	// Roll moves the window one byte forward, dropping the byte out
+	// and appending the byte in. It returns the hash of the new window.
+	Roll(out, in byte) uint64
+}
+
+// AddRolling adds the hash values of all windows of the given length in data
+// to f and returns the number of windows.
+//
+// AddRolling panics if window < 1.
+func (f *Filter) AddRolling(data []byte, window int, hasher RollingHasher) (n int) {
+	rolling(data, window, hasher, func(_ int, h uint64) {
+		f.Add(h)
+		n++
+	})
+	return n
+}
+
+// HasRolling reports, for each window of the given length in data,
+// whether f has that window's hash value. The results are appended to found,
+// which is returned. The number of results is len(data)-window+1,
+// or zero if data is shorter than window.
+//
+// HasRolling panics if window < 1.
+func (f *Filter) HasRolling(data []byte, window int, hasher RollingHasher, found []bool) []bool {
+	rolling(data, window, hasher, func(_ int, h uint64) {
+		found = append(found, f.Has(h))
+	})
+	return found
+}
+
+func rolling(data []byte, window int, hasher RollingHasher, fn func(pos int, h uint64)) {
+	if window < 1 {
+		panic("rolling hash window must be at least one byte")
+	}
+	if len(data) < window {
+		return
+	}
+
+	fn(0, hasher.Reset(data[:window]))
+	for i := window; i < len(data); i++ {
+		fn(i-window+1, hasher.Roll(data[i-window], data[i]))
+	}
+}
+
+// Buzhash is a RollingHasher that implements cyclic polynomial hashing,
+// also known as buzhash. Its output is passed through a mixing function,
+// so it can be used directly with a Filter.
+//
+// The zero value is ready to use. The hash values are the same across
+// processes and platforms, so filters built with Buzhash can be stored.
+type Buzhash struct {
+	h uint64
+	n int // Window length.
+}
+
+// Reset implements RollingHasher.
+func (b *Buzhash) Reset(window []byte) uint64 {
+	var h uint64
+	for _, c := range window {
+		h = bits.RotateLeft64(h, 1) ^ buzTable[c]
+	}
+	b.h, b.n = h, len(window)
+	return mix64(h)
+}
+
+// Roll implements RollingHasher.
+func (b *Buzhash) Roll(out, in byte) uint64 {
+	b.h = bits.RotateLeft64(b.h, 1) ^ bits.RotateLeft64(buzTable[out], b.n) ^ buzTable[in]
+	return mix64(b.h)
+}
+
+var buzTable = func() (t [256]uint64) {
+	for i := range t {
+		t[i] = mix64(uint64(i) + 0x9e3779b97f4a7c15)
+	}
+	return t
+}()
@@ -0,0 +1,52 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuzhash(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("the quick brown fox jumps over the lazy dog")
+
+	for _, window := range []int{1, 4, 63, 64, 65} {
+		var b, ref Buzhash
+		rolling(data, window, &b, func(pos int, h uint64) {
+			assert.Equal(t, ref.Reset(data[pos:pos+window]), h)
+		})
+	}
+}
+
+func TestAddRolling(t *testing.T) {
+	t.Parallel()
+
+	const window = 8
+	doc := []byte("It was the best of times, it was the worst of times")
+
+	f := New(1<<14, 4)
+	assert.Equal(t, len(doc)-window+1, f.AddRolling(doc, window, new(Buzhash)))
+
+	found := f.HasRolling([]byte("XXthe worst ofYY"), window, new(Buzhash), nil)
+	assert.Equal(t, []bool{
+		false, false, true, true, true, true, true, false, false,
+	}, found)
+
+	assert.Empty(t, f.HasRolling([]byte("short"), window, new(Buzhash), nil))
+	assert.Panics(t, func() { f.AddRolling(doc, 0, new(Buzhash)) })
+}
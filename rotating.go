@@ -0,0 +1,132 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A RotatingFilter is a Bloom filter from which keys expire, approximately.
+// It keeps a number of generations of filters. New keys are added to the
+// newest generation, lookups check all generations, and Rotate replaces
+// the oldest generation with an empty one that becomes the newest.
+//
+// When Rotate is called every interval, as by RotateEvery, a key remains
+// present for between (generations-1)*interval and generations*interval
+// after it was last added. The false positive rate is that of all
+// generations combined.
+//
+// A RotatingFilter is safe for concurrent use.
+type RotatingFilter struct {
+	mu    sync.RWMutex
+	gens  []*SyncFilter
+	cur   int // Index of newest generation in gens.
+	clock Clock
+}
+
+// NewRotating constructs a RotatingFilter with the given number of
+// generations, each of which is sized according to config.
+//
+// NewRotating panics if generations < 1 or if config.Layout is not a known
+// layout.
+func NewRotating(config Config, generations int) *RotatingFilter {
+	if generations < 1 {
+		panic("blobloom: a RotatingFilter needs at least one generation")
+	}
+	gens := make([]*SyncFilter, generations)
+	for i := range gens {
+		gens[i] = NewSyncOptimized(config)
+	}
+	return &RotatingFilter{gens: gens, clock: SystemClock}
+}
+
+// WithClock sets the Clock used by RotateEvery and returns r.
+// It must be called before RotateEvery.
+func (r *RotatingFilter) WithClock(c Clock) *RotatingFilter {
+	r.clock = c
+	return r
+}
+
+// Add inserts a key with hash value h into the newest generation.
+func (r *RotatingFilter) Add(h uint64) {
+	r.mu.RLock()
+	r.gens[r.cur].Add(h)
+	r.mu.RUnlock()
+}
+
+// Has reports whether a key with hash value h has been added
+// since the oldest generation was created. It may return a false positive.
+func (r *RotatingFilter) Has(h uint64) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Start at the newest generation, which recent keys are in.
+	for i := 0; i < len(r.gens); i++ {
+		j := r.cur - i
+		if j < 0 {
+			j += len(r.gens)
+		}
+		if r.gens[j].Has(h) {
+			return true
+		}
+	}
+	return false
+}
+
+// addIfAbsent adds h to the newest generation if no generation has it,
+// and reports whether it did so.
+func (r *RotatingFilter) addIfAbsent(h uint64) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i, g := range r.gens {
+		if i != r.cur && g.Has(h) {
+			return false
+		}
+	}
+	return !r.gens[r.cur].TestAndAdd(h)
+}
+
+// Generations returns the number of generations of r.
+func (r *RotatingFilter) Generations() int { return len(r.gens) }
+
+// Rotate drops the oldest generation and starts a new, empty one.
+func (r *RotatingFilter) Rotate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cur = (r.cur + 1) % len(r.gens)
+	b := r.gens[r.cur].b
+	for i := range b {
+		b[i] = block{}
+	}
+}
+
+// RotateEvery calls Rotate every interval until ctx is done.
+func (r *RotatingFilter) RotateEvery(ctx context.Context, interval time.Duration) {
+	t := r.clock.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C():
+			r.Rotate()
+		}
+	}
+}
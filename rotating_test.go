@@ -0,0 +1,75 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFilter(t *testing.T) {
+	t.Parallel()
+
+	const ngen = 3
+	r := NewRotating(Config{Capacity: 1000, FPRate: 1e-6}, ngen)
+	assert.Equal(t, ngen, r.Generations())
+
+	hashes := randomU64(4000, 0x707)
+	for gen := 0; gen < 4; gen++ {
+		for _, h := range hashes[1000*gen : 1000*(gen+1)] {
+			r.Add(h)
+		}
+		for old := 0; old <= gen; old++ {
+			expired := old <= gen-ngen
+			for _, h := range hashes[1000*old : 1000*(old+1)] {
+				assert.Equal(t, !expired, r.Has(h), "gen %d, added in %d", gen, old)
+			}
+		}
+		r.Rotate()
+	}
+
+	assert.Panics(t, func() { NewRotating(Config{Capacity: 1, FPRate: .1}, 0) })
+}
+
+func TestRotatingFilterConcurrent(t *testing.T) {
+	t.Parallel()
+
+	r := NewRotating(Config{Capacity: 1000, FPRate: 1e-3}, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.RotateEvery(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			for _, h := range randomU64(1e4, seed) {
+				r.Add(h)
+				r.Has(h)
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+	cancel()
+	<-done
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+// A SampledFilter is a Filter that degrades gracefully when more keys are
+// added to it than it was designed for. Once it has seen Capacity distinct
+// keys, it only admits one in every rate new keys, chosen by hash value,
+// so its false positive rate grows rate times slower than a plain Filter's.
+//
+// The price is false negatives: Has returns false for most keys first added
+// after the threshold. Since the choice is made by hash value, a key that is
+// not admitted is never admitted, and a key that was admitted is always
+// found. Has therefore answers "was this key seen, and was it sampled?".
+// Callers that use it for deduplication should expect keys beyond the
+// threshold to be reported as new repeatedly.
+//
+// A SampledFilter is not safe for concurrent use.
+type SampledFilter struct {
+	f         *Filter
+	threshold uint64
+	limit     uint64 // Admit keys whose sample hash is below this.
+	added     uint64 // Number of new keys seen.
+	skipped   uint64
+}
+
+// NewSampled constructs a SampledFilter that admits all keys until config's
+// Capacity has been reached, then one in every rate new keys.
+// It panics if rate is less than one.
+func NewSampled(config Config, rate int) *SampledFilter {
+	if rate < 1 {
+		panic("blobloom: sampling rate must be positive")
+	}
+	return &SampledFilter{
+		f:         NewOptimized(config),
+		threshold: config.Capacity,
+		limit:     ^uint64(0) / uint64(rate),
+	}
+}
+
+// Add inserts a key with hash value h into f, if f admits it.
+// It reports whether the key is now present.
+func (f *SampledFilter) Add(h uint64) bool {
+	if f.added < f.threshold {
+		if !f.f.TestAndAdd(h) {
+			f.added++
+		}
+		return true
+	}
+	if f.f.Has(h) {
+		return true
+	}
+	if mix64(h^sampleSalt) > f.limit {
+		f.skipped++
+		return false
+	}
+	f.f.Add(h)
+	f.added++
+	return true
+}
+
+// Has reports whether a key with hash value h has been admitted to f.
+// It may return a false positive.
+func (f *SampledFilter) Has(h uint64) bool { return f.f.Has(h) }
+
+// Sampling reports whether f has reached its threshold and is only
+// admitting a sample of new keys.
+func (f *SampledFilter) Sampling() bool { return f.added >= f.threshold }
+
+// Skipped returns the number of Add calls for keys that f did not admit.
+func (f *SampledFilter) Skipped() uint64 { return f.skipped }
+
+// Filter returns the underlying Filter.
+func (f *SampledFilter) Filter() *Filter { return f.f }
+
+// sampleSalt decorrelates the sampling decision from the bits
+// that the Filter uses.
+const sampleSalt = 0x5a4d91e3c2b7f061
@@ -0,0 +1,69 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampledFilter(t *testing.T) {
+	t.Parallel()
+
+	const capacity = 1000
+	config := Config{Capacity: capacity, FPRate: 1e-3}
+	s := NewSampled(config, 10)
+	plain := NewOptimized(config)
+
+	hashes := randomU64(20*capacity, 0x5a4d)
+	for _, h := range hashes[:capacity] {
+		assert.True(t, s.Add(h))
+	}
+	assert.True(t, s.Sampling())
+	assert.Zero(t, s.Skipped())
+
+	admitted := 0
+	for _, h := range hashes[capacity : 10*capacity] {
+		plain.Add(h)
+		if s.Add(h) {
+			admitted++
+			// Admission is consistent.
+			assert.True(t, s.Add(h))
+		} else {
+			assert.False(t, s.Add(h))
+		}
+	}
+	assert.InDelta(t, 900, admitted, 150)
+	assert.EqualValues(t, 2*(9*capacity-admitted), s.Skipped())
+
+	for _, h := range hashes[:capacity] {
+		assert.True(t, s.Has(h))
+	}
+
+	// The sampled filter is far less saturated.
+	fpSampled, fpPlain := 0, 0
+	for _, h := range hashes[10*capacity:] {
+		if s.Has(h) {
+			fpSampled++
+		}
+		if plain.Has(h) {
+			fpPlain++
+		}
+	}
+	assert.Less(t, 5*fpSampled, fpPlain)
+
+	assert.Panics(t, func() { NewSampled(config, 0) })
+}
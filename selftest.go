@@ -0,0 +1,118 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+)
+
+// Expected FNV-1a hash of the dump of the filter built by SelfTest.
+const selfTestDumpHash = 0x287cbc68fa92def9
+
+// SelfTest runs a quick, deterministic round trip of adding keys, looking
+// them up, estimating cardinality, set operations and dumping and loading
+// on a small temporary filter. It exercises every compiled-in implementation
+// of the bulk operations (see Implementations) and returns an error if any
+// result diverges from the expected one.
+//
+// SelfTest is meant for services that want to verify the package
+// on their hardware at startup. It takes well under a millisecond.
+func SelfTest() error {
+	const nblocks, nhashes, nkeys = 64, 7, 1000
+
+	f := New(nblocks*BlockBits, nhashes)
+	g := New(nblocks*BlockBits, nhashes)
+	s := NewSync(nblocks*BlockBits, nhashes)
+	for i := uint64(0); i < nkeys; i++ {
+		h := mix64(i)
+		f.Add(h)
+		s.Add(h)
+		g.Add(mix64(i + nkeys/2))
+	}
+
+	for i := uint64(0); i < 2*nkeys; i++ {
+		h := mix64(i)
+		has := f.Has(h)
+		switch {
+		case i < nkeys && !has:
+			return fmt.Errorf("blobloom: self-test: false negative for key %d", i)
+		case has != s.Has(h):
+			return fmt.Errorf("blobloom: self-test: SyncFilter.Has differs for key %d", i)
+		case has != f.HasConstantTime(h):
+			return fmt.Errorf("blobloom: self-test: HasConstantTime differs for key %d", i)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := Dump(&buf, f, ""); err != nil {
+		return err
+	}
+	h := fnv.New64a()
+	h.Write(buf.Bytes())
+	if sum := h.Sum64(); sum != selfTestDumpHash {
+		return fmt.Errorf("blobloom: self-test: dump hash %#x, expected %#x", sum, uint64(selfTestDumpHash))
+	}
+	l, err := NewLoader(&buf)
+	if err != nil {
+		return err
+	}
+	loaded, err := l.Load(nil)
+	if err != nil {
+		return err
+	}
+	if !loaded.Equals(f) {
+		return fmt.Errorf("blobloom: self-test: Dump/Load round trip differs")
+	}
+
+	union := append([]block(nil), f.b...)
+	unionGeneric(union, g.b)
+	intersection := append([]block(nil), f.b...)
+	intersectGeneric(intersection, g.b)
+	card := cardinality(nhashes, f.b, onescountGeneric)
+
+	for _, k := range allKernels {
+		u := append([]block(nil), f.b...)
+		k.union(u, g.b)
+		in := append([]block(nil), f.b...)
+		k.intersect(in, g.b)
+
+		switch {
+		case !blocksEqual(u, union):
+			return fmt.Errorf("blobloom: self-test: %s union differs", k.name)
+		case !blocksEqual(in, intersection):
+			return fmt.Errorf("blobloom: self-test: %s intersect differs", k.name)
+		case cardinality(nhashes, f.b, k.onescount) != card:
+			return fmt.Errorf("blobloom: self-test: %s cardinality differs", k.name)
+		case cardinality(nhashes, s.b, k.onescountAtomic) != card:
+			return fmt.Errorf("blobloom: self-test: %s atomic cardinality differs", k.name)
+		}
+	}
+
+	if card < nkeys*0.9 || card > nkeys*1.1 {
+		return fmt.Errorf("blobloom: self-test: cardinality %g for %d keys", card, nkeys)
+	}
+	return nil
+}
+
+func blocksEqual(a, b []block) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTest(t *testing.T) {
+	assert.NoError(t, SelfTest())
+
+	// Break a kernel and check that SelfTest notices.
+	saved := allKernels
+	defer func() { allKernels = saved }()
+
+	broken := genericKernels
+	broken.name = "broken"
+	broken.union = intersectGeneric
+	allKernels = append(append([]*kernels(nil), saved...), &broken)
+
+	assert.EqualError(t, SelfTest(), "blobloom: self-test: broken union differs")
+}
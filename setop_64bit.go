@@ -1,4 +1,4 @@
-// Copyright 2020-2022 the Blobloom authors
+// Copyright 2020-2026 the Blobloom authors
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,9 +12,10 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build (amd64 || arm64) && !nounsafe
+//go:build (amd64 || arm64) && !nounsafe && !blobloomdebug
 // +build amd64 arm64
 // +build !nounsafe
+// +build !blobloomdebug
 
 package blobloom
 
@@ -24,11 +25,20 @@ import (
 	"unsafe"
 )
 
+func init() {
+	registerKernels(&kernels{
+		name:            "unsafe64",
+		intersect:       intersect64,
+		union:           union64,
+		onescount:       onescount64,
+		onescountAtomic: onescountAtomic64,
+	})
+}
+
 // Block reinterpreted as array of uint64.
 type block64 [BlockBits / 64]uint64
 
-func (f *Filter) intersect(g *Filter) {
-	a, b := f.b, g.b
+func intersect64(a, b []block) {
 	for len(a) >= 2 && len(b) >= 2 {
 		p := (*block64)(unsafe.Pointer(&a[0]))
 		q := (*block64)(unsafe.Pointer(&b[0]))
@@ -72,8 +82,7 @@ func (f *Filter) intersect(g *Filter) {
 	}
 }
 
-func (f *Filter) union(g *Filter) {
-	a, b := f.b, g.b
+func union64(a, b []block) {
 	for len(a) >= 2 && len(b) >= 2 {
 		p := (*block64)(unsafe.Pointer(&a[0]))
 		q := (*block64)(unsafe.Pointer(&b[0]))
@@ -117,7 +126,7 @@ func (f *Filter) union(g *Filter) {
 	}
 }
 
-func onescount(b *block) (n int) {
+func onescount64(b *block) (n int) {
 	p := (*block64)(unsafe.Pointer(&b[0]))
 
 	n += bits.OnesCount64(p[0])
@@ -132,7 +141,7 @@ func onescount(b *block) (n int) {
 	return n
 }
 
-func onescountAtomic(b *block) (n int) {
+func onescountAtomic64(b *block) (n int) {
 	p := (*block64)(unsafe.Pointer(&b[0]))
 
 	n += bits.OnesCount64(atomic.LoadUint64(&p[0]))
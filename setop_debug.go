@@ -0,0 +1,109 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build blobloomdebug
+// +build blobloomdebug
+
+package blobloom
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The debug kernels replace the unsafe ones when the blobloomdebug build tag
+// is set. They use checked indexing and panic when the assumptions of the
+// other kernels are violated, to help diagnose crashes in those.
+func init() {
+	registerKernels(&kernels{
+		name:            "debug",
+		intersect:       intersectDebug,
+		union:           unionDebug,
+		onescount:       onescountDebug,
+		onescountAtomic: onescountAtomicDebug,
+	})
+}
+
+func intersectDebug(a, b []block) {
+	checkBinopDebug("intersect", a, b)
+	for i := range a {
+		for j := 0; j < blockWords; j++ {
+			a[i][j] &= b[i][j]
+		}
+		checkSubsetDebug("intersect", i, &a[i], &b[i])
+	}
+}
+
+func unionDebug(a, b []block) {
+	checkBinopDebug("union", a, b)
+	for i := range a {
+		for j := 0; j < blockWords; j++ {
+			a[i][j] |= b[i][j]
+		}
+		checkSubsetDebug("union", i, &b[i], &a[i])
+	}
+}
+
+func onescountDebug(b *block) int {
+	checkBlockDebug("onescount", b)
+	return onescountGeneric(b)
+}
+
+func onescountAtomicDebug(b *block) int {
+	checkBlockDebug("onescountAtomic", b)
+	return onescountAtomicGeneric(b)
+}
+
+// checkBinopDebug checks that a and b have equal lengths, are aligned
+// and either coincide or don't overlap.
+func checkBinopDebug(op string, a, b []block) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("blobloom: debug: %s on %d and %d blocks", op, len(a), len(b)))
+	}
+	if len(a) == 0 {
+		return
+	}
+	checkBlockDebug(op, &a[0])
+	checkBlockDebug(op, &b[0])
+
+	size := uintptr(len(a)) * BlockBits / 8
+	p, q := addrDebug(&a[0]), addrDebug(&b[0])
+	if p != q && p < q+size && q < p+size {
+		panic(fmt.Sprintf("blobloom: debug: %s on overlapping blocks %#x and %#x", op, p, q))
+	}
+}
+
+// checkBlockDebug checks that b is four-byte aligned, as a block of uint32
+// must be. LoadBytes uses blocks in place that have only this alignment.
+func checkBlockDebug(op string, b *block) {
+	if b == nil {
+		panic(fmt.Sprintf("blobloom: debug: %s on nil block", op))
+	}
+	if p := addrDebug(b); p%4 != 0 {
+		panic(fmt.Sprintf("blobloom: debug: %s on misaligned block at %#x", op, p))
+	}
+}
+
+// checkSubsetDebug checks that all bits set in block i of one operand
+// of op, a, are set in b, as must be the case after op. If not,
+// another goroutine modified the blocks while op was running.
+func checkSubsetDebug(op string, i int, a, b *block) {
+	for j := range a {
+		if a[j]&^b[j] != 0 {
+			panic(fmt.Sprintf("blobloom: debug: %s: block %d modified concurrently", op, i))
+		}
+	}
+}
+
+func addrDebug(b *block) uintptr { return reflect.ValueOf(b).Pointer() }
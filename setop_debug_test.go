@@ -0,0 +1,56 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build blobloomdebug
+// +build blobloomdebug
+
+package blobloom
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugKernels(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "debug", Implementation())
+
+	a, b := make([]block, 4), make([]block, 4)
+	b[3][15] = 1
+	unionDebug(a, b)
+	assert.EqualValues(t, 1, a[3][15])
+	intersectDebug(a, a) // Exact aliasing is allowed.
+
+	assert.PanicsWithValue(t, "blobloom: debug: union on 4 and 3 blocks", func() {
+		unionDebug(a, b[:3])
+	})
+	assert.Panics(t, func() { intersectDebug(a[1:], a[:3]) })
+
+	// Blocks need only be four-byte aligned, like those used by LoadBytes.
+	mem := make([]block, 2)
+	aligned4 := (*block)(unsafe.Pointer(&mem[0][1]))
+	assert.NotPanics(t, func() { onescountAtomicDebug(aligned4) })
+	misaligned := (*block)(unsafe.Pointer(uintptr(unsafe.Pointer(&mem[0][1])) + 2))
+	assert.Panics(t, func() { onescountAtomicDebug(misaligned) })
+
+	var x, y block
+	x[7], y[7] = 0b11, 0b01
+	checkSubsetDebug("union", 0, &y, &x)
+	assert.PanicsWithValue(t, "blobloom: debug: union: block 5 modified concurrently", func() {
+		checkSubsetDebug("union", 5, &x, &y)
+	})
+}
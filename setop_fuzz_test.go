@@ -0,0 +1,149 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package blobloom
+
+import (
+	"math/bits"
+	"reflect"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+// FuzzKernels checks all kernels against straightforward reference
+// implementations, on odd and zero block counts and on blocks that are
+// only four-byte aligned, which is all the Go spec guarantees for block.
+// It also checks that the kernels don't write outside their arguments.
+func FuzzKernels(f *testing.F) {
+	f.Add([]byte{}, uint8(0), false)
+	f.Add([]byte("\xff\x00\x55"), uint8(1), true)
+	f.Add(make([]byte, 64), uint8(2), false)
+	f.Add([]byte("some seed bytes"), uint8(3), true)
+	f.Add([]byte("\x01\x80\xfe\x7f\x00\xff"), uint8(13), false)
+
+	f.Fuzz(func(t *testing.T, data []byte, n uint8, misalign bool) {
+		nblocks := int(n % 17)
+
+		for _, k := range allKernels {
+			for _, op := range []struct {
+				name string
+				got  func(a, b []block)
+				ref  func(x, y uint32) uint32
+			}{
+				{"intersect", k.intersect, func(x, y uint32) uint32 { return x & y }},
+				{"union", k.union, func(x, y uint32) uint32 { return x | y }},
+			} {
+				if misalign && k.name == "debug" {
+					continue // Rejects misaligned blocks by design.
+				}
+				a := newGuarded(data, nblocks, 0, misalign)
+				b := newGuarded(data, nblocks, 7, false)
+				expect := make([]block, nblocks)
+				for i := range expect {
+					for j := range expect[i] {
+						expect[i][j] = op.ref(a.b[i][j], b.b[i][j])
+					}
+				}
+
+				op.got(a.b, b.b)
+				for i := range expect {
+					if a.b[i] != expect[i] {
+						t.Fatalf("%s/%s: wrong result in block %d of %d", k.name, op.name, i, nblocks)
+					}
+				}
+				a.check(t, k.name+"/"+op.name)
+				b.check(t, k.name+"/"+op.name)
+			}
+
+			a := newGuarded(data, nblocks, 3, misalign)
+			for i := range a.b {
+				expect := 0
+				for _, x := range a.b[i] {
+					expect += bits.OnesCount32(x)
+				}
+				// 64-bit atomic loads need eight-byte alignment on some
+				// platforms. Filters always have that; see NewWithAllocator.
+				if uintptr(unsafe.Pointer(&a.b[i]))%8 != 0 {
+					if k.name != "debug" {
+						if got := k.onescount(&a.b[i]); got != expect {
+							t.Fatalf("%s/onescount: got %d, want %d", k.name, got, expect)
+						}
+					}
+					continue
+				}
+				if got := k.onescount(&a.b[i]); got != expect {
+					t.Fatalf("%s/onescount: got %d, want %d", k.name, got, expect)
+				}
+				if got := k.onescountAtomic(&a.b[i]); got != expect {
+					t.Fatalf("%s/onescountAtomic: got %d, want %d", k.name, got, expect)
+				}
+			}
+			a.check(t, k.name+"/onescount")
+		}
+	})
+}
+
+// A guarded is a slice of blocks surrounded by guard words
+// that operations on it should leave alone.
+type guarded struct {
+	mem []uint32
+	off int
+	b   []block
+}
+
+const guardWord = 0xdeadbeef
+
+// newGuarded returns nblocks blocks filled from data, starting at offset
+// seed. If misalign is set, the blocks are not eight-byte aligned.
+func newGuarded(data []byte, nblocks, seed int, misalign bool) *guarded {
+	g := &guarded{mem: make([]uint32, blockWords*(nblocks+2)+1), off: blockWords}
+	for i := range g.mem {
+		g.mem[i] = guardWord
+	}
+	if misalign {
+		g.off++
+	}
+
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&g.b))
+	hdr.Data = uintptr(unsafe.Pointer(&g.mem[g.off]))
+	hdr.Len, hdr.Cap = nblocks, nblocks
+	runtime.KeepAlive(g.mem)
+
+	for i := range g.b {
+		for j := range g.b[i] {
+			var x uint32
+			if len(data) > 0 {
+				for k := 0; k < 4; k++ {
+					x = x<<8 | uint32(data[(seed+4*(blockWords*i+j)+k)%len(data)])
+				}
+			}
+			g.b[i][j] = x
+		}
+	}
+	return g
+}
+
+func (g *guarded) check(t *testing.T, what string) {
+	t.Helper()
+	end := g.off + blockWords*len(g.b)
+	for i, x := range g.mem {
+		if (i < g.off || i >= end) && x != guardWord {
+			t.Fatalf("%s: guard word %d overwritten", what, i)
+		}
+	}
+}
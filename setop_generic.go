@@ -1,4 +1,4 @@
-// Copyright 2020-2022 the Blobloom authors
+// Copyright 2020-2026 the Blobloom authors
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,9 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build (!amd64 && !arm64) || nounsafe
-// +build !amd64,!arm64 nounsafe
-
 package blobloom
 
 import (
@@ -22,15 +19,24 @@ import (
 	"sync/atomic"
 )
 
-func (f *Filter) intersect(g *Filter) {
-	for i := range f.b {
-		f.b[i].intersect(&g.b[i])
+// The generic kernels are portable and don't use package unsafe.
+var genericKernels = kernels{
+	name:            "generic",
+	intersect:       intersectGeneric,
+	union:           unionGeneric,
+	onescount:       onescountGeneric,
+	onescountAtomic: onescountAtomicGeneric,
+}
+
+func intersectGeneric(a, b []block) {
+	for i := range a {
+		a[i].intersect(&b[i])
 	}
 }
 
-func (f *Filter) union(g *Filter) {
-	for i := range f.b {
-		f.b[i].union(&g.b[i])
+func unionGeneric(a, b []block) {
+	for i := range a {
+		a[i].union(&b[i])
 	}
 }
 
@@ -72,7 +78,7 @@ func (b *block) union(c *block) {
 	b[15] |= c[15]
 }
 
-func onescount(b *block) (n int) {
+func onescountGeneric(b *block) (n int) {
 	n += bits.OnesCount32(b[0])
 	n += bits.OnesCount32(b[1])
 	n += bits.OnesCount32(b[2])
@@ -93,7 +99,7 @@ func onescount(b *block) (n int) {
 	return n
 }
 
-func onescountAtomic(b *block) (n int) {
+func onescountAtomicGeneric(b *block) (n int) {
 	n += bits.OnesCount32(atomic.LoadUint32(&b[0]))
 	n += bits.OnesCount32(atomic.LoadUint32(&b[1]))
 	n += bits.OnesCount32(atomic.LoadUint32(&b[2]))
@@ -0,0 +1,67 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"io"
+)
+
+// ErrInvalidSignature is returned by VerifyDump for signatures that
+// do not match. It wraps ErrChecksum.
+var ErrInvalidSignature error = &kindError{"blobloom: invalid dump signature", ErrChecksum}
+
+// Prefix of signed messages, to prevent signatures from being valid
+// for other purposes.
+const signContext = "blobloom signed dump v1\x00"
+
+// SignDump reads a dump, as written by Dump, from r and returns an ed25519
+// signature of it.
+//
+// The signature covers the SHA-256 hash of the entire dump, including
+// the header, so the filter's parameters and comment are authenticated
+// along with its contents.
+func SignDump(key ed25519.PrivateKey, r io.Reader) ([]byte, error) {
+	msg, err := signedMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(key, msg), nil
+}
+
+// VerifyDump reads a dump from r and checks sig, a signature produced by
+// SignDump. It returns ErrInvalidSignature if the signature does not match.
+//
+// To verify a dump before loading it, read it into memory, verify it,
+// then pass the same bytes to NewLoader.
+func VerifyDump(key ed25519.PublicKey, r io.Reader, sig []byte) error {
+	msg, err := signedMessage(r)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, msg, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func signedMessage(r io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum([]byte(signContext)), nil
+}
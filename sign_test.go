@@ -0,0 +1,59 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignDump(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.New(rand.NewSource(0x519)))
+	require.NoError(t, err)
+
+	f := New(1<<13, 4)
+	for _, h := range randomU64(100, 0x51) {
+		f.Add(h)
+	}
+	var buf bytes.Buffer
+	_, err = Dump(&buf, f, "blocklist")
+	require.NoError(t, err)
+	dump := buf.Bytes()
+
+	sig, err := SignDump(priv, bytes.NewReader(dump))
+	require.NoError(t, err)
+	assert.NoError(t, VerifyDump(pub, bytes.NewReader(dump), sig))
+
+	// Changing the comment or the contents invalidates the signature.
+	for _, i := range []int{20, len(dump) - 1} {
+		mutated := append([]byte(nil), dump...)
+		mutated[i] ^= 1
+		err = VerifyDump(pub, bytes.NewReader(mutated), sig)
+		assert.Equal(t, ErrInvalidSignature, err)
+	}
+	err = VerifyDump(pub, bytes.NewReader(dump[:len(dump)-1]), sig)
+	assert.Equal(t, ErrInvalidSignature, err)
+
+	otherPub, _, _ := ed25519.GenerateKey(rand.New(rand.NewSource(1)))
+	err = VerifyDump(otherPub, bytes.NewReader(dump), sig)
+	assert.Equal(t, ErrInvalidSignature, err)
+}
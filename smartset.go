@@ -0,0 +1,103 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "io"
+
+// A SmartSet stores hash values exactly while there are few of them,
+// then transparently switches to a Bloom filter when their number exceeds
+// a threshold. While in the exact state, Has never returns false positives.
+//
+// A SmartSet is not safe for concurrent use.
+type SmartSet struct {
+	config    Config
+	threshold int
+
+	set map[uint64]struct{} // Nil after switching to f.
+	f   *Filter
+}
+
+// NewSmartSet returns an empty SmartSet that switches to a Bloom filter,
+// constructed by NewOptimized(config), after threshold distinct hash values
+// have been added.
+//
+// If threshold is zero or negative, the SmartSet switches when an exact set
+// would take about as much memory as the Bloom filter.
+func NewSmartSet(config Config, threshold int) *SmartSet {
+	if threshold <= 0 {
+		nbits, _ := Optimize(config)
+		// At least eight bytes per key, plus map overhead.
+		threshold = int(nbits / (2 * 64))
+	}
+	return &SmartSet{
+		config:    config,
+		threshold: threshold,
+		set:       make(map[uint64]struct{}),
+	}
+}
+
+// Add inserts a key with hash value h into s.
+func (s *SmartSet) Add(h uint64) {
+	if s.f != nil {
+		s.f.Add(h)
+		return
+	}
+
+	s.set[h] = struct{}{}
+	if len(s.set) > s.threshold {
+		s.f = s.filter()
+		s.set = nil
+	}
+}
+
+// Dump writes s to w in the format produced by the package-level Dump
+// function, regardless of whether s is in the exact state.
+func (s *SmartSet) Dump(w io.Writer, comment string) (int64, error) {
+	return Dump(w, s.filter(), comment)
+}
+
+// Exact reports whether s is still storing hash values exactly.
+func (s *SmartSet) Exact() bool { return s.f == nil }
+
+// filter returns s's Bloom filter, constructing a new one if s is exact.
+func (s *SmartSet) filter() *Filter {
+	if s.f != nil {
+		return s.f
+	}
+	f := NewOptimized(s.config)
+	for h := range s.set {
+		f.Add(h)
+	}
+	return f
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive if s is no longer exact.
+func (s *SmartSet) Has(h uint64) bool {
+	if s.f != nil {
+		return s.f.Has(h)
+	}
+	_, ok := s.set[h]
+	return ok
+}
+
+// Len returns the number of distinct hash values in s while it is exact.
+// Afterwards, it returns an estimate from the Bloom filter's Cardinality.
+func (s *SmartSet) Len() float64 {
+	if s.f != nil {
+		return s.f.Cardinality()
+	}
+	return float64(len(s.set))
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmartSet(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Capacity: 1e4, FPRate: 1e-3}
+	hashes := randomU64(2000, 0x5a27)
+
+	s := NewSmartSet(cfg, 1000)
+	for _, h := range hashes[:1000] {
+		s.Add(h)
+	}
+	assert.True(t, s.Exact())
+	assert.EqualValues(t, 1000, s.Len())
+	for _, h := range hashes[1000:] {
+		assert.False(t, s.Has(h))
+	}
+
+	// The dump of an exact SmartSet is a regular Filter dump.
+	var buf bytes.Buffer
+	_, err := s.Dump(&buf, "smart")
+	require.NoError(t, err)
+	l, err := NewLoader(&buf)
+	require.NoError(t, err)
+	f, err := l.Load(nil)
+	require.NoError(t, err)
+
+	s.Add(hashes[1000])
+	assert.False(t, s.Exact())
+	assert.InDelta(t, 1001, s.Len(), 20)
+
+	for _, h := range hashes[:1000] {
+		assert.True(t, f.Has(h))
+		assert.True(t, s.Has(h))
+	}
+	f.Add(hashes[1000])
+	assert.True(t, f.Equals(s.f))
+
+	auto := NewSmartSet(cfg, 0)
+	assert.Equal(t, 1332, auto.threshold)
+}
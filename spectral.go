@@ -0,0 +1,157 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "io"
+
+// A SpectralFilter is a spectral Bloom filter (Cohen and Matias, 2003):
+// a Bloom filter with a counter in place of each bit, so that it can
+// estimate how many times a key has been added.
+//
+// A SpectralFilter maps hash values to counters the same way a Filter with
+// the same parameters maps them to bits, so Has returns the same answers
+// as that Filter would. Counters take eight bits each and saturate at 255,
+// so a SpectralFilter takes eight times as much memory as a Filter.
+type SpectralFilter struct {
+	b      []counterBlock
+	k      int
+	premix bool
+	layout Layout
+}
+
+// A counterBlock holds the counters corresponding to a block.
+type counterBlock [BlockBits]uint8
+
+// MaxCount is the maximum count reported by a SpectralFilter.
+const MaxCount = 255
+
+// NewSpectral constructs a spectral Bloom filter with given numbers of
+// counters and hash functions. These are adjusted as New adjusts its
+// numbers of bits and hash functions.
+func NewSpectral(ncounters uint64, nhashes int) *SpectralFilter {
+	ncounters, nhashes = fixBitsAndHashes(ncounters, nhashes)
+
+	return &SpectralFilter{
+		b: make([]counterBlock, ncounters/BlockBits),
+		k: nhashes,
+	}
+}
+
+// NewSpectralOptimized is like NewOptimized, but for a SpectralFilter.
+// Capacity and FPRate in config determine the rate at which
+// Count returns a non-zero count for keys that were never added.
+func NewSpectralOptimized(config Config) *SpectralFilter {
+	config.Layout.check()
+	f := NewSpectral(Optimize(config))
+	f.premix = config.Premix
+	f.layout = config.Layout
+	return f
+}
+
+// Add increments the count of a key with hash value h.
+//
+// Add uses the minimal increase rule: it only increments the key's
+// counters that are at the key's current minimum count. This reduces
+// the overestimation caused by keys sharing counters.
+func (f *SpectralFilter) Add(h uint64) {
+	b, h1, h2 := f.locate(h)
+	min := f.count(b, h1, h2)
+	if min == MaxCount {
+		return
+	}
+
+	for i := 1; i < f.k; i++ {
+		h1, h2 = doublehash(h1, h2, i)
+		if c := &b[h1%BlockBits]; *c == min {
+			*c++
+		}
+	}
+}
+
+// Count estimates the number of times a key with hash value h has been
+// added, up to MaxCount. The estimate is never too low, but may be too
+// high, because of hash collisions.
+func (f *SpectralFilter) Count(h uint64) int {
+	b, h1, h2 := f.locate(h)
+	return int(f.count(b, h1, h2))
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+func (f *SpectralFilter) Has(h uint64) bool { return f.Count(h) > 0 }
+
+// NumCounters returns the number of counters of f.
+func (f *SpectralFilter) NumCounters() uint64 {
+	return BlockBits * uint64(len(f.b))
+}
+
+func (f *SpectralFilter) locate(h uint64) (b *counterBlock, h1, h2 uint32) {
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	return &f.b[reducerange(blk, uint64(len(f.b)))], h1, h2
+}
+
+// count returns the minimum of the counters for h1, h2 in b.
+func (f *SpectralFilter) count(b *counterBlock, h1, h2 uint32) uint8 {
+	min := uint8(MaxCount)
+	for i := 1; i < f.k; i++ {
+		h1, h2 = doublehash(h1, h2, i)
+		if c := b[h1%BlockBits]; c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+// DumpSpectral writes f to w, with an optional comment string, in the
+// format that a Loader accepts. It returns the number of bytes written to w.
+func DumpSpectral(w io.Writer, f *SpectralFilter, comment string) (int64, error) {
+	if err := checkDumpComment(comment); err != nil {
+		return 0, err
+	}
+
+	hdr := appendHeader(nil, len(f.b), f.k, f.premix, f.layout, comment)
+	hdr[10] = byte(KindSpectral)
+	k, err := w.Write(hdr)
+	n := int64(k)
+
+	for i := 0; i < len(f.b) && err == nil; i++ {
+		k, err = w.Write(f.b[i][:])
+		n += int64(k)
+	}
+	return n, err
+}
+
+// LoadSpectral reads a SpectralFilter from the Loader.
+// It returns an error if the dump holds a different Kind of sketch.
+func (l *Loader) LoadSpectral() (*SpectralFilter, error) {
+	if err := l.checkKind(KindSpectral); err != nil {
+		return nil, err
+	}
+	if BlockBits*l.nblocks > MaxBits {
+		return nil, errorf(ErrTooLarge, "blobloom: %d blocks is too large", l.nblocks)
+	}
+
+	f := NewSpectral(BlockBits*l.nblocks, l.nhashes)
+	f.premix, f.layout = l.premix, l.layout
+	for i := range f.b {
+		if err := l.fill(f.b[i][:]); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
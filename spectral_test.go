@@ -0,0 +1,65 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpectralFilter(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1e4, FPRate: 1e-3, Premix: true, Layout: LayoutV1}
+	s := NewSpectralOptimized(config)
+	f := NewOptimized(config)
+	assert.Equal(t, f.NumBits(), s.NumCounters())
+
+	hashes := randomU64(2e4, 0x5bec)
+	keys, others := hashes[:1e4], hashes[1e4:]
+	for i, h := range keys {
+		// Key i is added 1 + i%5 times.
+		for j := 0; j <= i%5; j++ {
+			s.Add(h)
+		}
+		f.Add(h)
+	}
+
+	exact := 0
+	for i, h := range keys {
+		c := s.Count(h)
+		assert.GreaterOrEqual(t, c, 1+i%5)
+		if c == 1+i%5 {
+			exact++
+		}
+	}
+	assert.Greater(t, exact, 98*len(keys)/100)
+
+	for _, h := range others {
+		assert.Equal(t, f.Has(h), s.Has(h))
+	}
+}
+
+func TestSpectralFilterSaturate(t *testing.T) {
+	t.Parallel()
+
+	s := NewSpectral(BlockBits, 4)
+	for i := 0; i < 2*MaxCount; i++ {
+		s.Add(1)
+	}
+	assert.Equal(t, MaxCount, s.Count(1))
+	assert.Equal(t, 0, s.Count(2))
+}
@@ -0,0 +1,319 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// A StaticFilter is an immutable approximate set membership structure,
+// built once from a complete set of hash values. It is a binary fuse filter
+// (Graf and Lemire, https://arxiv.org/abs/2201.01174), which uses less
+// memory than a Bloom filter with the same false positive rate, but
+// does not support adding keys after construction.
+type StaticFilter struct {
+	seed               uint64
+	segmentLength      uint32
+	segmentLengthMask  uint32
+	segmentCountLength uint32
+
+	// Fingerprints. Exactly one of these is non-nil.
+	fp8  []uint8
+	fp16 []uint16
+}
+
+// BuildStatic builds a StaticFilter containing the given hash values,
+// which may contain duplicates.
+//
+// If bitsPerKey is at least 18, BuildStatic stores 16-bit fingerprints,
+// for a false positive rate of 2⁻¹⁶. Otherwise, it stores 8-bit fingerprints,
+// for a false positive rate of 2⁻⁸. For large sets, the filter uses about
+// 1.13 times the fingerprint size per key; small sets need more.
+//
+// BuildStatic returns an error if it fails to construct a filter, which is
+// very unlikely. It panics if there are 2³² or more hashes.
+func BuildStatic(hashes []uint64, bitsPerKey int) (*StaticFilter, error) {
+	if uint64(len(hashes)) > math.MaxUint32 {
+		panic("blobloom: too many hashes for static filter")
+	}
+	size := uint32(len(hashes))
+
+	f := &StaticFilter{}
+	capacity := f.init(size)
+	if bitsPerKey >= 18 {
+		f.fp16 = make([]uint16, capacity)
+	} else {
+		f.fp8 = make([]uint8, capacity)
+	}
+
+	stack, found, err := f.peel(hashes, capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Assign fingerprints in reverse peeling order, so that each key's
+	// fingerprint is determined by the one slot that was free when it
+	// was peeled.
+	var h012 [5]uint32
+	for i := len(stack) - 1; i >= 0; i-- {
+		hash := stack[i]
+		h012[0], h012[1], h012[2] = f.positions(hash)
+		h012[3], h012[4] = h012[0], h012[1]
+		j := found[i]
+
+		x := uint16(fingerprint(hash)) ^ f.fingerprint(h012[j+1]) ^ f.fingerprint(h012[j+2])
+		if f.fp8 != nil {
+			f.fp8[h012[j]] = uint8(x)
+		} else {
+			f.fp16[h012[j]] = x
+		}
+	}
+	return f, nil
+}
+
+// init sets the parameters of f for the given number of keys
+// and returns the number of fingerprints.
+func (f *StaticFilter) init(size uint32) (capacity uint32) {
+	const arity = 3
+
+	f.segmentLength = 4
+	if size > 0 {
+		f.segmentLength = 1 << uint(math.Floor(math.Log(float64(size))/math.Log(3.33)+2.25))
+	}
+	if f.segmentLength > 1<<18 {
+		f.segmentLength = 1 << 18
+	}
+	f.segmentLengthMask = f.segmentLength - 1
+
+	if size > 1 {
+		sizeFactor := math.Max(1.125, 0.875+0.25*math.Log(1e6)/math.Log(float64(size)))
+		capacity = uint32(math.Round(float64(size) * sizeFactor))
+	}
+	segmentCount := (capacity + f.segmentLength - 1) / f.segmentLength
+	if segmentCount <= arity-1 {
+		segmentCount = 1
+	} else {
+		segmentCount -= arity - 1
+	}
+	f.segmentCountLength = segmentCount * f.segmentLength
+	return (segmentCount + arity - 1) * f.segmentLength
+}
+
+var errStaticBuild = errors.New("blobloom: failed to build static filter")
+
+// peel finds a seed for which the hypergraph of hashes can be peeled.
+// It returns the mixed hashes in peeling order and, for each, the index
+// of the position that was free when it was peeled.
+func (f *StaticFilter) peel(hashes []uint64, capacity uint32) (stack []uint64, found []uint8, err error) {
+	size := len(hashes)
+	var (
+		alone        = make([]uint32, capacity)
+		t2count      = make([]uint8, capacity)
+		t2hash       = make([]uint64, capacity)
+		reverseOrder = make([]uint64, size+1)
+		reverseH     = make([]uint8, size)
+	)
+
+	segmentCount := f.segmentCountLength / f.segmentLength
+	blockBits := uint(1)
+	for 1<<blockBits < segmentCount {
+		blockBits++
+	}
+	startPos := make([]uint64, 1<<blockBits)
+
+	rng := uint64(1)
+	for iter := 0; iter < 100; iter++ {
+		if iter == 10 {
+			// Duplicates are only detected when they don't share all
+			// their positions with other keys. Remove the rest now.
+			hashes = dedup(hashes)
+			size = len(hashes)
+		}
+
+		f.seed = splitmix64(&rng)
+		for i := range t2count {
+			t2count[i], t2hash[i] = 0, 0
+		}
+		for i := range reverseOrder {
+			reverseOrder[i] = 0
+		}
+		reverseOrder[size] = 1
+
+		// Sort the hashes approximately by segment, for locality.
+		for i := range startPos {
+			startPos[i] = uint64(i) * uint64(size) >> blockBits
+		}
+		for _, h := range hashes {
+			hash := mix64(h + f.seed)
+			seg := hash >> (64 - blockBits)
+			for reverseOrder[startPos[seg]] != 0 {
+				seg = (seg + 1) & (1<<blockBits - 1)
+			}
+			reverseOrder[startPos[seg]] = hash
+			startPos[seg]++
+		}
+
+		failed, duplicates := false, 0
+		for _, hash := range reverseOrder[:size] {
+			i0, i1, i2 := f.positions(hash)
+			t2count[i0] += 4
+			t2hash[i0] ^= hash
+			t2count[i1] += 4
+			t2count[i1] ^= 1
+			t2hash[i1] ^= hash
+			t2count[i2] += 4
+			t2count[i2] ^= 2
+			t2hash[i2] ^= hash
+
+			// A duplicate cancels out the hash of its first occurrence.
+			if t2hash[i0]&t2hash[i1]&t2hash[i2] == 0 &&
+				(t2hash[i0] == 0 && t2count[i0] == 8 ||
+					t2hash[i1] == 0 && t2count[i1] == 8 ||
+					t2hash[i2] == 0 && t2count[i2] == 8) {
+				duplicates++
+				t2count[i0] -= 4
+				t2hash[i0] ^= hash
+				t2count[i1] -= 4
+				t2count[i1] ^= 1
+				t2hash[i1] ^= hash
+				t2count[i2] -= 4
+				t2count[i2] ^= 2
+				t2hash[i2] ^= hash
+			}
+			failed = failed || t2count[i0] < 4 || t2count[i1] < 4 || t2count[i2] < 4
+		}
+		if failed {
+			continue
+		}
+
+		// Peel positions that belong to a single key.
+		qsize := 0
+		for i := uint32(0); i < capacity; i++ {
+			alone[qsize] = i
+			if t2count[i]>>2 == 1 {
+				qsize++
+			}
+		}
+		nstack := 0
+		var h012 [5]uint32
+		for qsize > 0 {
+			qsize--
+			index := alone[qsize]
+			if t2count[index]>>2 != 1 {
+				continue
+			}
+			hash := t2hash[index]
+			j := t2count[index] & 3
+			reverseH[nstack] = j
+			reverseOrder[nstack] = hash
+			nstack++
+
+			i0, i1, i2 := f.positions(hash)
+			h012[1], h012[2], h012[3] = i1, i2, i0
+			h012[4] = h012[1]
+
+			for _, d := range [2]uint8{1, 2} {
+				other := h012[j+d]
+				alone[qsize] = other
+				if t2count[other]>>2 == 2 {
+					qsize++
+				}
+				t2count[other] -= 4
+				t2count[other] ^= mod3(j + d)
+				t2hash[other] ^= hash
+			}
+		}
+		if nstack+duplicates == size {
+			return reverseOrder[:nstack], reverseH[:nstack], nil
+		}
+	}
+	return nil, nil, errStaticBuild
+}
+
+// dedup returns the distinct values in hashes, in sorted order.
+func dedup(hashes []uint64) []uint64 {
+	u := append([]uint64(nil), hashes...)
+	sort.Slice(u, func(i, j int) bool { return u[i] < u[j] })
+
+	n := 0
+	for i, h := range u {
+		if i == 0 || h != u[n-1] {
+			u[n] = h
+			n++
+		}
+	}
+	return u[:n]
+}
+
+func mod3(x uint8) uint8 {
+	if x > 2 {
+		x -= 3
+	}
+	return x
+}
+
+func splitmix64(seed *uint64) uint64 {
+	*seed += 0x9e3779b97f4a7c15
+	return mix64(*seed)
+}
+
+// positions returns the three fingerprint positions of a mixed hash.
+func (f *StaticFilter) positions(hash uint64) (i0, i1, i2 uint32) {
+	hi, _ := bits.Mul64(hash, uint64(f.segmentCountLength))
+	i0 = uint32(hi)
+	i1 = i0 + f.segmentLength
+	i2 = i1 + f.segmentLength
+	i1 ^= uint32(hash>>18) & f.segmentLengthMask
+	i2 ^= uint32(hash) & f.segmentLengthMask
+	return i0, i1, i2
+}
+
+func fingerprint(hash uint64) uint64 { return hash ^ hash>>32 }
+
+func (f *StaticFilter) fingerprint(i uint32) uint16 {
+	if f.fp8 != nil {
+		return uint16(f.fp8[i])
+	}
+	return f.fp16[i]
+}
+
+// Has reports whether h was among the hashes f was built from.
+// It may return a false positive.
+func (f *StaticFilter) Has(h uint64) bool {
+	hash := mix64(h + f.seed)
+	i0, i1, i2 := f.positions(hash)
+	if f.fp8 != nil {
+		x := uint8(fingerprint(hash)) ^ f.fp8[i0] ^ f.fp8[i1] ^ f.fp8[i2]
+		return x == 0
+	}
+	x := uint16(fingerprint(hash)) ^ f.fp16[i0] ^ f.fp16[i1] ^ f.fp16[i2]
+	return x == 0
+}
+
+// FPRate returns the false positive rate of f.
+func (f *StaticFilter) FPRate() float64 {
+	if f.fp8 != nil {
+		return 1. / (1 << 8)
+	}
+	return 1. / (1 << 16)
+}
+
+// NumBits returns the size of f in bits.
+func (f *StaticFilter) NumBits() uint64 {
+	return 8*uint64(len(f.fp8)) + 16*uint64(len(f.fp16))
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticFilter(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(4e5, 0x57a71c)
+	keys, others := hashes[:2e5], hashes[2e5:]
+
+	for _, bitsPerKey := range []int{9, 18} {
+		f, err := BuildStatic(keys, bitsPerKey)
+		require.NoError(t, err)
+
+		for _, h := range keys {
+			assert.True(t, f.Has(h))
+		}
+		fp := 0
+		for _, h := range others {
+			if f.Has(h) {
+				fp++
+			}
+		}
+		fpr := float64(fp) / float64(len(others))
+		perKey := float64(f.NumBits()) / float64(len(keys))
+		t.Logf("bitsPerKey = %d: FPR = %g, %.2f bits per key", bitsPerKey, fpr, perKey)
+
+		assert.Less(t, fpr, 1.5*f.FPRate())
+		assert.Less(t, perKey, 1.2*float64(bitsPerKey/9*8))
+	}
+}
+
+func TestStaticFilterSmall(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 2, 3, 10, 100} {
+		keys := randomU64(n, int64(n))
+		// Duplicates are allowed.
+		keys = append(keys, keys...)
+
+		f, err := BuildStatic(keys, 8)
+		require.NoError(t, err, n)
+		for _, h := range keys {
+			assert.True(t, f.Has(h), n)
+		}
+	}
+}
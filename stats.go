@@ -0,0 +1,96 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+// BlockStats describes how evenly keys are spread over the blocks
+// of a filter. A good hash function fills all blocks about equally;
+// skew shows up as a wide histogram and a high MaxOnes, and blocks
+// close to saturation dominate the false positive rate.
+type BlockStats struct {
+	Blocks   uint64  // Number of blocks.
+	MinOnes  int     // Minimum number of set bits in a block.
+	MaxOnes  int     // Maximum number of set bits in a block.
+	MeanOnes float64 // Mean number of set bits per block.
+
+	// Histogram[i] is the number of blocks with i bits set,
+	// for 0 <= i <= BlockBits.
+	Histogram []uint64
+}
+
+// Full returns the number of blocks that have all their bits set.
+func (s *BlockStats) Full() uint64 { return s.Histogram[BlockBits] }
+
+// Stats returns statistics about the occupancy of f's blocks.
+func (f *Filter) Stats() BlockStats {
+	return blockStats(f.b, kernel().onescount)
+}
+
+// Stats returns statistics about the occupancy of f's blocks.
+//
+// If other goroutines are concurrently adding keys, the statistics may
+// reflect some of their additions, but not others.
+func (f *SyncFilter) Stats() BlockStats {
+	return blockStats(f.b, kernel().onescountAtomic)
+}
+
+// FillRatio returns the fraction of f's bits that are set.
+// It is not to be confused with Fill, which sets all bits.
+func (f *Filter) FillRatio() float64 {
+	return fillRatio(f.b, kernel().onescount)
+}
+
+// FillRatio returns the fraction of f's bits that are set.
+// It is not to be confused with Fill, which sets all bits.
+func (f *SyncFilter) FillRatio() float64 {
+	return fillRatio(f.b, kernel().onescountAtomic)
+}
+
+func fillRatio(b []block, onescount func(*block) int) float64 {
+	if len(b) == 0 {
+		return 0
+	}
+	var ones uint64
+	for i := range b {
+		ones += uint64(onescount(&b[i]))
+	}
+	return float64(ones) / float64(BlockBits*uint64(len(b)))
+}
+
+func blockStats(b []block, onescount func(*block) int) BlockStats {
+	s := BlockStats{
+		Blocks:    uint64(len(b)),
+		MinOnes:   BlockBits,
+		Histogram: make([]uint64, BlockBits+1),
+	}
+
+	var total uint64
+	for i := range b {
+		ones := onescount(&b[i])
+		s.Histogram[ones]++
+		total += uint64(ones)
+		if ones < s.MinOnes {
+			s.MinOnes = ones
+		}
+		if ones > s.MaxOnes {
+			s.MaxOnes = ones
+		}
+	}
+	if len(b) == 0 {
+		s.MinOnes = 0
+	} else {
+		s.MeanOnes = float64(total) / float64(len(b))
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	f := New(4*BlockBits, 3)
+	s := f.Stats()
+	assert.EqualValues(t, 4, s.Blocks)
+	assert.Equal(t, 0, s.MinOnes)
+	assert.Equal(t, 0, s.MaxOnes)
+	assert.Equal(t, 0.0, s.MeanOnes)
+	assert.EqualValues(t, 4, s.Histogram[0])
+	assert.Zero(t, s.Full())
+
+	f.b[1] = block{0xff, 1}
+	f.b[2][15] = 1 << 31
+	for i := range f.b[3] {
+		f.b[3][i] = ^uint32(0)
+	}
+	s = f.Stats()
+	assert.Equal(t, 0, s.MinOnes)
+	assert.Equal(t, BlockBits, s.MaxOnes)
+	assert.Equal(t, float64(9+1+BlockBits)/4, s.MeanOnes)
+	assert.EqualValues(t, 1, s.Histogram[0])
+	assert.EqualValues(t, 1, s.Histogram[1])
+	assert.EqualValues(t, 1, s.Histogram[9])
+	assert.EqualValues(t, 1, s.Full())
+
+	sf := NewSync(4*BlockBits, 3)
+	copy(sf.b, f.b)
+	assert.Equal(t, s, sf.Stats())
+
+	ratio := float64(9+1+BlockBits) / (4 * BlockBits)
+	assert.Equal(t, ratio, f.FillRatio())
+	assert.Equal(t, ratio, sf.FillRatio())
+	assert.Zero(t, New(BlockBits, 3).FillRatio())
+}
+
+func TestStatsRandom(t *testing.T) {
+	t.Parallel()
+
+	f := NewOptimized(Config{Capacity: 1e4, FPRate: 1e-3})
+	for _, h := range randomU64(1e4, 0x57a7) {
+		f.Add(h)
+	}
+	s := f.Stats()
+
+	var n uint64
+	for _, c := range s.Histogram {
+		n += c
+	}
+	assert.Equal(t, s.Blocks, n)
+	assert.Less(t, s.MinOnes, int(s.MeanOnes))
+	assert.Greater(t, s.MaxOnes, int(s.MeanOnes))
+	assert.Zero(t, s.Full())
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"runtime"
+	"sync"
+)
+
+// A StripedFilter is a Bloom filter that can be accessed and updated by
+// multiple goroutines concurrently, like a SyncFilter, but protects its
+// blocks with a set of locks instead of atomic operations.
+//
+// Each lock guards every stripes'th block. Adds take a lock once per key
+// instead of retrying compare-and-swap operations for each bit, which
+// gives more predictable latency when many goroutines add the same keys.
+// Lookups take a read lock and are slower than a SyncFilter's.
+//
+// A StripedFilter maps hash values to bits in exactly the same way as a
+// Filter with the same parameters.
+type StripedFilter struct {
+	b      []block
+	k      int
+	premix bool
+	layout Layout
+	locks  []stripeLock
+}
+
+// A stripeLock is a lock padded to a cache line,
+// so that goroutines using neighboring locks don't contend.
+type stripeLock struct {
+	sync.RWMutex
+	_ [40]byte // sync.RWMutex takes 24 bytes.
+}
+
+// NewStriped constructs a StripedFilter with the given numbers of bits and
+// hash functions, which are adjusted as New adjusts them, and the given
+// number of locks. If stripes is not positive, it defaults to four times
+// GOMAXPROCS. There are never more locks than blocks.
+func NewStriped(nbits uint64, nhashes, stripes int) *StripedFilter {
+	nbits, nhashes = fixBitsAndHashes(nbits, nhashes)
+	nblocks := nbits / BlockBits
+
+	if stripes <= 0 {
+		stripes = 4 * runtime.GOMAXPROCS(0)
+	}
+	if uint64(stripes) > nblocks {
+		stripes = int(nblocks)
+	}
+
+	return &StripedFilter{
+		b:     makeBlocks(nblocks),
+		k:     nhashes,
+		locks: make([]stripeLock, stripes),
+	}
+}
+
+// NewStripedOptimized is like NewSyncOptimized, but for a StripedFilter.
+func NewStripedOptimized(config Config, stripes int) *StripedFilter {
+	config.Layout.check()
+	nbits, nhashes := Optimize(config)
+	f := NewStriped(nbits, nhashes, stripes)
+	f.premix = config.Premix
+	f.layout = config.Layout
+	return f
+}
+
+// Add inserts a key with hash value h into f.
+func (f *StripedFilter) Add(h uint64) {
+	b, l, h1, h2 := f.locate(h)
+	l.Lock()
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		b.setbit(bit)
+		return true
+	})
+	l.Unlock()
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+func (f *StripedFilter) Has(h uint64) bool {
+	b, l, h1, h2 := f.locate(h)
+	l.RLock()
+	defer l.RUnlock()
+	return probe(h1, h2, f.k, b.getbit)
+}
+
+// TestAndAdd inserts a key with hash value h into f and reports whether
+// it was already present. Unlike SyncFilter.TestAndAdd, exactly one of
+// several goroutines concurrently calling it for a new key gets false.
+func (f *StripedFilter) TestAndAdd(h uint64) bool {
+	b, l, h1, h2 := f.locate(h)
+	l.Lock()
+	defer l.Unlock()
+
+	present := true
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		if !b.testAndSet(bit) {
+			present = false
+		}
+		return true
+	})
+	return present
+}
+
+// Freeze returns a copy of f as a Filter. It holds all locks while copying,
+// so the copy reflects f at a single point in time.
+func (f *StripedFilter) Freeze() *Filter {
+	for i := range f.locks {
+		f.locks[i].RLock()
+	}
+	b := makeBlocks(uint64(len(f.b)))
+	copy(b, f.b)
+	for i := range f.locks {
+		f.locks[i].RUnlock()
+	}
+	return &Filter{b: b, k: f.k, premix: f.premix, layout: f.layout}
+}
+
+// NumBits returns the number of bits of f.
+func (f *StripedFilter) NumBits() uint64 {
+	return BlockBits * uint64(len(f.b))
+}
+
+// Stripes returns the number of locks of f.
+func (f *StripedFilter) Stripes() int { return len(f.locks) }
+
+func (f *StripedFilter) locate(h uint64) (b *block, l *stripeLock, h1, h2 uint32) {
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	i := reducerange(blk, uint64(len(f.b)))
+	return &f.b[i], &f.locks[i%uint64(len(f.locks))], h1, h2
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripedFilter(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Capacity: 1e4, FPRate: 1e-4, Premix: true, Layout: LayoutV1}
+	f := NewStripedOptimized(cfg, 0)
+	ref := NewOptimized(cfg)
+	assert.Greater(t, f.Stripes(), 0)
+	assert.Equal(t, ref.NumBits(), f.NumBits())
+
+	const nworkers = 4
+	var (
+		hashes = randomU64(1e4, 0x57e1)
+		fresh  int32
+		wg     sync.WaitGroup
+	)
+	for w := 0; w < nworkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i, h := range hashes {
+				switch {
+				case i%2 == 0:
+					f.Add(h)
+				case !f.TestAndAdd(h):
+					atomic.AddInt32(&fresh, 1)
+				}
+				f.Has(h)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for i, h := range hashes {
+		ref.Add(h)
+		assert.True(t, f.Has(h))
+		if i%2 == 1 {
+			assert.True(t, f.TestAndAdd(h))
+		}
+	}
+	assert.True(t, ref.Equals(f.Freeze()))
+	assert.LessOrEqual(t, int(fresh), len(hashes)/2)
+
+	assert.Equal(t, 1, NewStriped(BlockBits, 3, 8).Stripes())
+}
+
+func benchmarkHotAdd(b *testing.B, add func(uint64)) {
+	hashes := randomU64(16, 0x407)
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			add(hashes[i%len(hashes)])
+		}
+	})
+}
+
+func BenchmarkHotAddSync(b *testing.B) {
+	benchmarkHotAdd(b, NewSync(1<<20, 8).Add)
+}
+
+func BenchmarkHotAddStriped(b *testing.B) {
+	benchmarkHotAdd(b, NewStriped(1<<20, 8, 0).Add)
+}
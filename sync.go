@@ -29,9 +29,21 @@ import "sync/atomic"
 // with each method taking and releasing the lock,
 // but is implemented much more efficiently.
 // See the method descriptions for exceptions to the previous rule.
+//
+// A SyncFilter maps hash values to bits in exactly the same way as a Filter
+// with the same Layout, so a dump of one can be loaded as the other.
+//
+// Each block is aligned to its own cache line (see BlockAlignment),
+// so writers to different blocks don't contend on CPUs with 64-byte lines.
+// On CPUs that transfer lines in pairs, writes to neighboring blocks may
+// still interfere; BenchmarkAddSyncNeighbors measures this. For keys that
+// are hot enough to make the atomic operations in Add contend,
+// consider a StripedFilter.
 type SyncFilter struct {
-	b []block // Shards.
-	k int     // Number of hash functions required.
+	b      []block // Shards.
+	k      int     // Number of hash functions required.
+	premix bool    // Whether to mix hash values before use.
+	layout Layout
 }
 
 // NewSync constructs a Bloom filter with given numbers of bits and hash functions.
@@ -46,7 +58,7 @@ func NewSync(nbits uint64, nhashes int) *SyncFilter {
 	nbits, nhashes = fixBitsAndHashes(nbits, nhashes)
 
 	return &SyncFilter{
-		b: make([]block, nbits/BlockBits),
+		b: makeBlocks(nbits / BlockBits),
 		k: nhashes,
 	}
 
@@ -54,13 +66,15 @@ func NewSync(nbits uint64, nhashes int) *SyncFilter {
 
 // Add insert a key with hash value h into f.
 func (f *SyncFilter) Add(h uint64) {
-	h1, h2 := uint32(h>>32), uint32(h)
-	b := getblock(f.b, h2)
-
-	for i := 1; i < f.k; i++ {
-		h1, h2 = doublehash(h1, h2, i)
-		setbitAtomic(b, h1)
+	if f.premix {
+		h = mix64(h)
 	}
+	blk, h1, h2 := f.layout.split(h)
+	b := getblock(f.b, blk)
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		setbitAtomic(b, bit)
+		return true
+	})
 }
 
 // Cardinality estimates the number of distinct keys added to f.
@@ -78,7 +92,7 @@ func (f *SyncFilter) Add(h uint64) {
 // before the concurrent updates started and what is returned
 // after the updates complete.
 func (f *SyncFilter) Cardinality() float64 {
-	return cardinality(f.k, f.b, onescountAtomic)
+	return cardinality(f.k, f.b, kernel().onescountAtomic)
 }
 
 // Empty reports whether f contains no keys.
@@ -106,19 +120,73 @@ func (f *SyncFilter) Fill() {
 	}
 }
 
+// Freeze returns a copy of f as a Filter, which is cheaper to query.
+//
+// If other goroutines are concurrently adding keys, the copy contains
+// all keys whose Add completed before Freeze was called, and possibly
+// some of the keys added concurrently.
+func (f *SyncFilter) Freeze() *Filter {
+	b := makeBlocks(uint64(len(f.b)))
+	for i := range f.b {
+		for j := range f.b[i] {
+			b[i][j] = atomic.LoadUint32(&f.b[i][j])
+		}
+	}
+	return &Filter{b: b, k: f.k, premix: f.premix, layout: f.layout}
+}
+
 // Has reports whether a key with hash value h has been added.
 // It may return a false positive.
 func (f *SyncFilter) Has(h uint64) bool {
-	h1, h2 := uint32(h>>32), uint32(h)
-	b := getblock(f.b, h2)
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	b := getblock(f.b, blk)
+	return probe(h1, h2, f.k, func(bit uint32) bool {
+		return getbitAtomic(b, bit)
+	})
+}
 
-	for i := 1; i < f.k; i++ {
-		h1, h2 = doublehash(h1, h2, i)
-		if !getbitAtomic(b, h1) {
-			return false
-		}
+// TestAndAdd inserts a key with hash value h into f and reports whether
+// it was already present, as Has would have before the insertion.
+//
+// TestAndAdd is cheaper than Has followed by Add, and makes deduplication
+// more reliable: of several goroutines concurrently calling TestAndAdd for
+// a new key, at least one gets false, though more than one may.
+func (f *SyncFilter) TestAndAdd(h uint64) bool {
+	if f.premix {
+		h = mix64(h)
 	}
-	return true
+	blk, h1, h2 := f.layout.split(h)
+	return f.testAndAdd(getblock(f.b, blk), h1, h2)
+}
+
+func (f *SyncFilter) testAndAdd(b *block, h1, h2 uint32) bool {
+	present := true
+	probe(h1, h2, f.k, func(bit uint32) bool {
+		if !testAndSetAtomic(b, bit) {
+			present = false
+		}
+		return true
+	})
+	return present
+}
+
+// NumBits returns the number of bits of f.
+func (f *SyncFilter) NumBits() uint64 {
+	return BlockBits * uint64(len(f.b))
+}
+
+// NumBlocks returns the number of blocks of f.
+func (f *SyncFilter) NumBlocks() int { return len(f.b) }
+
+// K returns the number of hash functions of f.
+func (f *SyncFilter) K() int { return f.k }
+
+// Size returns the approximate number of bytes of memory used by f.
+func (f *SyncFilter) Size() uint64 {
+	return f.NumBits() / 8
 }
 
 // getbitAtomic reports whether bit (i modulo BlockBits) is set.
@@ -127,19 +195,3 @@ func getbitAtomic(b *block, i uint32) bool {
 	x := atomic.LoadUint32(&(*b)[(i/wordSize)%blockWords])
 	return x&bit != 0
 }
-
-// setbit sets bit (i modulo BlockBits) of b, atomically.
-func setbitAtomic(b *block, i uint32) {
-	bit := uint32(1) << (i % wordSize)
-	p := &(*b)[(i/wordSize)%blockWords]
-
-	for {
-		old := atomic.LoadUint32(p)
-		if old&bit != 0 {
-			// Checking here instead of checking the return value from
-			// the CAS is between 50% and 80% faster on the benchmark.
-			return
-		}
-		atomic.CompareAndSwapUint32(p, old, old|bit)
-	}
-}
@@ -0,0 +1,52 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+package blobloom
+
+import "sync/atomic"
+
+// These use atomic.OrUint32, which compiles to a single instruction
+// on arm64 and others, instead of a compare-and-swap loop. Each first
+// checks with a load, so that bits that are already set don't cause
+// writes to shared cache lines.
+
+// setbitAtomic sets bit (i modulo BlockBits) of b, atomically.
+func setbitAtomic(b *block, i uint32) {
+	bit := uint32(1) << (i % wordSize)
+	p := &(*b)[(i/wordSize)%blockWords]
+	if atomic.LoadUint32(p)&bit == 0 {
+		atomic.OrUint32(p, bit)
+	}
+}
+
+// testAndSetAtomic sets bit (i modulo BlockBits) of b, atomically,
+// and reports whether it was already set.
+func testAndSetAtomic(b *block, i uint32) bool {
+	bit := uint32(1) << (i % wordSize)
+	p := &(*b)[(i/wordSize)%blockWords]
+	if atomic.LoadUint32(p)&bit != 0 {
+		return true
+	}
+	return atomic.OrUint32(p, bit)&bit != 0
+}
+
+// orAtomic sets *p to *p | x, atomically.
+func orAtomic(p *uint32, x uint32) {
+	if old := atomic.LoadUint32(p); old|x != old {
+		atomic.OrUint32(p, x)
+	}
+}
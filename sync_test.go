@@ -15,6 +15,7 @@
 package blobloom
 
 import (
+	"bytes"
 	"math"
 	"math/rand"
 	"sync"
@@ -114,3 +115,119 @@ func TestSync(t *testing.T) {
 		check(f)
 	})
 }
+
+// Filter and SyncFilter must set the same bits, so that dumps of either
+// can be loaded as the other.
+func TestSyncFilterCompatible(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(1000, 0xc0c0)
+
+	for _, layout := range []Layout{LayoutV0, LayoutV1} {
+		cfg := Config{Capacity: 1000, FPRate: 1e-3, Layout: layout}
+		f := NewOptimized(cfg)
+		s := NewSyncOptimized(cfg)
+		for _, h := range hashes {
+			f.Add(h)
+			s.Add(h)
+		}
+		assert.Equal(t, f.b, s.b)
+		assert.Equal(t, f.NumBits(), s.NumBits())
+		assert.Equal(t, f.NumBlocks(), s.NumBlocks())
+		assert.Equal(t, f.K(), s.K())
+		assert.Equal(t, f.FillRatio(), s.FillRatio())
+
+		var buf bytes.Buffer
+		_, err := DumpSync(&buf, s, "")
+		require.NoError(t, err)
+		l, err := NewLoader(&buf)
+		require.NoError(t, err)
+		g, err := l.Load(nil)
+		require.NoError(t, err)
+		assert.True(t, f.Equals(g))
+
+		buf.Reset()
+		_, err = Dump(&buf, f, "")
+		require.NoError(t, err)
+		l, err = NewLoader(&buf)
+		require.NoError(t, err)
+		s2, err := l.LoadSync(nil)
+		require.NoError(t, err)
+		assert.Equal(t, s.b, s2.b)
+		assert.Equal(t, layout, s2.layout)
+	}
+}
+
+func TestTestAndAdd(t *testing.T) {
+	t.Parallel()
+
+	const nworkers = 4
+	var (
+		f      = NewSyncOptimized(Config{Capacity: 1e4, FPRate: 1e-6})
+		hashes = randomU64(1e4, 0x7a7a)
+		added  = make([]int, nworkers)
+		wg     sync.WaitGroup
+	)
+	for w := 0; w < nworkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for _, h := range hashes {
+				if !f.TestAndAdd(h) {
+					added[w]++
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// Every key was reported as new at least once.
+	total := 0
+	for _, n := range added {
+		total += n
+	}
+	assert.GreaterOrEqual(t, total, len(hashes)-1)
+	for _, h := range hashes {
+		assert.True(t, f.TestAndAdd(h))
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(2000, 0xf2ee)
+	cfg := Config{Capacity: 1000, FPRate: 1e-3, Premix: true, Layout: LayoutV1}
+	s := NewSyncOptimized(cfg)
+	for _, h := range hashes[:1000] {
+		s.Add(h)
+	}
+
+	// Keep adding while freezing.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, h := range hashes[1000:] {
+			s.Add(h)
+		}
+	}()
+	f := s.Freeze()
+	<-done
+
+	assert.Equal(t, s.K(), f.K())
+	assert.Equal(t, cfg.Layout, f.layout)
+	assert.True(t, f.premix)
+	for _, h := range hashes[:1000] {
+		assert.True(t, f.Has(h))
+	}
+
+	ref := NewOptimized(cfg)
+	for _, h := range hashes {
+		ref.Add(h)
+	}
+	g := s.Freeze()
+	assert.True(t, g.Equals(ref))
+
+	// The copy is independent of s.
+	g.Fill()
+	assert.False(t, s.Freeze().Equals(g))
+}
@@ -5,12 +5,15 @@ set -e -x
 golangci-lint run . examples/*
 
 go test
+go test -tags blobloomdebug
 
 if [ "$(go env GOARCH)" = amd64 ]; then
 	go test -tags nounsafe
 	GOARCH=386 go test
 fi
 
+(cd bloomvet && go test ./...)
+
 for e in examples/*; do
 	(cd $e && go build && rm $(basename $e))
 done
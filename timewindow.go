@@ -0,0 +1,203 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A TimeWindowFilter reports the keys added within the last window of time.
+//
+// The window is divided into slices, each of which is a Bloom filter.
+// Keys are forgotten a slice at a time, so a key is reported for at least
+// the window duration after it was last added and at most one slice
+// duration longer.
+//
+// Slices are aligned to multiples of the slice duration since the Unix
+// epoch and are rotated lazily by Add and Has, so a TimeWindowFilter needs
+// no background goroutine. Its state, including the clock, can be saved
+// with DumpTimeWindow and restored with LoadTimeWindow; slices that expired
+// in the meantime are dropped on first use.
+//
+// A TimeWindowFilter is safe for concurrent use.
+type TimeWindowFilter struct {
+	// Number of the newest slice.
+	// Accessed atomically; keep first for 64-bit alignment.
+	epoch int64
+
+	r     *RotatingFilter
+	slice int64 // Slice duration in nanoseconds.
+
+	mu    sync.Mutex // Held while rotating.
+	clock Clock
+}
+
+// NewTimeWindow constructs a TimeWindowFilter for the given window and
+// number of slices. Each slice is sized according to config.
+//
+// NewTimeWindow panics if slices < 1 or window is shorter than slices
+// nanoseconds.
+func NewTimeWindow(config Config, window time.Duration, slices int) *TimeWindowFilter {
+	if slices < 1 || int64(window) < int64(slices) {
+		panic("blobloom: invalid window or number of slices")
+	}
+	slice := (int64(window) + int64(slices) - 1) / int64(slices)
+
+	// One extra slice is filled while the others cover the window.
+	return &TimeWindowFilter{
+		r:     NewRotating(config, slices+1),
+		slice: slice,
+		clock: SystemClock,
+	}
+}
+
+// WithClock sets the Clock used by Add and Has and returns w.
+// It must be called before those.
+func (w *TimeWindowFilter) WithClock(c Clock) *TimeWindowFilter {
+	w.clock = c
+	return w
+}
+
+// Add inserts a key with hash value h at the current time.
+func (w *TimeWindowFilter) Add(h uint64) { w.AddAt(h, w.clock.Now()) }
+
+// AddAt inserts a key with hash value h at time t. Times must not go
+// backwards by more than a slice duration: keys added before the newest
+// slice are added to that slice.
+func (w *TimeWindowFilter) AddAt(h uint64, t time.Time) {
+	w.advance(t)
+	w.r.Add(h)
+}
+
+// Has reports whether a key with hash value h was added within the window
+// before the current time. It may return a false positive, and may report
+// keys that have left the window less than a slice duration ago.
+func (w *TimeWindowFilter) Has(h uint64) bool { return w.HasAt(h, w.clock.Now()) }
+
+// HasAt is like Has, but for the window before time t.
+func (w *TimeWindowFilter) HasAt(h uint64, t time.Time) bool {
+	w.advance(t)
+	return w.r.Has(h)
+}
+
+// advance rotates slices until the newest one contains t.
+func (w *TimeWindowFilter) advance(t time.Time) {
+	epoch := t.UnixNano() / w.slice
+	if epoch <= atomic.LoadInt64(&w.epoch) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := epoch - atomic.LoadInt64(&w.epoch)
+	if n > int64(w.r.Generations()) {
+		n = int64(w.r.Generations())
+	}
+	for ; n > 0; n-- {
+		w.r.Rotate()
+	}
+	if epoch > w.epoch {
+		atomic.StoreInt64(&w.epoch, epoch)
+	}
+}
+
+// DumpTimeWindow writes w to wr, with an optional comment string, in the
+// format that a Loader accepts. It returns the number of bytes written.
+//
+// The header stores the number of slices plus one in place of the number of
+// blocks and the slices' number of hash functions. It is followed by a
+// 64-byte block holding the slice duration in nanoseconds and the number of
+// the newest slice, as 64-bit integers, then by the slices, oldest first,
+// as Filter dumps.
+func DumpTimeWindow(wr io.Writer, w *TimeWindowFilter, comment string) (int64, error) {
+	if err := checkDumpComment(comment); err != nil {
+		return 0, err
+	}
+
+	// Hold w.mu to keep the clock consistent with the slices,
+	// and r.mu to block Rotate calls from other paths.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	r := w.r
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	g := r.gens[0]
+	buf := appendHeader(make([]byte, 0, 128), len(r.gens), g.k, g.premix, g.layout, comment)
+	buf[10] = byte(KindTimeWindow)
+	buf = buf[:128]
+	binary.LittleEndian.PutUint64(buf[64:], uint64(w.slice))
+	binary.LittleEndian.PutUint64(buf[72:], uint64(atomic.LoadInt64(&w.epoch)))
+
+	k, err := wr.Write(buf)
+	n := int64(k)
+	for i := 1; i <= len(r.gens) && err == nil; i++ {
+		g := r.gens[(r.cur+i)%len(r.gens)]
+		var m int64
+		m, err = DumpSync(wr, g, "")
+		n += m
+	}
+	return n, err
+}
+
+// LoadTimeWindow reads a TimeWindowFilter from the Loader.
+// It returns an error if the dump holds a different Kind of sketch.
+func (l *Loader) LoadTimeWindow() (*TimeWindowFilter, error) {
+	if err := l.checkKind(KindTimeWindow); err != nil {
+		return nil, err
+	}
+	if err := l.fillbuf(); err != nil {
+		return nil, err
+	}
+	slice := int64(binary.LittleEndian.Uint64(l.buf[:]))
+	epoch := int64(binary.LittleEndian.Uint64(l.buf[8:]))
+	if slice <= 0 || l.nblocks < 2 {
+		return nil, errorf(ErrFormat, "blobloom: invalid time window in dump")
+	}
+
+	gens := make([]*SyncFilter, 0, l.nblocks)
+	for uint64(len(gens)) < l.nblocks {
+		gl, err := NewLoader(l.r)
+		if err != nil {
+			return nil, err
+		}
+		if len(gens) > 0 {
+			g := gens[0]
+			err = gl.checkBitsAndHashes(len(g.b), g.k, g.premix, g.layout)
+		} else if gl.nhashes != l.nhashes || gl.premix != l.premix || gl.layout != l.layout {
+			err = errorf(ErrFormat, "blobloom: time window slice does not match header")
+		}
+		var g *SyncFilter
+		if err == nil {
+			g, err = gl.LoadSync(nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		gens = append(gens, g)
+	}
+
+	return &TimeWindowFilter{
+		r:     &RotatingFilter{gens: gens, cur: len(gens) - 1, clock: SystemClock},
+		slice: slice,
+		epoch: epoch,
+		clock: SystemClock,
+	}, nil
+}
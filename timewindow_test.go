@@ -0,0 +1,91 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeWindowFilter(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1000, FPRate: 1e-10}
+	w := NewTimeWindow(config, time.Minute, 4)
+	hashes := randomU64(100, 0x71e)
+
+	t0 := time.Unix(1e9, 0)
+	for i, h := range hashes {
+		w.AddAt(h, t0.Add(time.Duration(i)*time.Second))
+	}
+	last := t0.Add(99 * time.Second)
+
+	for i, h := range hashes {
+		added := t0.Add(time.Duration(i) * time.Second)
+		switch age := last.Sub(added); {
+		case age <= time.Minute:
+			assert.True(t, w.HasAt(h, last), "age %v", age)
+		case age > time.Minute+15*time.Second:
+			assert.False(t, w.HasAt(h, last), "age %v", age)
+		}
+	}
+
+	// Everything expires after a long gap.
+	assert.False(t, w.HasAt(hashes[99], last.Add(time.Hour)))
+	assert.Panics(t, func() { NewTimeWindow(config, time.Minute, 0) })
+}
+
+func TestTimeWindowDump(t *testing.T) {
+	t.Parallel()
+
+	config := Config{Capacity: 1000, FPRate: 1e-10, Premix: true}
+	w := NewTimeWindow(config, time.Minute, 3)
+	hashes := randomU64(90, 0xd1e)
+	t0 := time.Unix(2e9, 0)
+	for i, h := range hashes {
+		w.AddAt(h, t0.Add(time.Duration(i)*time.Second))
+	}
+	last := t0.Add(89 * time.Second)
+
+	var buf bytes.Buffer
+	n, err := DumpTimeWindow(&buf, w, "window")
+	require.NoError(t, err)
+	assert.EqualValues(t, buf.Len(), n)
+
+	l, err := NewLoader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, KindTimeWindow, l.Kind())
+	assert.Equal(t, "window", l.Comment)
+	u, err := l.LoadTimeWindow()
+	require.NoError(t, err)
+	assert.Zero(t, buf.Len())
+
+	for _, at := range []time.Time{last, last.Add(30 * time.Second), last.Add(2 * time.Minute)} {
+		for _, h := range hashes {
+			assert.Equal(t, w.HasAt(h, at), u.HasAt(h, at))
+		}
+	}
+
+	_, err = DumpTimeWindow(&buf, w, "")
+	require.NoError(t, err)
+	l, err = NewLoader(&buf)
+	require.NoError(t, err)
+	_, err = l.Load(nil)
+	assert.Error(t, err)
+}
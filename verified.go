@@ -0,0 +1,82 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+// A Verified wraps a Filter with a ground-truth check that is run whenever
+// the Filter reports a key as present. It records how often the Filter
+// was wrong, turning the false positive rate from an estimate into
+// a measurement.
+//
+// A Verified is not safe for concurrent use.
+type Verified struct {
+	f      *Filter
+	verify func(h uint64) bool
+
+	stats VerifiedStats
+}
+
+// VerifiedStats holds the lookup counts recorded by a Verified.
+type VerifiedStats struct {
+	Lookups        uint64 // Calls to Has.
+	Negatives      uint64 // Lookups for which the Filter returned false.
+	Positives      uint64 // Lookups confirmed by the verify function.
+	FalsePositives uint64 // Lookups rejected by the verify function.
+}
+
+// FPRate returns the measured false positive rate: the fraction of lookups
+// for absent keys that the Filter reported as present. It returns zero when
+// no lookups for absent keys have been recorded.
+func (s VerifiedStats) FPRate() float64 {
+	absent := s.Negatives + s.FalsePositives
+	if absent == 0 {
+		return 0
+	}
+	return float64(s.FalsePositives) / float64(absent)
+}
+
+// NewVerified returns a Verified that calls verify(h) when f.Has(h) is true.
+// The verify function must report whether h is truly in the set.
+func NewVerified(f *Filter, verify func(h uint64) bool) *Verified {
+	return &Verified{f: f, verify: verify}
+}
+
+// Add inserts a key with hash value h into the underlying Filter.
+func (v *Verified) Add(h uint64) { v.f.Add(h) }
+
+// Filter returns the underlying Filter.
+func (v *Verified) Filter() *Filter { return v.f }
+
+// Has reports whether a key with hash value h is in the set.
+// Unlike Filter.Has, it does not return false positives,
+// as long as the verify function is accurate.
+func (v *Verified) Has(h uint64) bool {
+	v.stats.Lookups++
+	if !v.f.Has(h) {
+		v.stats.Negatives++
+		return false
+	}
+	if !v.verify(h) {
+		v.stats.FalsePositives++
+		return false
+	}
+	v.stats.Positives++
+	return true
+}
+
+// ResetStats sets all counters to zero.
+func (v *Verified) ResetStats() { v.stats = VerifiedStats{} }
+
+// Stats returns the counters recorded so far.
+func (v *Verified) Stats() VerifiedStats { return v.stats }
@@ -0,0 +1,54 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerified(t *testing.T) {
+	t.Parallel()
+
+	const n = 2000
+	hashes := randomU64(2*n, 0x7e71f1ed)
+
+	set := make(map[uint64]bool, n)
+	f := NewOptimized(Config{Capacity: n, FPRate: .05})
+	v := NewVerified(f, func(h uint64) bool { return set[h] })
+
+	for _, h := range hashes[:n] {
+		set[h] = true
+		v.Add(h)
+	}
+	for _, h := range hashes[:n] {
+		assert.True(t, v.Has(h))
+	}
+	for _, h := range hashes[n:] {
+		assert.False(t, v.Has(h))
+	}
+
+	s := v.Stats()
+	assert.EqualValues(t, 2*n, s.Lookups)
+	assert.EqualValues(t, n, s.Positives)
+	assert.EqualValues(t, n, s.Negatives+s.FalsePositives)
+	assert.NotZero(t, s.FalsePositives)
+	assert.InDelta(t, f.FPRate(n), s.FPRate(), .03)
+
+	v.ResetStats()
+	assert.Equal(t, VerifiedStats{}, v.Stats())
+	assert.Zero(t, v.Stats().FPRate())
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+)
+
+// A VersionedFilter wraps a SyncFilter and records, for each block, the
+// version of the filter at which the block last changed. Replication and
+// caching layers can use ChangedSince to find the blocks that changed since
+// they last synchronized, without comparing the full filter.
+//
+// The version starts at zero and is incremented by every Add that sets
+// at least one new bit. Since bits are never cleared, this happens at most
+// once per bit, so the version cannot wrap around for filters smaller than
+// 2³² bits.
+//
+// A VersionedFilter is safe for concurrent use.
+type VersionedFilter struct {
+	f        *SyncFilter
+	version  uint32   // Accessed atomically.
+	versions []uint32 // Per block. Accessed atomically.
+
+	// Held for reading by Add from incrementing version until it has raised
+	// the block's version, and for writing by Version, so that Version never
+	// returns a version that some block has yet to reach.
+	publish sync.RWMutex
+}
+
+// NewVersioned returns a VersionedFilter that wraps f, with all blocks
+// at version zero. The filter f must only be modified through the
+// VersionedFilter afterwards.
+func NewVersioned(f *SyncFilter) *VersionedFilter {
+	return &VersionedFilter{f: f, versions: make([]uint32, len(f.b))}
+}
+
+// Add inserts a key with hash value h into the filter.
+func (v *VersionedFilter) Add(h uint64) {
+	f := v.f
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	i := reducerange(blk, uint64(len(f.b)))
+	if f.testAndAdd(&f.b[i], h1, h2) {
+		return
+	}
+
+	v.publish.RLock()
+	defer v.publish.RUnlock()
+
+	// Readers must never see a block version lower than one they have
+	// observed before, so only ever raise it.
+	ver := atomic.AddUint32(&v.version, 1)
+	p := &v.versions[i]
+	for {
+		old := atomic.LoadUint32(p)
+		if old >= ver || atomic.CompareAndSwapUint32(p, old, ver) {
+			return
+		}
+	}
+}
+
+// Has reports whether a key with hash value h has been added.
+// It may return a false positive.
+func (v *VersionedFilter) Has(h uint64) bool { return v.f.Has(h) }
+
+// Filter returns the underlying SyncFilter.
+func (v *VersionedFilter) Filter() *SyncFilter { return v.f }
+
+// Version returns the current version of the filter. All blocks changed
+// by Adds up to this version report so in ChangedSince, even if other Adds
+// are running concurrently.
+func (v *VersionedFilter) Version() uint32 {
+	v.publish.Lock()
+	defer v.publish.Unlock()
+	return atomic.LoadUint32(&v.version)
+}
+
+// ChangedSince appends to blocks the indices of the blocks that have changed
+// after the given version, and returns the result.
+//
+// To synchronize a copy, call Version, then ChangedSince with the version
+// of the previous synchronization, then copy the blocks with AppendBlock.
+// Blocks that change concurrently may be copied with their newer contents,
+// but will be reported again by the next call to ChangedSince.
+func (v *VersionedFilter) ChangedSince(version uint32, blocks []int) []int {
+	for i := range v.versions {
+		if atomic.LoadUint32(&v.versions[i]) > version {
+			blocks = append(blocks, i)
+		}
+	}
+	return blocks
+}
+
+// AppendBlock appends the contents of block i, in the encoding used by Dump,
+// to dst and returns the result.
+func (v *VersionedFilter) AppendBlock(dst []byte, i int) []byte {
+	b := &v.f.b[i]
+	for j := range b {
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], atomic.LoadUint32(&b[j]))
+		dst = append(dst, buf[:]...)
+	}
+	return dst
+}
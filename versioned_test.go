@@ -0,0 +1,120 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"encoding/binary"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionedFilter(t *testing.T) {
+	t.Parallel()
+
+	f := NewSync(64*BlockBits, 4)
+	v := NewVersioned(f)
+	assert.EqualValues(t, 0, v.Version())
+	assert.Empty(t, v.ChangedSince(0, nil))
+
+	hashes := randomU64(100, 0x7e5)
+	for _, h := range hashes[:10] {
+		v.Add(h)
+	}
+	v1 := v.Version()
+	assert.EqualValues(t, 10, v1)
+	changed := v.ChangedSince(0, nil)
+	assert.NotEmpty(t, changed)
+
+	// Adding existing keys doesn't change the version.
+	for _, h := range hashes[:10] {
+		v.Add(h)
+	}
+	assert.Equal(t, v1, v.Version())
+	assert.Empty(t, v.ChangedSince(v1, nil))
+
+	// Synchronize a copy block by block.
+	replica := NewSync(64*BlockBits, 4)
+	sync := func(since uint32) {
+		for _, i := range v.ChangedSince(since, nil) {
+			data := v.AppendBlock(nil, i)
+			for j := range replica.b[i] {
+				replica.b[i][j] = binary.LittleEndian.Uint32(data[4*j:])
+			}
+		}
+	}
+	sync(0)
+	for _, h := range hashes[10:] {
+		v.Add(h)
+	}
+	changed = v.ChangedSince(v1, nil)
+	assert.Less(t, len(changed), len(f.b))
+	sync(v1)
+
+	for _, h := range hashes {
+		assert.True(t, v.Has(h))
+		assert.True(t, replica.Has(h))
+	}
+	assert.Equal(t, f.b, replica.b)
+}
+
+func TestVersionedFilterConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const nwriters = 4
+	f := NewSync(16*BlockBits, 4)
+	v := NewVersioned(f)
+	replica := make([]block, len(f.b))
+
+	var prev uint32
+	synchronize := func() {
+		ver := v.Version()
+		for _, i := range v.ChangedSince(prev, nil) {
+			data := v.AppendBlock(nil, i)
+			for j := range replica[i] {
+				replica[i][j] = binary.LittleEndian.Uint32(data[4*j:])
+			}
+		}
+		prev = ver
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(nwriters)
+	for w := 0; w < nwriters; w++ {
+		go func(hashes []uint64) {
+			defer wg.Done()
+			for _, h := range hashes {
+				v.Add(h)
+			}
+		}(randomU64(2000, int64(w)))
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		synchronize()
+	}
+	synchronize()
+	assert.Equal(t, f.b, replica)
+}
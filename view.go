@@ -0,0 +1,137 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+// A FilterView is a read-only view of a contiguous range of blocks
+// of a Filter. It can answer Has correctly for those keys that map to
+// blocks in its range, which makes it a building block for distributing
+// a Filter over multiple owners.
+//
+// A FilterView shares memory with the Filter it was made from,
+// so modifications of the Filter are visible through the view.
+type FilterView struct {
+	b       []block
+	first   uint64 // Index of b[0] in the Filter.
+	nblocks uint64 // Number of blocks in the Filter.
+	k       int
+	premix  bool
+	layout  Layout
+}
+
+// Slice returns a view of nblocks blocks of f, starting at block first.
+// It panics if the range is not within f.
+func (f *Filter) Slice(first, nblocks uint64) *FilterView {
+	if first > uint64(len(f.b)) || nblocks > uint64(len(f.b))-first {
+		panic("blobloom: block range out of bounds")
+	}
+	return &FilterView{
+		b:       f.b[first : first+nblocks : first+nblocks],
+		first:   first,
+		nblocks: uint64(len(f.b)),
+		k:       f.k,
+		premix:  f.premix,
+		layout:  f.layout,
+	}
+}
+
+// FirstBlock returns the index of the first block of v in the Filter.
+func (v *FilterView) FirstBlock() uint64 { return v.first }
+
+// NumBlocks returns the number of blocks in v.
+func (v *FilterView) NumBlocks() uint64 { return uint64(len(v.b)) }
+
+// BlockOf returns the index in the Filter of the block that a key with hash
+// value h maps to. It does not depend on the range of v.
+func (v *FilterView) BlockOf(h uint64) uint64 {
+	if v.premix {
+		h = mix64(h)
+	}
+	blk, _, _ := v.layout.split(h)
+	return reducerange(blk, v.nblocks)
+}
+
+// Owns reports whether a key with hash value h maps to a block in v.
+func (v *FilterView) Owns(h uint64) bool {
+	return v.BlockOf(h)-v.first < uint64(len(v.b))
+}
+
+// Has reports whether a key with hash value h has been added to the Filter.
+// It may return a false positive.
+//
+// If the key does not map to a block in v, Has cannot rule it out
+// and returns true.
+func (v *FilterView) Has(h uint64) bool {
+	if v.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := v.layout.split(h)
+	i := reducerange(blk, v.nblocks) - v.first
+	if i >= uint64(len(v.b)) {
+		return true
+	}
+	b := &v.b[i]
+	return probe(h1, h2, v.k, b.getbit)
+}
+
+// add inserts a key with hash value h through v.
+// The key must map to a block in v.
+func (v *FilterView) add(h uint64) {
+	if v.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := v.layout.split(h)
+	b := &v.b[reducerange(blk, v.nblocks)-v.first]
+	probe(h1, h2, v.k, func(bit uint32) bool {
+		b.setbit(bit)
+		return true
+	})
+}
+
+// A ProbeView answers Has for a Filter using fewer hash functions than
+// the Filter was built with, trading a higher false positive rate for
+// faster lookups. Different call sites can use different ProbeViews of
+// the same Filter.
+type ProbeView struct {
+	f *Filter
+	k int
+}
+
+// WithK returns a ProbeView of f that probes the bits for k hash functions.
+// Since these are a subset of the bits for f's own number of hash functions,
+// the view has no false negatives. Its false positive rate is f's rate
+// for k hash functions; see FPRate.
+//
+// WithK panics if k < 2 or k is greater than f's number of hash functions.
+func (f *Filter) WithK(k int) ProbeView {
+	if k < 2 || k > f.k {
+		panic("blobloom: invalid number of hashes for WithK")
+	}
+	return ProbeView{f: f, k: k}
+}
+
+// Has reports whether a key with hash value h has been added to the Filter.
+// It may return a false positive.
+func (v ProbeView) Has(h uint64) bool {
+	f := v.f
+	if f.premix {
+		h = mix64(h)
+	}
+	blk, h1, h2 := f.layout.split(h)
+	b := getblock(f.b, blk)
+	return probe(h1, h2, v.k, b.getbit)
+}
+
+// K returns the number of hash functions that v probes.
+func (v ProbeView) K() int { return v.k }
@@ -0,0 +1,94 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterView(t *testing.T) {
+	t.Parallel()
+
+	f := NewOptimized(Config{Capacity: 1e4, FPRate: 1e-3, Premix: true})
+	hashes := randomU64(2e4, 0x5ce)
+	for _, h := range hashes[:1e4] {
+		f.Add(h)
+	}
+
+	n := uint64(len(f.b))
+	views := []*FilterView{
+		f.Slice(0, n/3),
+		f.Slice(n/3, n-n/3-1),
+		f.Slice(n-1, 1),
+		f.Slice(n, 0),
+	}
+
+	for _, h := range hashes {
+		owners := 0
+		for _, v := range views {
+			if !v.Owns(h) {
+				assert.True(t, v.Has(h))
+				continue
+			}
+			owners++
+			assert.Equal(t, f.Has(h), v.Has(h))
+			assert.Equal(t, f.Explain(h).Block, v.BlockOf(h))
+		}
+		assert.Equal(t, 1, owners)
+	}
+
+	assert.Equal(t, n/3, views[1].FirstBlock())
+	assert.Equal(t, n-n/3-1, views[1].NumBlocks())
+
+	assert.Panics(t, func() { f.Slice(n+1, 0) })
+	assert.Panics(t, func() { f.Slice(1, n) })
+	assert.Panics(t, func() { f.Slice(2, ^uint64(0)) })
+}
+
+func TestProbeView(t *testing.T) {
+	t.Parallel()
+
+	hashes := randomU64(20000, 0x9b0)
+	keys, others := hashes[:1000], hashes[1000:]
+
+	f := NewOptimized(Config{Capacity: 1000, FPRate: 1e-4, Premix: true})
+	for _, h := range keys {
+		f.Add(h)
+	}
+
+	full, low := f.WithK(f.k), f.WithK(2)
+	assert.Equal(t, 2, low.K())
+	nfull, nlow := 0, 0
+	for _, h := range keys {
+		assert.True(t, full.Has(h))
+		assert.True(t, low.Has(h))
+	}
+	for _, h := range others {
+		assert.Equal(t, f.Has(h), full.Has(h))
+		if full.Has(h) {
+			nfull++
+			assert.True(t, low.Has(h))
+		}
+		if low.Has(h) {
+			nlow++
+		}
+	}
+	assert.Greater(t, nlow, nfull)
+
+	assert.Panics(t, func() { f.WithK(1) })
+	assert.Panics(t, func() { f.WithK(f.k + 1) })
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import "sync/atomic"
+
+// A WindowFilter is a sliding-window Bloom filter: it reports the keys
+// among the last W insertions, where W is its window size.
+//
+// The window is divided into segments, each of which is a Bloom filter.
+// Keys are forgotten a segment at a time, so keys among the last W
+// insertions are always reported, but keys up to W/segments insertions
+// older may be reported as well.
+//
+// For windows measured in time rather than insertions,
+// use a TimeWindowFilter.
+//
+// A WindowFilter is safe for concurrent use.
+type WindowFilter struct {
+	// Number of insertions. Accessed atomically; keep first for 64-bit alignment.
+	n uint64
+
+	r   *RotatingFilter
+	per uint64 // Insertions per segment.
+}
+
+// NewWindowFilter constructs a WindowFilter for the given window size and
+// number of segments. Each segment is sized for window/segments keys at
+// config.FPRate; config.Capacity is ignored.
+//
+// NewWindowFilter panics if segments < 1 or window < segments.
+func NewWindowFilter(config Config, window uint64, segments int) *WindowFilter {
+	if segments < 1 || window < uint64(segments) {
+		panic("blobloom: invalid window size or number of segments")
+	}
+	per := (window + uint64(segments) - 1) / uint64(segments)
+	config.Capacity = per
+
+	// One extra segment is filled while the others cover the window.
+	return &WindowFilter{r: NewRotating(config, segments+1), per: per}
+}
+
+// Add inserts a key with hash value h, which is then reported by Has
+// for at least the next W-1 insertions.
+func (w *WindowFilter) Add(h uint64) {
+	w.r.Add(h)
+	if atomic.AddUint64(&w.n, 1)%w.per == 0 {
+		w.r.Rotate()
+	}
+}
+
+// Has reports whether a key with hash value h was among the last W
+// insertions. It may return a false positive, and may report keys that
+// have recently left the window.
+func (w *WindowFilter) Has(h uint64) bool { return w.r.Has(h) }
@@ -0,0 +1,52 @@
+// Copyright 2026 the Blobloom authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobloom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowFilter(t *testing.T) {
+	t.Parallel()
+
+	const (
+		window   = 1000
+		segments = 4
+		slack    = window / segments
+	)
+	w := NewWindowFilter(Config{FPRate: 1e-10}, window, segments)
+
+	hashes := randomU64(5000, 0xd0)
+	for i, h := range hashes {
+		w.Add(h)
+
+		if i%97 != 0 {
+			continue
+		}
+		for j := 0; j <= i; j++ {
+			switch age := i - j; {
+			case age < window:
+				assert.True(t, w.Has(hashes[j]), "age %d", age)
+			case age >= window+slack:
+				assert.False(t, w.Has(hashes[j]), "age %d", age)
+			}
+		}
+	}
+
+	assert.Panics(t, func() { NewWindowFilter(Config{FPRate: .01}, 3, 4) })
+	assert.Panics(t, func() { NewWindowFilter(Config{FPRate: .01}, 3, 0) })
+}